@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"go-scanner/cmd/go-scanner-web/app/views"
 	"go-scanner/internal/app/scan"
+	"go-scanner/internal/scanner/service"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -20,12 +22,14 @@ type PageData struct {
 // handler agrupa las dependencias del handler de escaneo
 type Handler struct {
 	renderer *views.Renderer
+	vulnFeed string //feed local de CVEs ("" = sin match de vulnerabilidades)
 }
 
 // nueva instancia del handler
-func NewHandler(renderer *views.Renderer) *Handler {
+func NewHandler(renderer *views.Renderer, vulnFeed string) *Handler {
 	return &Handler{
 		renderer: renderer,
+		vulnFeed: vulnFeed,
 	}
 }
 
@@ -51,15 +55,24 @@ func (h *Handler) Scan(w http.ResponseWriter, r *http.Request) {
 		Target: rawTarget,
 	}
 
+	// "syn+udp" -> campaña combinada
+	scanType := r.FormValue("scan_type")
+	var scanTypes []string
+	if strings.Contains(scanType, "+") {
+		scanTypes = strings.Split(scanType, "+")
+	}
+
 	// configuracion del escaneo asumiendo input crudo
 	req := scan.ScanRequest{
 		Targets:     []string{r.FormValue("target")},
 		Ports:       r.FormValue("ports"),
 		ProfileName: r.FormValue("profile"),
 		Options: scan.ScanOptions{
-			ScanType: r.FormValue("scan_type"),
-			Banner:   r.FormValue("banner") == "true",
-			Probe:    r.FormValue("probe") == "true",
+			ScanType:  scanType,
+			ScanTypes: scanTypes,
+			Banner:    r.FormValue("banner") == "true",
+			Probe:     r.FormValue("probe") == "true",
+			VulnFeed:  h.vulnFeed,
 			// ProbeTypes -> empty; para usar defaults del profile/cli logic
 		},
 	}
@@ -78,7 +91,13 @@ func (h *Handler) Scan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// transparencia (reporte)
+	// transparencia (reporte), lo mas severo primero en cada puerto
+	for _, res := range report.Results {
+		if res.ServiceInfo != nil {
+			service.SortFindings(res.ServiceInfo.Findings)
+			service.SortVulnerabilities(res.ServiceInfo.Vulns)
+		}
+	}
 	data.Report = report
 
 	// renderizado
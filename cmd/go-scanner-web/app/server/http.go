@@ -7,8 +7,9 @@ import (
 )
 
 // inicializar y correr el servidor HTTP
-func Start(port int, templateDir string) error {
-	mux, err := SetupRoutes(templateDir)
+// vulnFeed: feed local de CVEs para todos los escaneos ("" = sin match de vulnerabilidades)
+func Start(port int, templateDir, vulnFeed string) error {
+	mux, err := SetupRoutes(templateDir, vulnFeed)
 	if err != nil {
 		return fmt.Errorf("failed to setup routes: %w", err)
 	}
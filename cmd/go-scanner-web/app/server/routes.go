@@ -7,14 +7,14 @@ import (
 )
 
 // configura las rutas de la aplicacion (que no son muchas por ahora)
-func SetupRoutes(templateDir string) (*http.ServeMux, error) {
+func SetupRoutes(templateDir, vulnFeed string) (*http.ServeMux, error) {
 	// inicializar dependencias
 	renderer, err := views.NewRenderer(templateDir)
 	if err != nil {
 		return nil, err
 	}
 
-	h := handlers.NewHandler(renderer)
+	h := handlers.NewHandler(renderer, vulnFeed)
 
 	mux := http.NewServeMux()
 
@@ -3,11 +3,14 @@ function filterResults() {
     const showFiltered = document.getElementById('showFiltered').checked;
     const showClosed = document.getElementById('showClosed').checked;
     const minConfidence = document.getElementById('minConfidence').value;
+    const protocol = document.getElementById('protocol').value;
+    const minSeverity = parseInt(document.getElementById('minSeverity').value, 10);
     const rows = document.getElementsByClassName('result-row');
 
     for (let row of rows) {
         const status = row.getAttribute('data-status');
         const confidence = row.getAttribute('data-confidence');
+        const rowProtocol = row.getAttribute('data-protocol');
         let visible = true;
 
         // filtro en base del estado
@@ -15,6 +18,9 @@ function filterResults() {
         if (status === 'FILTERED' && !showFiltered) visible = false;
         if (status === 'CLOSED' && !showClosed) visible = false;
 
+        //filtro en base al protocolo
+        if (visible && protocol !== 'all' && rowProtocol !== protocol) visible = false;
+
         //filtro en base a confidence
         if (visible && minConfidence !== 'all') {
             //high > medium > low/unknown
@@ -22,12 +28,41 @@ function filterResults() {
             if (minConfidence === 'medium' && (confidence === 'low' || confidence === 'unknown')) visible = false;
         }
 
+        //filtro en base a severidad (1 info .. 5 critical), oculta tambien los hallazgos por debajo
+        if (visible && minSeverity > 0 && parseInt(row.getAttribute('data-severity'), 10) < minSeverity) visible = false;
+        for (let item of row.getElementsByClassName('finding')) {
+            item.style.display = parseInt(item.getAttribute('data-severity'), 10) >= minSeverity ? '' : 'none';
+        }
+
         row.style.display = visible ? '' : 'none';
     }
 }
 
+//ordena por severidad y CVSS (mayor primero) o vuelve al orden original
+function sortResults() {
+    const sortBy = document.getElementById('sortBy').value;
+    const tbody = document.querySelector('#resultsTable tbody');
+    if (!tbody) return;
+    const rows = Array.from(tbody.getElementsByClassName('result-row'));
+
+    rows.sort(function (a, b) {
+        if (sortBy === 'severity') {
+            const sev = parseInt(b.getAttribute('data-severity'), 10) - parseInt(a.getAttribute('data-severity'), 10);
+            if (sev !== 0) return sev;
+            const cvss = parseFloat(b.getAttribute('data-cvss')) - parseFloat(a.getAttribute('data-cvss'));
+            if (cvss !== 0) return cvss;
+        }
+        return parseInt(a.getAttribute('data-index'), 10) - parseInt(b.getAttribute('data-index'), 10);
+    });
+    for (let row of rows) tbody.appendChild(row);
+}
+
 //listeners cuando DOM esta listo
 document.addEventListener('DOMContentLoaded', function () {
+    //orden original para volver a el despues de ordenar
+    const rows = document.getElementsByClassName('result-row');
+    for (let i = 0; i < rows.length; i++) rows[i].setAttribute('data-index', i);
+
     const showClosed = document.getElementById('showClosed');
     if (showClosed) {
         filterResults();
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"flag"
 	"go-scanner/cmd/go-scanner-web/app/server"
 	"log"
 )
 
 func main() {
+	vulnFeed := flag.String("vuln-feed", "", "Local JSON feed of CVEs by CPE version range, matched against identified services")
+	flag.Parse()
+
 	port := 8080
 	templateDir := "cmd/go-scanner-web/app/views/templates"
 
 	// iniciar servidor
-	if err := server.Start(port, templateDir); err != nil {
+	if err := server.Start(port, templateDir, *vulnFeed); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
@@ -2,7 +2,12 @@ module go-scanner
 
 go 1.25.0
 
-require golang.org/x/net v0.48.0
+require (
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+	golang.org/x/crypto v0.46.0
+	golang.org/x/net v0.48.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/google/uuid v1.6.0
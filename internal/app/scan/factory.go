@@ -5,18 +5,49 @@ import (
 	"go-scanner/internal/model"
 	"go-scanner/internal/orchestrator"
 	"go-scanner/internal/scanner"
+	"go-scanner/internal/scanner/banner"
 	"go-scanner/internal/scanner/tcp"
 	"go-scanner/internal/scanner/udp"
+	"go-scanner/internal/utils"
 	"os"
 	"runtime"
 )
 
 // factory para crear scanner
-func NewScanner(target string, ports []int, policy orchestrator.ScanPolicy, meta *model.HostMetadata) (scanner.Scanner, error) {
-	switch policy.Type {
+// con varios tipos de escaneo se combinan en un MultiScanner, cada uno con los puertos de su protocolo
+// grabber (opcional) captura banners en cualquier puerto abierto, compartido por toda la campaña
+func NewScanner(target string, spec utils.PortSpec, policy orchestrator.ScanPolicy, meta *model.HostMetadata, grabber *banner.Grabber) (scanner.Scanner, error) {
+	var scanners []scanner.Scanner
+
+	for _, t := range policy.ScanTypes() {
+		ports := spec.For(t.Protocol())
+		if len(ports) == 0 {
+			continue
+		}
+
+		s, err := newTypedScanner(t, target, ports, policy, meta, grabber)
+		if err != nil {
+			return nil, err
+		}
+		scanners = append(scanners, s)
+	}
+
+	switch len(scanners) {
+	case 0:
+		return nil, fmt.Errorf("no ports to scan for %s", target)
+	case 1:
+		return scanners[0], nil
+	default:
+		return scanner.NewMultiScanner(scanners...), nil
+	}
+}
+
+// crea el scanner de un tipo concreto
+func newTypedScanner(scanType orchestrator.ScanType, target string, ports []int, policy orchestrator.ScanPolicy, meta *model.HostMetadata, grabber *banner.Grabber) (scanner.Scanner, error) {
+	switch scanType {
 	case orchestrator.ScanTypeConnect:
 		//TCP connect estandar
-		return tcp.NewTCPConnectScanner(
+		s := tcp.NewTCPConnectScanner(
 			target,
 			ports,
 			policy.Timeout,
@@ -24,7 +55,11 @@ func NewScanner(target string, ports []int, policy orchestrator.ScanPolicy, meta
 			//ServiceDetection para activar el Banner Grabbing
 			policy.ServiceDetection,
 			meta,
-		), nil
+			policy.Order,
+		)
+		s.Grabber = grabber //null probe en cualquier puerto (reemplaza la lista de puertos conocidos)
+		s.Dialer = policy.Dialer
+		return s, nil
 
 	case orchestrator.ScanTypeSYN:
 		//verificar privilegios antes de crear el scanner
@@ -38,19 +73,23 @@ func NewScanner(target string, ports []int, policy orchestrator.ScanPolicy, meta
 			policy.Timeout,
 			policy.Concurrency,
 			meta,
+			policy.Order,
 		), nil
 
 	case orchestrator.ScanTypeUDP:
-		return udp.NewUDPScanner(
+		s := udp.NewUDPScanner(
 			target,
 			ports,
 			policy.Timeout,
 			policy.Concurrency,
 			meta,
-		), nil
+			policy.Order,
+		)
+		s.Dialer = policy.Dialer
+		return s, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported scan type: %s", policy.Type)
+		return nil, fmt.Errorf("unsupported scan type: %s", scanType)
 	}
 }
 
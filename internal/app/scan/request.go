@@ -3,17 +3,39 @@ package scan
 // define los parametros de entrada para un escaneo
 type ScanRequest struct {
 	Targets     []string    //lista de targets
-	Ports       string      //puertos a escanear
+	Ports       string      //puertos a escanear (numeros, rangos, nombres, T:/U:)
+	TopPorts    int         //escanear los N puertos mas frecuentes
 	ProfileName string      //perfil de configuracion
 	Options     ScanOptions //opciones de tuning
 }
 
 // define las opciones de tuning fino
 type ScanOptions struct {
-	TimeoutMs   int  //timeout en ms
-	Concurrency int  //nivel de concurrencia
-	Banner      bool //habilita la captura de banners explícitamente
-	Probe       bool //habilita el probing activo
-	ProbeTypes  []string
-	ScanType    string //tipo de escaneo
+	TimeoutMs        int  //timeout en ms
+	Concurrency      int  //nivel de concurrencia
+	ParallelHosts    int  //hosts escaneados a la vez
+	Banner           bool //habilita la captura de banners explícitamente
+	Probe            bool //habilita el probing activo
+	ProbeTypes       []string
+	ScanType         string   //tipo de escaneo
+	ScanTypes        []string //tipos combinados (ej. SYN + UDP), reemplaza a ScanType
+	VersionDetection bool     //deteccion de version con la base de probes
+	VersionIntensity int      //rareza maxima de los probes de version (1-9)
+	ServiceProbes    string   //archivo de probes del usuario
+	TLSFingerprints  string   //archivo de hashes JARM/JA3S conocidos
+	VHosts           []string //virtual hosts para los probes HTTP
+	DNSZones         []string //zonas para probar AXFR en servidores DNS
+	SNMPCommunities  []string //comunidades SNMP a probar
+	Templates        string   //directorio de templates YAML
+	Scripts          string   //directorio de scripts Starlark
+	VulnFeed         string   //feed JSON local de CVEs por CPE
+	SourceIP         string   //IP local de origen de las conexiones (scanners y probers)
+	TLSCert          string   //certificado de cliente (PEM) para los handshakes de los probers
+	TLSKey           string   //clave privada (PEM) del certificado de cliente
+	NullProbe        bool     //banner grabbing en cualquier puerto abierto
+	GenericProbe     bool     //probes genericos cuando el servicio no habla primero
+	BannerWaitMs     int      //espera maxima por banner en ms
+	BannerBudgetMs   int      //tiempo total de banner grabbing en ms
+	NoRandomize      bool     //recorre puertos y hosts en orden secuencial
+	Seed             int64    //semilla de la permutacion (0 = aleatoria)
 }
@@ -20,4 +20,6 @@ type ExecutionMetadata struct {
 	TargetCount int           //cantidad de targets
 	ProfileUsed string        //perfil utilizado
 	ScanType    string        //tipo de escaneo (SYN, CONNECT)
+	Randomized  bool          //si el orden de puertos/hosts fue permutado
+	Seed        int64         //semilla usada, permite reproducir el orden
 }
@@ -2,6 +2,7 @@ package scan
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,6 +12,15 @@ import (
 	"go-scanner/internal/model"
 	"go-scanner/internal/orchestrator"
 	"go-scanner/internal/scanner"
+	"go-scanner/internal/scanner/banner"
+	"go-scanner/internal/scanner/portdb"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/builtin"
+	"go-scanner/internal/scanner/probe/jarm"
+	"go-scanner/internal/scanner/probe/script"
+	"go-scanner/internal/scanner/probe/template"
+	"go-scanner/internal/scanner/version"
+	"go-scanner/internal/scanner/vuln"
 	"go-scanner/internal/utils"
 
 	"github.com/google/uuid"
@@ -55,23 +65,64 @@ func (s *service) Run(ctx context.Context, req ScanRequest) (*ScanReport, error)
 	policy := selectedProfile.Policy
 	s.applyOptions(&policy, req.Options)
 
-	// parsear puertos
-	portStr := req.Ports
-	if portStr == "" {
-		portStr = "80,443,22,21,25,8080,8443,3306,5432,3389" //puertos predefinidos
+	// dialer y TLS de la campaña, compartidos por scanners y probers
+	if err := applyConnection(&policy, req.Options); err != nil {
+		return nil, err
+	}
+
+	// fijar la semilla una sola vez, todos los scanners comparten la misma permutacion
+	policy.Order = utils.NewPermutation(policy.Order.Enabled, policy.Order.Seed)
+
+	// validar la base de probes antes de empezar (evita fallar silenciosamente por host)
+	// tambien se usa para identificar banners, aunque no haya deteccion de version
+	if _, err := version.Load(policy.ServiceProbes); err != nil {
+		return nil, fmt.Errorf("invalid service probes: %w", err)
+	}
+
+	// lista de fingerprints TLS conocidos
+	if _, err := jarm.LoadKnown(policy.TLSFingerprints); err != nil {
+		return nil, fmt.Errorf("invalid tls fingerprints: %w", err)
+	}
+
+	// feed de vulnerabilidades (opcional)
+	if policy.VulnFeed != "" {
+		if _, err := vuln.Load(policy.VulnFeed); err != nil {
+			return nil, fmt.Errorf("invalid vulnerability feed: %w", err)
+		}
+	}
+
+	// probers de la campaña: los incluidos mas los templates y scripts del usuario
+	probers, err := loadProbers(policy)
+	if err != nil {
+		return nil, err
 	}
-	ports, err := utils.ParsePortRange(portStr)
+
+	// parsear puertos
+	ports, err := resolvePorts(req, policy.ScanTypes())
 	if err != nil {
 		return nil, fmt.Errorf("invalid ports: %w", err)
 	}
 
+	// los prefijos T:/U: suman el scanner del otro protocolo a la campaña
+	policy.Types = withPortProtocols(policy.ScanTypes(), ports)
+	if err := validateScanTypes(policy.Types, ports); err != nil {
+		return nil, err
+	}
+
 	// normalizar targets
+	//se recuerda el hostname original de cada IP (Host/SNI de los probes)
 	var finalTargets []string
+	names := make(map[string]string)
 	for _, t := range req.Targets {
 		expanded, err := utils.ParseTarget(t)
 		if err != nil {
 			return nil, fmt.Errorf("invalid target '%s': %w", t, err)
 		}
+		if utils.IsHostname(t) {
+			for _, ip := range expanded {
+				names[ip] = t
+			}
+		}
 		finalTargets = append(finalTargets, expanded...)
 	}
 
@@ -79,11 +130,24 @@ func (s *service) Run(ctx context.Context, req ScanRequest) (*ScanReport, error)
 		return nil, errors.New("no valid targets found after parsing")
 	}
 
+	// un solo grabber para toda la campaña, asi el presupuesto de tiempo es global
+	var grabber *banner.Grabber
+	if policy.NullProbe {
+		grabber = banner.NewGrabber(policy.BannerWait, policy.BannerBudget, policy.GenericProbe)
+		grabber.Dialer = policy.Dialer
+	}
+
+	// el limite de conexiones es de la campaña, cada host recibe su parte
+	hostPolicy := policy
+	hostPolicy.Concurrency = policy.HostConcurrency(len(finalTargets))
+
 	scannerFactory := func(t string, meta *model.HostMetadata) (scanner.Scanner, error) {
-		return NewScanner(t, ports, policy, meta) //t -> target
+		return NewScanner(t, ports, hostPolicy, meta, grabber) //t -> target
 	}
 
 	coord := orchestrator.NewCoordinator(policy, scannerFactory)
+	coord.Names = names
+	coord.Probers = probers
 	resultsChan, errChan := coord.Run(ctx, finalTargets)
 
 	// estado inicial
@@ -141,11 +205,147 @@ func (s *service) Run(ctx context.Context, req ScanRequest) (*ScanReport, error)
 			Duration:    elapsed,
 			TargetCount: len(finalTargets),
 			ProfileUsed: selectedProfile.Name,
-			ScanType:    string(policy.Type),
+			ScanType:    joinScanTypes(policy.ScanTypes()),
+			Randomized:  policy.Order.Enabled,
+			Seed:        policy.Order.Seed,
 		},
 	}, nil
 }
 
+// cantidad de puertos frecuentes escaneados cuando el request no especifica ninguno
+const defaultTopPorts = 100
+
+// resuelve los puertos del request: lista explicita, top-N o los mas frecuentes por defecto
+// los puertos sin prefijo pertenecen al protocolo del primer tipo de escaneo
+func resolvePorts(req ScanRequest, types []orchestrator.ScanType) (utils.PortSpec, error) {
+	if req.Ports != "" && req.TopPorts > 0 {
+		return utils.PortSpec{}, errors.New("port list and top ports cannot be combined")
+	}
+
+	if req.Ports != "" {
+		return utils.ParsePortSpec(req.Ports, types[0].Protocol())
+	}
+
+	n := req.TopPorts
+	if n <= 0 {
+		n = defaultTopPorts
+	}
+
+	var spec utils.PortSpec
+	for _, t := range types {
+		proto := t.Protocol()
+		//un top-N pedido que la tabla no cubre es un error, no se recorta en silencio
+		if req.TopPorts > portdb.Ranked(proto) {
+			return utils.PortSpec{}, fmt.Errorf("top %d ports requested but the service table only ranks %d %s ports", req.TopPorts, portdb.Ranked(proto), proto)
+		}
+		switch proto {
+		case portdb.UDP:
+			spec.UDP = portdb.Top(n, portdb.UDP)
+		default:
+			spec.TCP = portdb.Top(n, portdb.TCP)
+		}
+	}
+	return spec, nil
+}
+
+// agrega un tipo de escaneo por cada protocolo con puertos que no tenga uno asignado
+func withPortProtocols(types []orchestrator.ScanType, spec utils.PortSpec) []orchestrator.ScanType {
+	hasTCP, hasUDP := false, false
+	for _, t := range types {
+		if t.Protocol() == portdb.UDP {
+			hasUDP = true
+		} else {
+			hasTCP = true
+		}
+	}
+
+	out := append([]orchestrator.ScanType(nil), types...)
+	if len(spec.TCP) > 0 && !hasTCP {
+		out = append(out, orchestrator.ScanTypeConnect)
+	}
+	if len(spec.UDP) > 0 && !hasUDP {
+		out = append(out, orchestrator.ScanTypeUDP)
+	}
+	return out
+}
+
+// un solo tipo por protocolo y cada tipo con al menos un puerto
+func validateScanTypes(types []orchestrator.ScanType, spec utils.PortSpec) error {
+	seen := make(map[string]orchestrator.ScanType)
+	for _, t := range types {
+		proto := t.Protocol()
+		if prev, dup := seen[proto]; dup {
+			return fmt.Errorf("scan types %s and %s both cover %s, choose one", prev, t, proto)
+		}
+		seen[proto] = t
+
+		if len(spec.For(proto)) == 0 {
+			return fmt.Errorf("invalid ports: no %s ports to scan for %s scan", proto, t)
+		}
+	}
+	return nil
+}
+
+// catalogo incluido mas los templates y scripts de los directorios (si se indicaron)
+func loadProbers(policy orchestrator.ScanPolicy) (*probe.Registry, error) {
+	probers := builtin.Default()
+	if policy.Templates != "" {
+		templates, err := template.LoadDir(policy.Templates)
+		if err != nil {
+			return nil, fmt.Errorf("invalid templates: %w", err)
+		}
+		if err := template.Register(probers, templates); err != nil {
+			return nil, fmt.Errorf("invalid templates: %w", err)
+		}
+	}
+	if policy.Scripts != "" {
+		scripts, err := script.LoadDir(policy.Scripts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scripts: %w", err)
+		}
+		if err := script.Register(probers, scripts); err != nil {
+			return nil, fmt.Errorf("invalid scripts: %w", err)
+		}
+	}
+	return probers, nil
+}
+
+// IP de origen y certificado de cliente del request (sin ellos: conexion directa y TLS sin certificado)
+func applyConnection(p *orchestrator.ScanPolicy, opts ScanOptions) error {
+	if opts.SourceIP != "" {
+		dialer, err := utils.NewSourceDialer(opts.SourceIP)
+		if err != nil {
+			return fmt.Errorf("invalid source ip: %w", err)
+		}
+		p.Dialer = dialer
+	}
+
+	if opts.TLSCert != "" || opts.TLSKey != "" {
+		if opts.TLSCert == "" || opts.TLSKey == "" {
+			return errors.New("invalid tls client certificate: both certificate and key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.TLSCert, opts.TLSKey)
+		if err != nil {
+			return fmt.Errorf("invalid tls client certificate: %w", err)
+		}
+		//los probers inspeccionan el certificado del servidor, no lo validan
+		p.TLS = &tls.Config{
+			InsecureSkipVerify: true,
+			Certificates:       []tls.Certificate{cert},
+		}
+	}
+	return nil
+}
+
+// representacion de los tipos combinados (ej. "SYN+UDP")
+func joinScanTypes(types []orchestrator.ScanType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, "+")
+}
+
 // aplicar sobreescrituras a la politica base.
 func (s *service) applyOptions(p *orchestrator.ScanPolicy, opts ScanOptions) {
 	if opts.TimeoutMs > 0 {
@@ -154,6 +354,9 @@ func (s *service) applyOptions(p *orchestrator.ScanPolicy, opts ScanOptions) {
 	if opts.Concurrency > 0 {
 		p.Concurrency = opts.Concurrency
 	}
+	if opts.ParallelHosts > 0 {
+		p.ParallelHosts = opts.ParallelHosts
+	}
 	// aplicar configuracion de probes activos
 	if opts.Probe {
 		p.ActiveProbing = true
@@ -161,12 +364,81 @@ func (s *service) applyOptions(p *orchestrator.ScanPolicy, opts ScanOptions) {
 			p.AllowedProbes = opts.ProbeTypes
 		}
 		if len(p.AllowedProbes) == 0 {
-			p.AllowedProbes = []string{"http", "https"}
+			p.AllowedProbes = []string{"http", "https", "tls"}
 		}
 	}
 
+	// deteccion de version
+	if opts.VersionDetection {
+		p.VersionDetection = true
+	}
+	if opts.VersionIntensity > 0 {
+		p.VersionIntensity = opts.VersionIntensity
+	}
+	if opts.ServiceProbes != "" {
+		p.ServiceProbes = opts.ServiceProbes
+	}
+	if opts.TLSFingerprints != "" {
+		p.TLSFingerprints = opts.TLSFingerprints
+	}
+	if len(opts.VHosts) > 0 {
+		p.VHosts = opts.VHosts
+	}
+	if len(opts.DNSZones) > 0 {
+		p.DNSZones = opts.DNSZones
+	}
+	if len(opts.SNMPCommunities) > 0 {
+		p.SNMPCommunities = opts.SNMPCommunities
+	}
+	if opts.Templates != "" {
+		p.Templates = opts.Templates
+	}
+	if opts.Scripts != "" {
+		p.Scripts = opts.Scripts
+	}
+	if opts.VulnFeed != "" {
+		p.VulnFeed = opts.VulnFeed
+	}
+
+	// banner grabbing en cualquier puerto
+	if opts.NullProbe {
+		p.NullProbe = true
+	}
+	if opts.GenericProbe {
+		p.NullProbe = true
+		p.GenericProbe = true
+	}
+	if opts.BannerWaitMs > 0 {
+		p.BannerWait = time.Duration(opts.BannerWaitMs) * time.Millisecond
+	}
+	if opts.BannerBudgetMs > 0 {
+		p.BannerBudget = time.Duration(opts.BannerBudgetMs) * time.Millisecond
+	}
+
+	// orden de recorrido
+	if opts.NoRandomize {
+		p.Order.Enabled = false
+	}
+	if opts.Seed != 0 {
+		p.Order.Seed = opts.Seed
+	}
+
 	// override del tipo de escaneo si se especifica
 	if opts.ScanType != "" {
 		p.Type = orchestrator.ScanType(strings.ToUpper(opts.ScanType))
+		p.Types = nil
+	}
+
+	// campaña combinada, el primer tipo pasa a ser el principal
+	if len(opts.ScanTypes) > 0 {
+		p.Types = nil
+		for _, t := range opts.ScanTypes {
+			if t = strings.TrimSpace(t); t != "" {
+				p.Types = append(p.Types, orchestrator.ScanType(strings.ToUpper(t)))
+			}
+		}
+		if len(p.Types) > 0 {
+			p.Type = p.Types[0]
+		}
 	}
 }
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+)
+
+// el paquete flag no entiende "-p-" (todos los puertos), se reescribe como "-p=-"
+func normalizePortArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "-p-" || a == "--p-" {
+			a = "-p=-"
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// true si el flag fue indicado explicitamente en la linea de comandos
+func flagWasSet(cmd *flag.FlagSet, name string) bool {
+	set := false
+	cmd.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// lista separada por comas, sin espacios ni elementos vacios (respeta mayusculas)
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"go-scanner/internal/app/scan"
 	"go-scanner/internal/report"
+	"go-scanner/internal/scanner/service"
 	"go-scanner/internal/utils"
 	"os"
 	"strings"
@@ -45,17 +46,47 @@ func handleTCPGeneric(args []string, scanType string) {
 
 	//flags comunes
 	profileName := cmd.String("profile", "default", "Scan profile: passive, default, aggressive")
-	portRange := cmd.String("p", "1-1024", "Ports to scan (e.g: '80', '1-1024', '80,443')")
+	portRange := cmd.String("p", "1-1024", "Ports to scan (e.g: '80', '1-1024', '80,443', 'http,ssh', 'T:80,U:53', '-' for all)")
+	topPorts := cmd.Int("top-ports", 0, "Scan the N most frequent ports")
 	timeoutMs := cmd.Int("timeout", -1, "Timeout per connection in ms (default: from profile)")
-	concurrency := cmd.Int("threads", -1, "Maximum number of concurrent connections (default: from profile)")
+	concurrency := cmd.Int("threads", -1, "Maximum number of concurrent connections across all hosts (default: from profile)")
+	parallelHosts := cmd.Int("parallel-hosts", 0, "Number of hosts scanned at the same time (default: from profile)")
 
 	//flags irrelevantes para SYN
 	banner := cmd.Bool("banner", false, "Enable passive banner grabbing (Connect scan only)")
 	probeFlag := cmd.Bool("probe", false, "Enable ACTIVE probing on detected services")
-	probeTypes := cmd.String("probe-types", "http,https", "Comma-separated list of probe types to run (default: http,https)")
+	probeTypes := cmd.String("probe-types", "http,https,tls", "Comma-separated list of probe types to run (default: http,https,tls)")
 	allPorts := cmd.Bool("all", false, "Show all scanned ports (including CLOSED)")
-
-	cmd.Parse(args)
+	jsonOut := cmd.String("json", "", "Write the report as JSON to a file ('-' for stdout)")
+	withUDP := cmd.Bool("udp", false, "Also run a UDP scan in the same campaign (U: ports or top UDP ports)")
+	nullProbe := cmd.Bool("null-probe", false, "Wait for an unsolicited banner on every open port (Connect scan only)")
+	genericProbe := cmd.Bool("generic-probe", false, "Send generic probes (blank lines, HTTP GET) when no banner arrives (implies --null-probe)")
+	bannerWait := cmd.Int("banner-wait", 0, "Max wait per port for a banner in ms (default: from profile)")
+	bannerBudget := cmd.Int("banner-budget", 0, "Total banner grabbing time across the scan in ms (default: from profile)")
+	tlsFingerprints := cmd.String("tls-fingerprints", "", "Extra list of known JARM/JA3S hashes (type<TAB>hash<TAB>label)")
+	vhosts := cmd.String("vhosts", "", "Comma-separated virtual hosts to request on web ports (Host header and SNI)")
+	dnsZones := cmd.String("dns-zones", "", "Comma-separated zones to test for zone transfer (AXFR) on DNS servers")
+	communities := cmd.String("snmp-communities", "public", "Comma-separated SNMP communities to try (v1/v2c GET only)")
+	templates := cmd.String("templates", "", "Directory of YAML check templates (enable with --probe-types templates, tag:<tag> or severity:<level>)")
+	scripts := cmd.String("scripts", "", "Directory of Starlark probe scripts (enable with --probe-types scripts, tag:<tag> or the script name)")
+	vulnFeed := cmd.String("vuln-feed", "", "Local JSON feed of CVEs by CPE version range, matched against identified services")
+	sourceIP := cmd.String("source-ip", "", "Local IP address to send connections from (scanners, version detection and probes)")
+	tlsCert := cmd.String("tls-cert", "", "Client certificate (PEM) presented by the probes in TLS handshakes")
+	tlsKey := cmd.String("tls-key", "", "Private key (PEM) of --tls-cert")
+	minSeverity := cmd.String("min-severity", "info", "Lowest severity of findings and vulnerabilities to report (info, low, medium, high, critical); ports are still listed")
+	versionDetect := cmd.Bool("version-detect", false, "Identify product and version with the service probe database")
+	versionIntensity := cmd.Int("version-intensity", 0, "Highest probe rarity to send during version detection (1-9, default 7)")
+	serviceProbes := cmd.String("service-probes", "", "Extra service probe file (nmap-service-probes subset)")
+	noRandomize := cmd.Bool("no-randomize", false, "Scan ports and hosts in sequential order")
+	seed := cmd.Int64("seed", 0, "Seed for the port/host permutation (default: random)")
+
+	cmd.Parse(normalizePortArgs(args))
+
+	//--top-ports reemplaza la lista por defecto de -p
+	ports := *portRange
+	if *topPorts > 0 && !flagWasSet(cmd, "p") {
+		ports = ""
+	}
 
 	//validar argumentos
 	if cmd.NArg() < 1 {
@@ -77,24 +108,76 @@ func handleTCPGeneric(args []string, scanType string) {
 		os.Exit(1)
 	}
 
+	severity, ok := service.ParseSeverity(*minSeverity)
+	if !ok {
+		fmt.Printf("Error: unknown severity '%s' (info, low, medium, high, critical)\n", *minSeverity)
+		os.Exit(1)
+	}
+
 	//parsear probes types
 	activeProbes := strings.Split(*probeTypes, ",")
 	for i := range activeProbes {
 		activeProbes[i] = strings.TrimSpace(strings.ToLower(activeProbes[i]))
 	}
 
+	//virtual hosts (vacio = solo el hostname del target)
+	var vhostList []string
+	for _, v := range strings.Split(*vhosts, ",") {
+		if v = strings.TrimSpace(strings.ToLower(v)); v != "" {
+			vhostList = append(vhostList, v)
+		}
+	}
+
+	//zonas para AXFR (vacio = no se pide ninguna transferencia)
+	var zoneList []string
+	for _, z := range strings.Split(*dnsZones, ",") {
+		if z = strings.TrimSuffix(strings.TrimSpace(strings.ToLower(z)), "."); z != "" {
+			zoneList = append(zoneList, z)
+		}
+	}
+
+	communityList := splitList(*communities)
+
+	//campaña combinada TCP + UDP
+	var scanTypes []string
+	if *withUDP {
+		scanTypes = []string{scanType, "UDP"}
+	}
+
 	//configurar request con el ScanType explicito
 	req := scan.ScanRequest{
-		Targets:     targets,
-		Ports:       *portRange,
+		Targets:     []string{rawTarget}, //el servicio expande el target y conserva el hostname
+		Ports:       ports,
+		TopPorts:    *topPorts,
 		ProfileName: *profileName,
 		Options: scan.ScanOptions{
-			TimeoutMs:   *timeoutMs,
-			Concurrency: *concurrency,
-			Banner:      *banner,
-			Probe:       *probeFlag,
-			ProbeTypes:  activeProbes,
-			ScanType:    scanType, //inyeccion critica
+			TimeoutMs:        *timeoutMs,
+			Concurrency:      *concurrency,
+			ParallelHosts:    *parallelHosts,
+			Banner:           *banner,
+			Probe:            *probeFlag,
+			ProbeTypes:       activeProbes,
+			ScanType:         scanType, //inyeccion critica
+			ScanTypes:        scanTypes,
+			NullProbe:        *nullProbe,
+			GenericProbe:     *genericProbe,
+			BannerWaitMs:     *bannerWait,
+			BannerBudgetMs:   *bannerBudget,
+			VersionDetection: *versionDetect,
+			VersionIntensity: *versionIntensity,
+			ServiceProbes:    *serviceProbes,
+			TLSFingerprints:  *tlsFingerprints,
+			VHosts:           vhostList,
+			DNSZones:         zoneList,
+			SNMPCommunities:  communityList,
+			Templates:        *templates,
+			Scripts:          *scripts,
+			VulnFeed:         *vulnFeed,
+			SourceIP:         *sourceIP,
+			TLSCert:          *tlsCert,
+			TLSKey:           *tlsKey,
+			NoRandomize:      *noRandomize,
+			Seed:             *seed,
 		},
 	}
 
@@ -117,7 +200,30 @@ func handleTCPGeneric(args []string, scanType string) {
 	}
 
 	// Reportar
+	//hallazgos y vulnerabilidades desde la severidad pedida (ambos reportes)
+	reportResult.Results = report.FilterSeverity(reportResult.Results, severity)
+
+	if *jsonOut == "-" {
+		//solo JSON en stdout, para poder encadenarlo con otras herramientas
+		if err := report.SaveJSON(*jsonOut, reportResult); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON report: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	report.PrintResults(reportResult.Results, *allPorts)
 
+	if *jsonOut != "" {
+		if err := report.SaveJSON(*jsonOut, reportResult); err != nil {
+			fmt.Printf("Error writing JSON report: %v\n", err)
+		} else {
+			fmt.Printf("JSON report written to %s\n", *jsonOut)
+		}
+	}
+
+	if reportResult.Metadata.Randomized {
+		fmt.Printf("Randomized order seed: %d (use --seed to reproduce)\n", reportResult.Metadata.Seed)
+	}
 	fmt.Printf("Campaign completed in %v\n", reportResult.Metadata.Duration)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"go-scanner/internal/app/scan"
 	"go-scanner/internal/report"
+	"go-scanner/internal/scanner/service"
 	"go-scanner/internal/utils"
 	"os"
 	"strings"
@@ -23,11 +24,26 @@ func handleUDPCommand(args []string) {
 func printUDPUsage() {
 	fmt.Println("Usage: go-scanner udp [options] <target>")
 	fmt.Println("Options:")
-	fmt.Println("  -p <ports>       Ports to scan (e.g: '53,67,123,161' or '1-1000')")
+	fmt.Println("  -p <ports>       Ports to scan (e.g: '53,67,123,161', '1-1000', 'dns,snmp' or '-')")
+	fmt.Println("  --top-ports <n>  Scan the N most frequent UDP ports")
 	fmt.Println("  --profile        Scan profile: passive, default, aggressive")
 	fmt.Println("  --timeout        Timeout per packet in ms")
 	fmt.Println("  --threads        Maximum concurrent packets")
+	fmt.Println("  --parallel-hosts Number of hosts scanned at the same time")
 	fmt.Println("  --all            Show all scanned ports")
+	fmt.Println("  --json <file>    Write the report as JSON ('-' for stdout)")
+	fmt.Println("  --probe          Enable ACTIVE probing on detected services")
+	fmt.Println("  --probe-types    Comma-separated probe types (default: dns,snmp)")
+	fmt.Println("  --snmp-communities Comma-separated SNMP communities to try (default: public)")
+	fmt.Println("  --templates      Directory of YAML check templates (see --probe-types)")
+	fmt.Println("  --scripts        Directory of Starlark probe scripts (see --probe-types)")
+	fmt.Println("  --vuln-feed      Local JSON feed of CVEs matched against identified services")
+	fmt.Println("  --source-ip      Local IP address to send packets from")
+	fmt.Println("  --min-severity   Lowest severity of findings and vulnerabilities to report, ports are still listed (default: info)")
+	fmt.Println("  --version-detect Identify product and version with the service probe database")
+	fmt.Println("  --service-probes Extra service probe file (nmap-service-probes subset)")
+	fmt.Println("  --no-randomize   Scan ports and hosts in sequential order")
+	fmt.Println("  --seed           Seed for the port/host permutation")
 	fmt.Println("\nExample:")
 	fmt.Println("  go-scanner udp -p 53,67,123 192.168.1.1")
 }
@@ -36,12 +52,33 @@ func handleUDPScan(args []string) {
 	cmd := flag.NewFlagSet("udp", flag.ExitOnError)
 
 	profileName := cmd.String("profile", "default", "Scan profile: passive, default, aggressive")
-	portRange := cmd.String("p", "53,67,123,161,500,4500", "Ports to scan")
+	portRange := cmd.String("p", "53,67,123,161,500,4500", "Ports to scan (numbers, ranges, service names, '-' for all)")
+	topPorts := cmd.Int("top-ports", 0, "Scan the N most frequent UDP ports")
 	timeoutMs := cmd.Int("timeout", -1, "Timeout per packet in ms")
 	concurrency := cmd.Int("threads", -1, "Maximum concurrent packets")
+	parallelHosts := cmd.Int("parallel-hosts", 0, "Number of hosts scanned at the same time (default: from profile)")
 	allPorts := cmd.Bool("all", false, "Show all scanned ports")
-
-	cmd.Parse(args)
+	jsonOut := cmd.String("json", "", "Write the report as JSON to a file ('-' for stdout)")
+	probeFlag := cmd.Bool("probe", false, "Enable ACTIVE probing on detected services")
+	probeTypes := cmd.String("probe-types", "dns,snmp", "Comma-separated list of probe types to run (default: dns,snmp)")
+	communities := cmd.String("snmp-communities", "public", "Comma-separated SNMP communities to try (v1/v2c GET only)")
+	templates := cmd.String("templates", "", "Directory of YAML check templates (enable with --probe-types templates, tag:<tag> or severity:<level>)")
+	scripts := cmd.String("scripts", "", "Directory of Starlark probe scripts (enable with --probe-types scripts, tag:<tag> or the script name)")
+	vulnFeed := cmd.String("vuln-feed", "", "Local JSON feed of CVEs by CPE version range, matched against identified services")
+	sourceIP := cmd.String("source-ip", "", "Local IP address to send connections from (scanners, version detection and probes)")
+	minSeverity := cmd.String("min-severity", "info", "Lowest severity of findings and vulnerabilities to report (info, low, medium, high, critical); ports are still listed")
+	versionDetect := cmd.Bool("version-detect", false, "Identify product and version with the service probe database")
+	versionIntensity := cmd.Int("version-intensity", 0, "Highest probe rarity to send during version detection (1-9, default 7)")
+	serviceProbes := cmd.String("service-probes", "", "Extra service probe file (nmap-service-probes subset)")
+	noRandomize := cmd.Bool("no-randomize", false, "Scan ports and hosts in sequential order")
+	seed := cmd.Int64("seed", 0, "Seed for the port/host permutation (default: random)")
+
+	cmd.Parse(normalizePortArgs(args))
+
+	ports := *portRange
+	if *topPorts > 0 && !flagWasSet(cmd, "p") {
+		ports = ""
+	}
 
 	if cmd.NArg() < 1 {
 		fmt.Println("Error: target required")
@@ -62,14 +99,34 @@ func handleUDPScan(args []string) {
 		os.Exit(1)
 	}
 
+	severity, ok := service.ParseSeverity(*minSeverity)
+	if !ok {
+		fmt.Printf("Error: unknown severity '%s' (info, low, medium, high, critical)\n", *minSeverity)
+		os.Exit(1)
+	}
+
 	req := scan.ScanRequest{
-		Targets:     targets,
-		Ports:       *portRange,
+		Targets:     []string{rawTarget}, //el servicio expande el target y conserva el hostname
+		Ports:       ports,
+		TopPorts:    *topPorts,
 		ProfileName: *profileName,
 		Options: scan.ScanOptions{
-			TimeoutMs:   *timeoutMs,
-			Concurrency: *concurrency,
-			ScanType:    "UDP",
+			TimeoutMs:        *timeoutMs,
+			Concurrency:      *concurrency,
+			ParallelHosts:    *parallelHosts,
+			ScanType:         "UDP",
+			Probe:            *probeFlag,
+			ProbeTypes:       splitList(strings.ToLower(*probeTypes)),
+			SNMPCommunities:  splitList(*communities),
+			Templates:        *templates,
+			Scripts:          *scripts,
+			VulnFeed:         *vulnFeed,
+			SourceIP:         *sourceIP,
+			VersionDetection: *versionDetect,
+			VersionIntensity: *versionIntensity,
+			ServiceProbes:    *serviceProbes,
+			NoRandomize:      *noRandomize,
+			Seed:             *seed,
 		},
 	}
 
@@ -88,7 +145,30 @@ func handleUDPScan(args []string) {
 		os.Exit(1)
 	}
 
+	//hallazgos y vulnerabilidades desde la severidad pedida (ambos reportes)
+	reportResult.Results = report.FilterSeverity(reportResult.Results, severity)
+
+	if *jsonOut == "-" {
+		//solo JSON en stdout, para poder encadenarlo con otras herramientas
+		if err := report.SaveJSON(*jsonOut, reportResult); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON report: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	report.PrintResults(reportResult.Results, *allPorts)
 
+	if *jsonOut != "" {
+		if err := report.SaveJSON(*jsonOut, reportResult); err != nil {
+			fmt.Printf("Error writing JSON report: %v\n", err)
+		} else {
+			fmt.Printf("JSON report written to %s\n", *jsonOut)
+		}
+	}
+
+	if reportResult.Metadata.Randomized {
+		fmt.Printf("Randomized order seed: %d (use --seed to reproduce)\n", reportResult.Metadata.Seed)
+	}
 	fmt.Printf("Scan completed in %v\n", reportResult.Metadata.Duration)
 }
@@ -3,6 +3,7 @@ package profile
 import (
 	"go-scanner/internal/discover/policy"
 	"go-scanner/internal/orchestrator"
+	"go-scanner/internal/utils"
 	"time"
 )
 
@@ -23,9 +24,13 @@ var (
 			Type:             orchestrator.ScanTypeConnect,
 			Timeout:          2 * time.Second,
 			Concurrency:      50,
+			ParallelHosts:    2,
 			ServiceDetection: true,
 			ActiveProbing:    false,
 			AllowedProbes:    nil,
+			BannerWait:       2 * time.Second,
+			BannerBudget:     60 * time.Second,
+			Order:            utils.Permutation{Enabled: true},
 			Discovery: policy.Policy{
 				Enabled: false, // passive scan asume que sabes que existen, o no hace ruido extra
 				Methods: nil,
@@ -42,9 +47,13 @@ var (
 			Type:             orchestrator.ScanTypeConnect,
 			Timeout:          1 * time.Second,
 			Concurrency:      100,
+			ParallelHosts:    8,
 			ServiceDetection: true,
 			ActiveProbing:    false,
 			AllowedProbes:    nil,
+			BannerWait:       1500 * time.Millisecond,
+			BannerBudget:     30 * time.Second,
+			Order:            utils.Permutation{Enabled: true},
 			Discovery: policy.Policy{
 				Enabled: true,
 				Methods: []string{"icmp", "tcp-connect"},
@@ -56,14 +65,19 @@ var (
 	// AGGRESSIVE: escaneo rapido con probing activo
 	Aggressive = Profile{
 		Name:        "aggressive",
-		Description: "Aggressive scan: faster, active probing enabled on HTTP/HTTPS",
+		Description: "Aggressive scan: faster, active probing enabled on HTTP/HTTPS/TLS/STARTTLS/SSH/databases and version detection",
 		Policy: orchestrator.ScanPolicy{
 			Type:             orchestrator.ScanTypeConnect,
 			Timeout:          500 * time.Millisecond,
 			Concurrency:      200,
+			ParallelHosts:    16,
 			ServiceDetection: true,
 			ActiveProbing:    true,
-			AllowedProbes:    []string{"http", "https"},
+			AllowedProbes:    []string{"http", "https", "tls", "starttls", "ssh", "database"},
+			VersionDetection: true,
+			BannerWait:       1 * time.Second,
+			BannerBudget:     30 * time.Second,
+			Order:            utils.Permutation{Enabled: true},
 			Discovery: policy.Policy{
 				Enabled: true,
 				Methods: []string{"icmp", "tcp-connect"},
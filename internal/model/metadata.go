@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
 
 // encapsula el contexto del descubrimiento sobre un target obtenido
 type HostMetadata struct {
@@ -10,6 +15,74 @@ type HostMetadata struct {
 	DiscoveryReason string          //razon de vida (syn-ack, echo-reply)
 	DiscoveryTime   time.Time       //momento del descubrimiento
 	Confidence      ConfidenceLevel //high, medium, low
+	Target          string          //hostname dado por el usuario (vacio si el target era una IP)
+
+	mu        sync.Mutex //los hostnames llegan desde varios probes en paralelo
+	hostnames []string   //nombres asociados al host (SANs de certificados, ...)
+	workgroup string     //workgroup o dominio NetBIOS (SMB/NBSTAT)
+}
+
+// agrega hostnames descubiertos durante el escaneo (sin duplicados)
+func (m *HostMetadata) AddHostnames(names ...string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(n), "."))
+		if n == "" || containsName(m.hostnames, n) {
+			continue
+		}
+		m.hostnames = append(m.hostnames, n)
+	}
+}
+
+// hostnames conocidos del host, ordenados
+func (m *HostMetadata) Hostnames() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]string, len(m.hostnames))
+	copy(out, m.hostnames)
+	sort.Strings(out)
+	return out
+}
+
+// registra el workgroup o dominio NetBIOS (se conserva el primero)
+func (m *HostMetadata) SetWorkgroup(name string) {
+	if m == nil || name == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.workgroup == "" {
+		m.workgroup = strings.ToUpper(name)
+	}
+}
+
+// workgroup o dominio NetBIOS del host (vacio si no se obtuvo)
+func (m *HostMetadata) Workgroup() string {
+	if m == nil {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.workgroup
+}
+
+func containsName(list []string, name string) bool {
+	for _, v := range list {
+		if v == name {
+			return true
+		}
+	}
+	return false
 }
 
 // nivel de confianza del descubrimiento
@@ -21,3 +94,11 @@ const (
 	ConfidenceLow     ConfidenceLevel = "low"
 	ConfidenceUnknown ConfidenceLevel = "unknown"
 )
+
+// protocolo de transporte de un resultado
+type Protocol string
+
+const (
+	ProtocolTCP Protocol = "tcp"
+	ProtocolUDP Protocol = "udp"
+)
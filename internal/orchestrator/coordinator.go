@@ -6,6 +6,10 @@ import (
 	"go-scanner/internal/discover/core"
 	"go-scanner/internal/model"
 	"go-scanner/internal/scanner"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/builtin"
+	"os"
+	"sync"
 )
 
 // define una funcion que crea un scanner para un target dado
@@ -15,12 +19,15 @@ type ScannerFactory func(target string, meta *model.HostMetadata) (scanner.Scann
 type Coordinator struct {
 	Policy  ScanPolicy
 	Factory ScannerFactory
+	Names   map[string]string //IP -> hostname dado por el usuario (opcional)
+	Probers *probe.Registry   //probers de la campaña, compartidos por todos los hosts
 }
 
 func NewCoordinator(policy ScanPolicy, factory ScannerFactory) *Coordinator {
 	return &Coordinator{
 		Policy:  policy,
 		Factory: factory,
+		Probers: builtin.Default(),
 	}
 }
 
@@ -40,7 +47,8 @@ func (c *Coordinator) Run(ctx context.Context, targets []string) (<-chan scanner
 		//fase de descubrimiento
 		scannableTargets := targets
 		if c.Policy.Discovery.Enabled {
-			fmt.Printf("Starting discovery phase on %d targets...\n", len(targets))
+			//progreso a stderr, stdout queda libre para reportes (ej. --json -)
+			fmt.Fprintf(os.Stderr, "Starting discovery phase on %d targets...\n", len(targets))
 			aliveResults, err := core.Run(ctx, targets, c.Policy.Discovery)
 
 			if err != nil {
@@ -66,52 +74,73 @@ func (c *Coordinator) Run(ctx context.Context, targets []string) (<-chan scanner
 				}
 			}
 			scannableTargets = aliveIPs
-			fmt.Printf("Discovery complete. %d/%d hosts alive.\n", len(scannableTargets), len(targets))
+			fmt.Fprintf(os.Stderr, "Discovery complete. %d/%d hosts alive.\n", len(scannableTargets), len(targets))
 		}
 
-		//iterar sobre targets
+		//orden de hosts permutado y varios hosts a la vez, asi la carga no cae sobre un solo host
+		scannableTargets = c.Policy.Order.Hosts(scannableTargets)
+		hosts := make(chan string)
+		var wg sync.WaitGroup
+		for i := 0; i < c.Policy.HostParallelism(len(scannableTargets)); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for target := range hosts {
+					c.scanHost(ctx, target, metadataMap[target], out, errChan)
+				}
+			}()
+		}
+
+		//repartir targets (en el orden de la permutacion)
+	feed:
 		for _, target := range scannableTargets {
-			//revisar contexto
 			select {
 			case <-ctx.Done():
-				return
-			default:
+				break feed
+			case hosts <- target:
 			}
+		}
+		close(hosts)
+		wg.Wait()
+	}()
 
-			meta := metadataMap[target]
+	return out, errChan
+}
 
-			//en caso de si discovery esta deshabilitado, hace que meta sea nil pues
-			if meta == nil {
-				meta = &model.HostMetadata{
-					ID:         target,
-					Confidence: "unknown",
-				}
-			}
+// escanea un host y envia sus resultados
+func (c *Coordinator) scanHost(ctx context.Context, target string, meta *model.HostMetadata, out chan<- scanner.ScanResult, errChan chan<- error) {
+	//en caso de si discovery esta deshabilitado, hace que meta sea nil pues
+	if meta == nil {
+		meta = &model.HostMetadata{
+			ID:         target,
+			Confidence: "unknown",
+		}
+	}
+	if name := c.Names[target]; name != "" {
+		meta.Target = name
+		meta.AddHostnames(name)
+	}
 
-			//emplea el factory de los scanners
-			s, err := c.Factory(target, meta)
+	//emplea el factory de los scanners
+	s, err := c.Factory(target, meta)
 
-			if err != nil {
-				errChan <- fmt.Errorf("failed to create scanner for %s: %w", target, err)
-				continue
-			}
+	if err != nil {
+		errChan <- fmt.Errorf("failed to create scanner for %s: %w", target, err)
+		return
+	}
 
-			engine := NewEngine(c.Policy, target, s)
+	engine := NewEngine(c.Policy, target, s)
+	engine.Probers = c.Probers
 
-			//ejecutar engine
-			// PENDIENTE -> el engine debe retornar errores en caso de fallo
-			results := engine.Run(ctx)
+	//ejecutar engine
+	// PENDIENTE -> el engine debe retornar errores en caso de fallo
+	results := engine.Run(ctx)
 
-			//resultados
-			for res := range results {
-				select {
-				case <-ctx.Done():
-					return
-				case out <- res:
-				}
-			}
+	//resultados (se drena el canal aunque se cancele, el engine no se queda bloqueado)
+	for res := range results {
+		select {
+		case <-ctx.Done():
+		case out <- res:
 		}
-	}()
-
-	return out, errChan
+	}
 }
@@ -4,17 +4,25 @@ import (
 	"context"
 	"go-scanner/internal/scanner"
 	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/builtin"
 	"go-scanner/internal/scanner/service"
+	"go-scanner/internal/scanner/version"
+	"go-scanner/internal/scanner/vuln"
+	"net"
 	"strings"
 	"sync"
 	"time"
 )
 
-// pipeline: scanner -> service detection -> active probing
+// timeout minimo de 3s para probes (puede que en policy defina un timeout global)
+const probeTimeout = 3 * time.Second
+
+// pipeline: scanner -> service detection -> version detection -> active probing
 type Engine struct {
 	Policy  ScanPolicy
 	Target  string
 	Scanner scanner.Scanner
+	Probers *probe.Registry //probers del probing activo (por defecto los incluidos)
 }
 
 func NewEngine(policy ScanPolicy, target string, baseScanner scanner.Scanner) *Engine {
@@ -22,6 +30,7 @@ func NewEngine(policy ScanPolicy, target string, baseScanner scanner.Scanner) *E
 		Policy:  policy,
 		Target:  target,
 		Scanner: baseScanner,
+		Probers: builtin.Default(),
 	}
 }
 
@@ -60,7 +69,7 @@ func (e *Engine) Run(ctx context.Context) <-chan scanner.ScanResult {
 				}
 
 				//procesar resultado
-				enriched := e.processResult(r)
+				enriched := e.processResult(ctx, r)
 
 				//enviar a la salida
 				out <- enriched
@@ -74,67 +83,199 @@ func (e *Engine) Run(ctx context.Context) <-chan scanner.ScanResult {
 }
 
 // logica de negocio sobre un resultado crudo
-func (e *Engine) processResult(res scanner.ScanResult) scanner.ScanResult {
+func (e *Engine) processResult(ctx context.Context, res scanner.ScanResult) scanner.ScanResult {
 	if !res.IsOpen() {
 		return res
 	}
 
 	//deteccion de servicio (pasiva o activa)
 	if e.Policy.ServiceDetection {
-		svcInfo := service.Detect(res.Port, res.Banner)
-		res.Service = string(svcInfo.Type)
+		svcInfo := service.Detect(res.Protocol, res.Port, res.Banner)
+		res.ServiceInfo = &svcInfo
+
+		//lo que dice el servicio pesa mas que el numero de puerto (sin trafico extra)
+		identified := e.applyBannerMatch(&res)
+
+		//deteccion de version basada en la base de probes
+		if e.Policy.VersionDetection && !identified {
+			e.applyVersionDetection(&res)
+		}
 
-		//probing activo
+		//probing activo (segun el servicio identificado, no el puerto)
 		if e.Policy.ActiveProbing {
-			e.applyActiveProbe(&res, svcInfo.Type)
+			e.applyProbers(ctx, &res)
 		}
+
+		//CVEs conocidos para lo identificado (feed local, sin trafico)
+		if e.Policy.VulnFeed != "" {
+			e.applyVulns(&res)
+		}
+		res.Service = string(res.ServiceInfo.Type)
 	}
 	return res
 }
 
-// aplica probes activos
-func (e *Engine) applyActiveProbe(res *scanner.ScanResult, svcType service.ServiceType) {
-	serviceName := string(svcType)
+// identifica el servicio por el banner ya capturado con las reglas de la base de probes
+// retorna true si hubo un match definitivo (hard match)
+func (e *Engine) applyBannerMatch(res *scanner.ScanResult) bool {
+	if res.Banner == "" {
+		return false
+	}
 
-	//buscar prober en probe/registry
-	prober, found := probe.Get(serviceName)
-	if !found {
-		return
+	db, err := version.Load(e.Policy.ServiceProbes)
+	if err != nil {
+		return false //validado al crear el escaneo
 	}
 
-	//verficar si el prober esta permitido
-	allowed := false
-	lowerService := strings.ToLower(serviceName)
+	match, ok := db.MatchBanner(res.Protocol, res.Banner)
+	if !ok {
+		return false
+	}
+	res.ServiceInfo.Merge(match.Info)
+	return !match.Soft
+}
 
-	if len(e.Policy.AllowedProbes) == 0 {
-		//asumimos nada por seguridad, actualmente el CLI deja como default "http,https"
-		//falta implementar mas policy
+// identifica producto y version enviando los probes de la base
+func (e *Engine) applyVersionDetection(res *scanner.ScanResult) {
+	db, err := version.Load(e.Policy.ServiceProbes)
+	if err != nil {
+		return //validado al crear el escaneo
+	}
+
+	engine := version.NewEngine(db, probeTimeout, e.Policy.VersionIntensity)
+	engine.Dialer = e.Policy.Dialer
+	info, err := engine.Identify(e.Target, res.Port, res.Protocol)
+	if err == nil && info != nil {
+		res.ServiceInfo.Merge(info)
+	}
+}
+
+// vulnerabilidades del feed para los CPE del servicio
+func (e *Engine) applyVulns(res *scanner.ScanResult) {
+	db, err := vuln.Load(e.Policy.VulnFeed)
+	if err != nil {
+		return //validado al crear el escaneo
+	}
+	res.ServiceInfo.Merge(&service.ServiceInfo{Vulns: db.Match(res.ServiceInfo)})
+}
+
+// ejecuta los probers que aplican al servicio, respetando sus dependencias
+// cada resultado se integra antes de elegir el siguiente, asi se encadenan (ej. tls identifica HTTPS -> http)
+func (e *Engine) applyProbers(ctx context.Context, res *scanner.ScanResult) {
+	if e.Probers == nil {
 		return
 	}
 
-	//iterar sobre la lista de allowedProbes
-	for _, t := range e.Policy.AllowedProbes {
-		if t == "all" || t == lowerService {
-			allowed = true
-			break
+	opts := e.probeOptions()
+	done := make(map[string]bool)
+	for ctx.Err() == nil {
+		target := probe.Target{
+			Host:     e.Target,
+			Port:     res.Port,
+			Protocol: res.Protocol,
+			Service:  res.ServiceInfo,
+			VHosts:   e.vhosts(res),
+		}
+		prober, ok := e.Probers.Next(target, e.probeAllowed, done)
+		if !ok {
+			return
+		}
+		done[strings.ToLower(prober.Spec().Name)] = true
+
+		//lo identificado por el probe se integra al fingerprint, el banner queda crudo
+		info, err := prober.Probe(ctx, target, opts)
+		if err != nil {
+			continue
 		}
+		res.ServiceInfo.Merge(info)
+		recordHostnames(res, info)
 	}
+}
 
-	if !allowed {
-		return
+// conexion y datos de la policy que reciben los probers
+func (e *Engine) probeOptions() probe.Options {
+	return probe.Options{
+		Dialer:          e.Policy.Dialer,
+		TLS:             e.Policy.TLS,
+		Timeout:         probeTimeout,
+		DNSZones:        e.Policy.DNSZones,
+		SNMPCommunities: e.Policy.SNMPCommunities,
+		TLSFingerprints: e.Policy.TLSFingerprints,
 	}
+}
 
-	//timeout minimo de 3s
-	probeTimeout := 3 * time.Second
+// hostname dado por el usuario primero, luego los vhosts de la policy
+func (e *Engine) vhosts(res *scanner.ScanResult) []string {
+	var out []string
+	var name string
+	if res.Metadata != nil && res.Metadata.Target != "" {
+		name = res.Metadata.Target
+		out = append(out, name)
+	}
+	for _, v := range e.Policy.VHosts {
+		if !strings.EqualFold(v, name) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
 
-	//puede que en policy defina un timeout global
+// los SANs del certificado y los nombres NTLM/NetBIOS/SNMP/LDAP son nombres del host
+func recordHostnames(res *scanner.ScanResult, info *service.ServiceInfo) {
+	if info.TLSInfo != nil && info.TLSInfo.Certificate != nil {
+		res.Metadata.AddHostnames(dnsNames(info.TLSInfo.Certificate.SANs)...)
+	}
 
-	probeBanner, err := prober.Probe(e.Target, res.Port, probeTimeout)
-	if err == nil && probeBanner != "" {
-		if res.Banner != "" {
-			res.Banner = res.Banner + " | " + probeBanner
-		} else {
-			res.Banner = probeBanner
+	//nombres y workgroup anunciados por el challenge NTLM (SMB, RDP) y NetBIOS
+	if info.SMB != nil {
+		recordNTLM(res, info.SMB.NTLM)
+	}
+	if info.RDP != nil {
+		recordNTLM(res, info.RDP.NTLM)
+	}
+	if info.SNMP != nil {
+		res.Metadata.AddHostnames(info.SNMP.SysName)
+	}
+	if info.LDAP != nil {
+		res.Metadata.AddHostnames(info.LDAP.DNSHostName)
+	}
+	if info.NetBIOS != nil {
+		for _, name := range info.NetBIOS.Names {
+			if !name.Group && name.Suffix == 0x00 {
+				res.Metadata.AddHostnames(name.Name)
+			}
 		}
+		res.Metadata.SetWorkgroup(info.NetBIOS.Workgroup)
 	}
 }
+
+func recordNTLM(res *scanner.ScanResult, n *service.NTLMInfo) {
+	if n == nil {
+		return
+	}
+	res.Metadata.AddHostnames(n.DNSComputer, n.NetBIOSComputer)
+	res.Metadata.SetWorkgroup(n.NetBIOSDomain)
+}
+
+// solo nombres DNS (sin IPs ni comodines)
+func dnsNames(sans []string) []string {
+	var names []string
+	for _, n := range sans {
+		if net.ParseIP(n) == nil && !strings.HasPrefix(n, "*") {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// verifica si un prober esta en la lista blanca de la policy
+func (e *Engine) probeAllowed(spec probe.Spec) bool {
+	if len(e.Policy.AllowedProbes) == 0 {
+		//asumimos nada por seguridad, actualmente el CLI deja como default "http,https,tls"
+		//falta implementar mas policy
+		return false
+	}
+
+	//por nombre, por grupo o "all" (que no incluye los explicitos, ej. OT)
+	return probe.Allowed(spec, e.Policy.AllowedProbes)
+}
@@ -1,7 +1,10 @@
 package orchestrator
 
 import (
+	"crypto/tls"
 	"go-scanner/internal/discover/policy"
+	"go-scanner/internal/scanner/portdb"
+	"go-scanner/internal/utils"
 	"time"
 )
 
@@ -14,19 +17,81 @@ const (
 	ScanTypeUDP     ScanType = "UDP"
 )
 
+// protocolo de transporte que recorre el tipo de escaneo ("tcp" o "udp")
+func (t ScanType) Protocol() string {
+	if t == ScanTypeUDP {
+		return portdb.UDP
+	}
+	return portdb.TCP
+}
+
 // define las reglas de negocio para el escaneo
 type ScanPolicy struct {
-	Type ScanType
+	Type  ScanType
+	Types []ScanType //tipos combinados en una misma campaña (ej. SYN + UDP), vacio = solo Type
 	//comportamiento general
-	Timeout     time.Duration
-	Concurrency int
+	Timeout       time.Duration
+	Concurrency   int //conexiones simultaneas en toda la campaña (se reparten entre los hosts en paralelo)
+	ParallelHosts int //hosts escaneados a la vez (0 = DefaultParallelHosts)
 
 	ServiceDetection bool     //deteccion de servicios (pasiva o activa)
 	ActiveProbing    bool     //probing activo (envio de payloads)
 	AllowedProbes    []string //lista blanca de tipos de probes permitidos
 
+	VersionDetection bool   //deteccion de version con la base de probes (envia payloads)
+	VersionIntensity int    //rareza maxima de los probes enviados (1-9, 0 = por defecto)
+	ServiceProbes    string //archivo de probes del usuario (formato nmap-service-probes)
+
+	TLSFingerprints string   //archivo de hashes JARM/JA3S conocidos del usuario
+	VHosts          []string //virtual hosts a probar (Host/SNI) en servicios web, ademas del hostname del target
+	DNSZones        []string //zonas para las que se prueba la transferencia (AXFR) en servidores DNS
+	SNMPCommunities []string //comunidades SNMP a probar (vacio = "public")
+	Templates       string   //directorio de templates YAML del usuario (checks declarativos)
+	Scripts         string   //directorio de scripts Starlark del usuario (probers con logica)
+	VulnFeed        string   //feed JSON local de CVEs por CPE (vacio = sin match de vulnerabilidades)
+
+	Dialer utils.Dialer //conexiones de scanners, banners, version y probers (nil = directo)
+	TLS    *tls.Config  //base de los handshakes de los probers (nil = sin verificar el certificado)
+
+	NullProbe    bool          //espera un banner no solicitado en cualquier puerto abierto
+	GenericProbe bool          //si no llega banner envia "\r\n\r\n" y un GET HTTP (activo)
+	BannerWait   time.Duration //espera maxima por puerto
+	BannerBudget time.Duration //tiempo total de espera de banners en toda la campaña
+
+	Order utils.Permutation //orden pseudo-aleatorio de puertos y hosts (anti IDS)
+
 	// Politica de descubrimiento (fase previa)
 	Discovery policy.Policy
 }
 
+// hosts escaneados a la vez cuando la policy no lo define
+const DefaultParallelHosts = 8
+
+// hosts escaneados a la vez, nunca mas que los hosts de la campaña
+func (p ScanPolicy) HostParallelism(hosts int) int {
+	n := p.ParallelHosts
+	if n <= 0 {
+		n = DefaultParallelHosts
+	}
+	if hosts > 0 && hosts < n {
+		n = hosts
+	}
+	return n
+}
+
+// conexiones simultaneas de cada host, el limite de la campaña repartido entre los hosts en paralelo
+func (p ScanPolicy) HostConcurrency(hosts int) int {
+	c := p.Concurrency / p.HostParallelism(hosts)
+	if c < 1 {
+		return 1
+	}
+	return c
+}
 
+// retorna los tipos de escaneo que ejecuta la campaña
+func (p ScanPolicy) ScanTypes() []ScanType {
+	if len(p.Types) > 0 {
+		return p.Types
+	}
+	return []ScanType{p.Type}
+}
@@ -3,9 +3,12 @@ package report
 import (
 	"fmt"
 	"go-scanner/internal/scanner"
+	"go-scanner/internal/scanner/service"
 	"os"
 	"sort"
+	"strings"
 	"text/tabwriter" //permite imprimir tablas alienadas :p
+	"time"
 )
 
 // lee el slice de resultados y los muestra formateados en consola
@@ -54,35 +57,691 @@ func PrintResults(results []scanner.ScanResult, showAll bool) {
 
 		fmt.Printf("\nTarget: %s\n", host)
 
-		//ordenamiento de resultados (ports)
+		//ordenamiento de resultados (port, proto)
 		sort.Slice(hostResults, func(i, j int) bool {
-			return hostResults[i].Port < hostResults[j].Port
+			return scanner.Less(hostResults[i], hostResults[j])
 		})
 
-		//verificar si se capturo algun banner para ajustar columnas
-		showBanner := false
+		//verificar si hay version o banner para ajustar columnas
+		showVersion, showBanner := false, false
 		for _, res := range hostResults {
+			if res.ServiceInfo.Summary() != "" {
+				showVersion = true
+			}
 			if res.Banner != "" {
 				showBanner = true
-				break
 			}
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
 		// Encabezados con STATE
+		header := []string{"PORT", "STATE", "SERVICE"}
+		if showVersion {
+			header = append(header, "VERSION")
+		}
 		if showBanner {
-			fmt.Fprintln(w, "PORT\tSTATE\tSERVICE\tBANNER")
-			for _, res := range hostResults {
-				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", res.Port, res.State, res.Service, res.Banner)
+			header = append(header, "BANNER")
+		}
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+
+		for _, res := range hostResults {
+			row := []string{res.PortLabel(), string(res.State), res.Service}
+			if showVersion {
+				row = append(row, res.ServiceInfo.Summary())
 			}
-		} else {
-			fmt.Fprintln(w, "PORT\tSTATE\tSERVICE")
-			for _, res := range hostResults {
-				fmt.Fprintf(w, "%d\t%s\t%s\n", res.Port, res.State, res.Service)
+			if showBanner {
+				row = append(row, firstLine(res.Banner))
 			}
+			fmt.Fprintln(w, strings.Join(row, "\t"))
 		}
 		w.Flush()
+
+		printServiceInfo(hostResults)
+		printTLSInfo(hostResults)
+		printSSHInfo(hostResults)
+		printHTTPInfo(hostResults)
+		printVHosts(hostResults)
+		printWebCapabilities(hostResults)
+		printDatabaseInfo(hostResults)
+		printSMBInfo(hostResults)
+		printRemoteDesktop(hostResults)
+		printDNSInfo(hostResults)
+		printSNMPInfo(hostResults)
+		printInfraInfo(hostResults)
+		printOTInfo(hostResults)
+		printFindings(hostResults)
+		printVulns(hostResults)
+		printHostnames(hostResults)
 	}
+
+	printBySeverity(results)
+	printSharedHostKeys(results)
 	fmt.Println("------------------------------")
 }
+
+// resumen por host de OS, tipos de dispositivo y CPE reportados por los servicios
+func printServiceInfo(results []scanner.ScanResult) {
+	var osHints, devices, cpes []string
+	seen := make(map[string]bool)
+
+	add := func(list *[]string, v string) {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			*list = append(*list, v)
+		}
+	}
+
+	for _, res := range results {
+		info := res.ServiceInfo
+		if info == nil || !res.IsOpen() {
+			continue
+		}
+		add(&osHints, info.OSHint)
+		add(&devices, info.DeviceType)
+		for _, c := range info.CPE {
+			add(&cpes, c)
+		}
+	}
+
+	var parts []string
+	if len(osHints) > 0 {
+		parts = append(parts, "OS: "+strings.Join(osHints, ", "))
+	}
+	if len(devices) > 0 {
+		parts = append(parts, "Device: "+strings.Join(devices, ", "))
+	}
+	if len(cpes) > 0 {
+		parts = append(parts, "CPE: "+strings.Join(cpes, ", "))
+	}
+	if len(parts) > 0 {
+		fmt.Printf("Service Info: %s\n", strings.Join(parts, "; "))
+	}
+}
+
+// detalle TLS por puerto: handshake, versiones obsoletas y certificado
+func printTLSInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil {
+			continue
+		}
+		if st := res.ServiceInfo.StartTLS; st != nil {
+			state := "not offered"
+			if st.Offered {
+				state = "offered"
+			}
+			if st.Required {
+				state += ", required"
+			}
+			fmt.Printf("%s %s: %s\n", st.Command, res.PortLabel(), state)
+		}
+
+		if fp := res.ServiceInfo.TLSFingerprint; fp != nil {
+			line := fmt.Sprintf("JARM %s: %s", res.PortLabel(), fp.JARM)
+			if fp.JA3S != "" {
+				line += " JA3S: " + fp.JA3S
+			}
+			if len(fp.Labels) > 0 {
+				line += " [" + strings.Join(fp.Labels, ", ") + "]"
+			}
+			fmt.Println(line)
+		}
+
+		t := res.ServiceInfo.TLSInfo
+		if t == nil {
+			continue
+		}
+
+		fmt.Printf("TLS %s: %s\n", res.PortLabel(), t.Summary())
+		if deprecated := t.Deprecated(); len(deprecated) > 0 {
+			fmt.Printf("  deprecated versions accepted: %s\n", strings.Join(deprecated, ", "))
+		}
+
+		c := t.Certificate
+		if c == nil {
+			continue
+		}
+		fmt.Printf("  subject: %s\n", c.Subject)
+		fmt.Printf("  issuer: %s\n", c.Issuer)
+		if len(c.SANs) > 0 {
+			fmt.Printf("  SANs: %s\n", strings.Join(c.SANs, ", "))
+		}
+		fmt.Printf("  valid: %s -> %s\n", c.NotBefore.Format("2006-01-02"), c.NotAfter.Format("2006-01-02"))
+
+		key := fmt.Sprintf("  key: %s %d", c.KeyType, c.KeyBits)
+		if c.SelfSigned {
+			key += " [self-signed]"
+		}
+		if c.Expired {
+			key += " [expired]"
+		}
+		fmt.Println(key)
+	}
+}
+
+// algoritmos, claves de host y algoritmos debiles de SSH
+func printSSHInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.SSH == nil {
+			continue
+		}
+		s := res.ServiceInfo.SSH
+
+		fmt.Printf("SSH %s: %s\n", res.PortLabel(), s.Banner)
+		fmt.Printf("  kex: %s\n", strings.Join(s.KexAlgorithms, ", "))
+		fmt.Printf("  host key: %s\n", strings.Join(s.HostKeyAlgorithms, ", "))
+		fmt.Printf("  ciphers: %s\n", strings.Join(s.Ciphers, ", "))
+		fmt.Printf("  macs: %s\n", strings.Join(s.MACs, ", "))
+		for _, k := range s.HostKeys {
+			fmt.Printf("  %s %s\n", k.Type, k.Fingerprint)
+		}
+		if len(s.Weak) > 0 {
+			fmt.Printf("  weak: %s\n", strings.Join(s.Weak, ", "))
+		}
+	}
+}
+
+// analisis de paginas web: titulo, redirects, headers, cookies, favicon y tecnologias
+func printHTTPInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.HTTP == nil {
+			continue
+		}
+		h := res.ServiceInfo.HTTP
+
+		fmt.Printf("HTTP %s: %d %s\n", res.PortLabel(), h.Status, h.Title)
+		if len(h.Redirects) > 0 {
+			fmt.Printf("  redirects: %s\n", strings.Join(h.Redirects, " -> "))
+		}
+		fmt.Printf("  final url: %s\n", h.FinalURL)
+		if len(h.MissingHeaders) > 0 {
+			fmt.Printf("  missing headers: %s\n", strings.Join(h.MissingHeaders, ", "))
+		}
+		for _, c := range h.Cookies {
+			var flags []string
+			if c.Secure {
+				flags = append(flags, "Secure")
+			}
+			if c.HttpOnly {
+				flags = append(flags, "HttpOnly")
+			}
+			if c.SameSite != "" {
+				flags = append(flags, "SameSite="+c.SameSite)
+			}
+			if len(flags) == 0 {
+				flags = append(flags, "no flags")
+			}
+			fmt.Printf("  cookie %s: %s\n", c.Name, strings.Join(flags, ", "))
+		}
+		if h.FaviconHash != nil {
+			fmt.Printf("  favicon mmh3: %d\n", *h.FaviconHash)
+		}
+		if len(h.Technologies) > 0 {
+			var techs []string
+			for _, t := range h.Technologies {
+				name := t.Name
+				if t.Version != "" {
+					name += " " + t.Version
+				}
+				techs = append(techs, name)
+			}
+			fmt.Printf("  technologies: %s\n", strings.Join(techs, ", "))
+		}
+	}
+}
+
+// respuestas por virtual host, marcando las que difieren de la respuesta por defecto
+func printVHosts(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || len(res.ServiceInfo.VHosts) == 0 {
+			continue
+		}
+
+		fmt.Printf("VHosts %s:\n", res.PortLabel())
+		for _, v := range res.ServiceInfo.VHosts {
+			host := v.Host
+			if host == "" {
+				host = "(default)"
+			}
+			line := fmt.Sprintf("  %s: %d %dB", host, v.Status, v.Length)
+			if v.Title != "" {
+				line += fmt.Sprintf(" %q", v.Title)
+			}
+			if v.Location != "" {
+				line += " -> " + v.Location
+			}
+			if v.Distinct {
+				line += " [distinct]"
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// h2, h2c, HTTP/3 anunciado y WebSocket por puerto
+func printWebCapabilities(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.Web == nil {
+			continue
+		}
+		w := res.ServiceInfo.Web
+
+		var caps []string
+		if w.H2 {
+			caps = append(caps, "h2")
+		}
+		if w.H2C {
+			caps = append(caps, "h2c")
+		}
+		if w.H3 {
+			caps = append(caps, "h3 (Alt-Svc, unconfirmed)")
+		}
+		if len(w.WebSocket) > 0 {
+			caps = append(caps, "websocket "+strings.Join(w.WebSocket, " "))
+		}
+		if len(caps) == 0 {
+			caps = append(caps, "http/1.x only")
+		}
+		fmt.Printf("Web %s: %s\n", res.PortLabel(), strings.Join(caps, ", "))
+		if len(w.AltSvc) > 0 {
+			fmt.Printf("  alt-svc: %s\n", strings.Join(w.AltSvc, ", "))
+		}
+	}
+}
+
+// autenticacion y cifrado anunciados por bases de datos
+func printDatabaseInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.Database == nil {
+			continue
+		}
+		d := res.ServiceInfo.Database
+
+		auth := "no authentication required"
+		if d.AuthRequired {
+			auth = "authentication required"
+		}
+		if d.AuthMethod != "" {
+			auth += " (" + d.AuthMethod + ")"
+		}
+		line := fmt.Sprintf("Database %s: %s", res.PortLabel(), auth)
+		if d.Encryption != "" {
+			line += ", encryption " + d.Encryption
+		}
+		fmt.Println(line)
+	}
+}
+
+// dialectos y firma SMB, datos NTLM y tabla de nombres NetBIOS
+func printSMBInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil {
+			continue
+		}
+		if s := res.ServiceInfo.SMB; s != nil {
+			signing := "disabled"
+			switch {
+			case s.SigningRequired:
+				signing = "required"
+			case s.SigningEnabled:
+				signing = "enabled, not required"
+			}
+			smb1 := "disabled"
+			if s.SMB1 {
+				smb1 = "enabled"
+			}
+			fmt.Printf("SMB %s: dialects %s, SMBv1 %s, signing %s\n",
+				res.PortLabel(), strings.Join(s.Dialects, ", "), smb1, signing)
+			if s.ServerGUID != "" {
+				fmt.Printf("  Server GUID: %s\n", s.ServerGUID)
+			}
+			printNTLM(s.NTLM)
+		}
+		if nb := res.ServiceInfo.NetBIOS; nb != nil {
+			var names []string
+			for _, n := range nb.Names {
+				label := fmt.Sprintf("%s<%02x>", n.Name, n.Suffix)
+				if n.Group {
+					label += " (group)"
+				}
+				names = append(names, label)
+			}
+			fmt.Printf("NetBIOS %s: %s", res.PortLabel(), strings.Join(names, ", "))
+			if nb.MAC != "" {
+				fmt.Printf(", MAC %s", nb.MAC)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// datos del challenge NTLM (SMB, RDP)
+func printNTLM(n *service.NTLMInfo) {
+	if n == nil {
+		return
+	}
+	fmt.Printf("  NTLM: computer %s, domain %s", n.NetBIOSComputer, n.NetBIOSDomain)
+	if n.DNSComputer != "" {
+		fmt.Printf(", FQDN %s", n.DNSComputer)
+	}
+	if n.OSVersion != "" {
+		fmt.Printf(", OS %s", n.OSVersion)
+	}
+	fmt.Println()
+}
+
+// protocolos de seguridad RDP y tipos de seguridad VNC
+func printRemoteDesktop(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil {
+			continue
+		}
+		if r := res.ServiceInfo.RDP; r != nil {
+			line := fmt.Sprintf("RDP %s: security %s", res.PortLabel(), strings.Join(r.Protocols, ", "))
+			if r.NLARequired {
+				line += " (NLA required)"
+			}
+			fmt.Println(line)
+			printNTLM(r.NTLM)
+		}
+		if v := res.ServiceInfo.VNC; v != nil {
+			line := fmt.Sprintf("VNC %s: RFB %s, security %s", res.PortLabel(), v.Version, strings.Join(v.SecurityTypes, ", "))
+			if v.NoAuth {
+				line += " [NO AUTHENTICATION]"
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// identidad CHAOS, recursion y transferencias de zona
+func printDNSInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.DNS == nil {
+			continue
+		}
+		d := res.ServiceInfo.DNS
+
+		var parts []string
+		if d.VersionBind != "" {
+			parts = append(parts, fmt.Sprintf("version.bind %q", d.VersionBind))
+		}
+		if d.IDServer != "" {
+			parts = append(parts, fmt.Sprintf("id.server %q", d.IDServer))
+		}
+		if d.Recursion {
+			parts = append(parts, "recursion available [OPEN RESOLVER]")
+		} else {
+			parts = append(parts, "no recursion")
+		}
+		fmt.Printf("DNS %s: %s\n", res.PortLabel(), strings.Join(parts, ", "))
+
+		for _, z := range d.ZoneTransfers {
+			if z.Allowed {
+				fmt.Printf("  AXFR %s: ALLOWED (%d records)\n", z.Zone, z.Records)
+			} else {
+				fmt.Printf("  AXFR %s: refused\n", z.Zone)
+			}
+		}
+	}
+}
+
+// comunidad aceptada, grupo system y engine SNMPv3
+func printSNMPInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.SNMP == nil {
+			continue
+		}
+		s := res.ServiceInfo.SNMP
+
+		if s.Community != "" {
+			fmt.Printf("SNMP %s: community %q accepted (%s)\n", res.PortLabel(), s.Community, s.Version)
+			if s.SysDescr != "" {
+				fmt.Printf("  sysDescr: %s\n", firstLine(s.SysDescr))
+			}
+			if s.SysName != "" {
+				fmt.Printf("  sysName: %s\n", s.SysName)
+			}
+			if s.SysObjectID != "" {
+				fmt.Printf("  sysObjectID: %s\n", s.SysObjectID)
+			}
+			if s.SysUpTime > 0 {
+				fmt.Printf("  sysUpTime: %s\n", time.Duration(s.SysUpTime)*10*time.Millisecond)
+			}
+			if s.SysContact != "" {
+				fmt.Printf("  sysContact: %s\n", s.SysContact)
+			}
+		} else {
+			fmt.Printf("SNMP %s: no community accepted\n", res.PortLabel())
+		}
+		if s.EngineID != "" {
+			line := fmt.Sprintf("  SNMPv3 engine %s, boots %d, time %s", s.EngineID, s.EngineBoots, time.Duration(s.EngineTime)*time.Second)
+			if s.EngineVendor != "" {
+				line += " (" + s.EngineVendor + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// NTP, SIP, LDAP, Kerberos, MQTT y AMQP
+func printInfraInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		info := res.ServiceInfo
+		if info == nil {
+			continue
+		}
+		if n := info.NTP; n != nil {
+			line := fmt.Sprintf("NTP %s: v%d, stratum %d", res.PortLabel(), n.Version, n.Stratum)
+			if n.RefID != "" {
+				line += ", refid " + n.RefID
+			}
+			if n.Daemon != "" {
+				line += fmt.Sprintf(", daemon %q", n.Daemon)
+			}
+			if n.System != "" {
+				line += fmt.Sprintf(", system %q", n.System)
+			}
+			if n.Monlist {
+				line += " [MONLIST ENABLED]"
+			}
+			fmt.Println(line)
+		}
+		if s := info.SIP; s != nil {
+			line := fmt.Sprintf("SIP %s: %s", res.PortLabel(), s.Status)
+			if s.Server != "" {
+				line += fmt.Sprintf(", server %q", s.Server)
+			}
+			fmt.Println(line)
+			if len(s.Allow) > 0 {
+				fmt.Printf("  Allow: %s\n", strings.Join(s.Allow, ", "))
+			}
+		}
+		if l := info.LDAP; l != nil {
+			fmt.Printf("LDAP %s: rootDSE readable anonymously\n", res.PortLabel())
+			if len(l.NamingContexts) > 0 {
+				fmt.Printf("  namingContexts: %s\n", strings.Join(l.NamingContexts, ", "))
+			}
+			if l.DNSHostName != "" {
+				fmt.Printf("  dnsHostName: %s\n", l.DNSHostName)
+			}
+			if l.VendorName != "" {
+				fmt.Printf("  vendor: %s %s\n", l.VendorName, l.VendorVersion)
+			}
+			if len(l.SASLMechanisms) > 0 {
+				fmt.Printf("  SASL: %s\n", strings.Join(l.SASLMechanisms, ", "))
+			}
+		}
+		if k := info.Kerberos; k != nil {
+			line := fmt.Sprintf("Kerberos %s: ", res.PortLabel())
+			if k.Realm != "" {
+				line += "realm " + k.Realm + " confirmed"
+			} else {
+				line += "KDC answered, realm not confirmed"
+			}
+			if k.ErrorName != "" {
+				line += " (" + k.ErrorName + ")"
+			}
+			if k.ServerTime != "" {
+				line += ", server time " + k.ServerTime
+			}
+			fmt.Println(line)
+		}
+		if m := info.MQTT; m != nil {
+			if m.Anonymous {
+				fmt.Printf("MQTT %s: anonymous CONNECT accepted [NO AUTHENTICATION]\n", res.PortLabel())
+			} else {
+				fmt.Printf("MQTT %s: anonymous CONNECT refused (return code %d)\n", res.PortLabel(), m.ReturnCode)
+			}
+		}
+		if a := info.AMQP; a != nil {
+			line := fmt.Sprintf("AMQP %s: protocol %s", res.PortLabel(), a.Protocol)
+			if len(a.Mechanisms) > 0 {
+				line += ", mechanisms " + strings.Join(a.Mechanisms, ", ")
+			}
+			if a.Platform != "" {
+				line += ", platform " + a.Platform
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// identidad de dispositivos industriales
+func printOTInfo(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.OT == nil {
+			continue
+		}
+		o := res.ServiceInfo.OT
+
+		var parts []string
+		for _, f := range [][2]string{
+			{"vendor", o.Vendor}, {"product", o.Product}, {"model", o.Model}, {"revision", o.Revision},
+			{"serial", o.Serial}, {"name", o.Name}, {"type", o.DeviceType}, {"address", o.Address},
+		} {
+			if f[1] != "" {
+				parts = append(parts, f[0]+" "+f[1])
+			}
+		}
+		if o.Vendor == "" && o.VendorID != 0 {
+			parts = append(parts, fmt.Sprintf("vendor id %d", o.VendorID))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "no identity reported")
+		}
+		fmt.Printf("%s %s: %s\n", o.Protocol, res.PortLabel(), strings.Join(parts, ", "))
+	}
+}
+
+// hallazgos de templates y scripts por puerto, los mas severos primero
+func printFindings(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || len(res.ServiceInfo.Findings) == 0 {
+			continue
+		}
+
+		fmt.Printf("Findings %s:\n", res.PortLabel())
+		for _, f := range sortedFindings(res.ServiceInfo.Findings) {
+			line := fmt.Sprintf("  [%s] %s (%s)", f.Severity, f.Name, f.ID)
+			if f.Matched != "" {
+				line += " " + f.Matched
+			}
+			fmt.Println(line)
+			if len(f.Extracted) > 0 {
+				fmt.Printf("    extracted: %s\n", strings.Join(f.Extracted, ", "))
+			}
+		}
+	}
+}
+
+// CVEs del feed por puerto, los mas severos primero
+func printVulns(results []scanner.ScanResult) {
+	for _, res := range results {
+		if res.ServiceInfo == nil || len(res.ServiceInfo.Vulns) == 0 {
+			continue
+		}
+
+		fmt.Printf("Vulnerabilities %s:\n", res.PortLabel())
+		for _, v := range sortedVulns(res.ServiceInfo.Vulns) {
+			line := fmt.Sprintf("  [%s", v.Severity)
+			if v.CVSS > 0 {
+				line += fmt.Sprintf(" %.1f", v.CVSS)
+			}
+			line += "] " + v.ID
+			if v.Summary != "" {
+				line += ": " + firstLine(v.Summary)
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// puertos con hallazgos o vulnerabilidades de todos los hosts, los mas severos primero
+func printBySeverity(results []scanner.ScanResult) {
+	var flagged []scanner.ScanResult
+	for _, res := range results {
+		if res.IsOpen() && res.ServiceInfo != nil && res.ServiceInfo.MaxSeverity() != "" {
+			flagged = append(flagged, res)
+		}
+	}
+	if len(flagged) == 0 {
+		return
+	}
+
+	sort.SliceStable(flagged, func(i, j int) bool {
+		a, b := flagged[i].ServiceInfo.MaxSeverity().Rank(), flagged[j].ServiceInfo.MaxSeverity().Rank()
+		if a != b {
+			return a > b
+		}
+		return scanner.Less(flagged[i], flagged[j])
+	})
+
+	fmt.Println("\nPorts by severity:")
+	for _, res := range flagged {
+		info := res.ServiceInfo
+		line := fmt.Sprintf("  [%s] %s:%s", info.MaxSeverity(), res.Host, res.PortLabel())
+		if summary := info.Summary(); summary != "" {
+			line += " " + summary
+		}
+		fmt.Printf("%s (vulnerabilities: %d, findings: %d)\n", line, len(info.Vulns), len(info.Findings))
+	}
+}
+
+// claves de host SSH repetidas entre hosts distintos
+func printSharedHostKeys(results []scanner.ScanResult) {
+	shared := SharedHostKeys(results)
+	if len(shared) == 0 {
+		return
+	}
+
+	fmt.Println("\nShared SSH host keys:")
+	for _, k := range shared {
+		var where []string
+		for _, r := range k.Results {
+			where = append(where, fmt.Sprintf("%s:%d", r.Host, r.Port))
+		}
+		fmt.Printf("  %s %s -> %s\n", k.Type, k.Fingerprint, strings.Join(where, ", "))
+	}
+}
+
+// hostnames descubiertos para el host (SANs de certificados, ...)
+func printHostnames(results []scanner.ScanResult) {
+	if len(results) == 0 || results[0].Metadata == nil {
+		return
+	}
+	if names := results[0].Metadata.Hostnames(); len(names) > 0 {
+		fmt.Printf("Hostnames: %s\n", strings.Join(names, ", "))
+	}
+	if wg := results[0].Metadata.Workgroup(); wg != "" {
+		fmt.Printf("Workgroup: %s\n", wg)
+	}
+}
+
+// los banners pueden ser multilinea, en la tabla solo va la primera
+func firstLine(s string) string {
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"go-scanner/internal/scanner"
+	"sort"
+)
+
+// clave de host SSH presente en mas de un host del reporte
+// (imagenes clonadas, appliances con claves de fabrica, un mismo equipo con varias IPs)
+type SharedHostKey struct {
+	Type        string
+	Fingerprint string
+	Results     []scanner.ResultKey //host/proto/port que presentan la clave
+}
+
+// agrupa las claves de host por fingerprint y retorna las que aparecen en mas de un host
+func SharedHostKeys(results []scanner.ScanResult) []SharedHostKey {
+	byFingerprint := make(map[string]*SharedHostKey)
+	hosts := make(map[string]map[string]bool)
+
+	for _, res := range results {
+		if res.ServiceInfo == nil || res.ServiceInfo.SSH == nil {
+			continue
+		}
+		for _, k := range res.ServiceInfo.SSH.HostKeys {
+			shared, ok := byFingerprint[k.Fingerprint]
+			if !ok {
+				shared = &SharedHostKey{Type: k.Type, Fingerprint: k.Fingerprint}
+				byFingerprint[k.Fingerprint] = shared
+				hosts[k.Fingerprint] = make(map[string]bool)
+			}
+			shared.Results = append(shared.Results, res.Key())
+			hosts[k.Fingerprint][res.Host] = true
+		}
+	}
+
+	var out []SharedHostKey
+	for fp, shared := range byFingerprint {
+		if len(hosts[fp]) < 2 {
+			continue
+		}
+		sort.Slice(shared.Results, func(i, j int) bool {
+			a, b := shared.Results[i], shared.Results[j]
+			if a.Host != b.Host {
+				return a.Host < b.Host
+			}
+			return a.Port < b.Port
+		})
+		out = append(out, *shared)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Fingerprint < out[j].Fingerprint })
+	return out
+}
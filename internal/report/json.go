@@ -0,0 +1,685 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-scanner/internal/app/scan"
+	"go-scanner/internal/scanner"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// documento JSON exportado, estable para comparar con escaneos anteriores
+type jsonReport struct {
+	JobID    string       `json:"job_id"`
+	Status   string       `json:"status"`
+	Metadata jsonMetadata `json:"metadata"`
+	Hosts    []jsonHost   `json:"hosts,omitempty"`
+	Shared   []jsonShared `json:"shared_host_keys,omitempty"`
+	Results  []jsonResult `json:"results"`
+	Errors   []jsonError  `json:"errors,omitempty"`
+}
+
+type jsonMetadata struct {
+	DurationMs  int64  `json:"duration_ms"`
+	TargetCount int    `json:"target_count"`
+	Profile     string `json:"profile"`
+	ScanType    string `json:"scan_type"`
+	Randomized  bool   `json:"randomized"`
+	Seed        int64  `json:"seed,omitempty"`
+}
+
+// cada resultado se identifica por (host, protocol, port)
+type jsonResult struct {
+	ID         string       `json:"id"` //host/proto/port
+	Host       string       `json:"host"`
+	Protocol   string       `json:"protocol"`
+	Port       int          `json:"port"`
+	State      string       `json:"state"`
+	Service    *jsonService `json:"service,omitempty"`
+	Banner     string       `json:"banner,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Confidence string       `json:"confidence,omitempty"` //confianza del discovery del host
+}
+
+// fingerprint estructurado del servicio
+type jsonService struct {
+	Name       string        `json:"name"`
+	Method     string        `json:"method,omitempty"`
+	Product    string        `json:"product,omitempty"`
+	Version    string        `json:"version,omitempty"`
+	ExtraInfo  string        `json:"extra_info,omitempty"`
+	OSHint     string        `json:"os,omitempty"`
+	DeviceType string        `json:"device_type,omitempty"`
+	CPE        []string      `json:"cpe,omitempty"`
+	TLS        bool          `json:"tls,omitempty"`
+	TLSInfo    *jsonTLS      `json:"tls_info,omitempty"`
+	StartTLS   *jsonStartTLS `json:"starttls,omitempty"`
+	JARM       string        `json:"jarm,omitempty"`
+	JA3S       string        `json:"ja3s,omitempty"`
+	Labels     []string      `json:"fingerprint_labels,omitempty"`
+	SSH        *jsonSSH      `json:"ssh,omitempty"`
+	HTTP       *jsonHTTP     `json:"http,omitempty"`
+	VHosts     []jsonVHost   `json:"vhosts,omitempty"`
+	Web        *jsonWeb      `json:"web,omitempty"`
+	Database   *jsonDatabase `json:"database,omitempty"`
+	SMB        *jsonSMB      `json:"smb,omitempty"`
+	NetBIOS    *jsonNetBIOS  `json:"netbios,omitempty"`
+	RDP        *jsonRDP      `json:"rdp,omitempty"`
+	VNC        *jsonVNC      `json:"vnc,omitempty"`
+	DNS        *jsonDNS      `json:"dns,omitempty"`
+	SNMP       *jsonSNMP     `json:"snmp,omitempty"`
+	NTP        *jsonNTP      `json:"ntp,omitempty"`
+	SIP        *jsonSIP      `json:"sip,omitempty"`
+	LDAP       *jsonLDAP     `json:"ldap,omitempty"`
+	Kerberos   *jsonKerberos `json:"kerberos,omitempty"`
+	MQTT       *jsonMQTT     `json:"mqtt,omitempty"`
+	AMQP       *jsonAMQP     `json:"amqp,omitempty"`
+	OT         *jsonOT       `json:"ot,omitempty"`
+	Findings   []jsonFinding `json:"findings,omitempty"`
+	Vulns      []jsonVuln    `json:"vulnerabilities,omitempty"`
+	Severity   string        `json:"max_severity,omitempty"` //la mas alta entre findings y vulnerabilities
+	Confidence string        `json:"confidence,omitempty"`
+}
+
+// clave de host SSH presente en varios hosts
+type jsonShared struct {
+	Type        string   `json:"type"`
+	Fingerprint string   `json:"fingerprint"`
+	Results     []string `json:"results"` //ids host/proto/port
+}
+
+// algoritmos y claves de host SSH
+type jsonSSH struct {
+	Banner            string        `json:"banner"`
+	KexAlgorithms     []string      `json:"kex"`
+	HostKeyAlgorithms []string      `json:"host_key_algorithms"`
+	Ciphers           []string      `json:"ciphers"`
+	MACs              []string      `json:"macs"`
+	Compression       []string      `json:"compression,omitempty"`
+	HostKeys          []jsonHostKey `json:"host_keys,omitempty"`
+	Weak              []string      `json:"weak,omitempty"`
+}
+
+type jsonHostKey struct {
+	Type        string `json:"type"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// analisis de la pagina web
+type jsonHTTP struct {
+	Status          int              `json:"status"`
+	Title           string           `json:"title,omitempty"`
+	FinalURL        string           `json:"final_url"`
+	Redirects       []string         `json:"redirects,omitempty"`
+	SecurityHeaders []string         `json:"security_headers,omitempty"`
+	MissingHeaders  []string         `json:"missing_security_headers,omitempty"`
+	Cookies         []jsonCookie     `json:"cookies,omitempty"`
+	FaviconHash     *int32           `json:"favicon_mmh3,omitempty"`
+	Technologies    []jsonTechnology `json:"technologies,omitempty"`
+}
+
+type jsonCookie struct {
+	Name     string `json:"name"`
+	Secure   bool   `json:"secure"`
+	HttpOnly bool   `json:"http_only"`
+	SameSite string `json:"same_site,omitempty"`
+}
+
+type jsonTechnology struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Version  string `json:"version,omitempty"`
+}
+
+// respuesta de un virtual host (host vacio = por defecto)
+type jsonVHost struct {
+	Host     string `json:"host"`
+	Status   int    `json:"status"`
+	Title    string `json:"title,omitempty"`
+	Location string `json:"location,omitempty"`
+	Length   int    `json:"length"`
+	Distinct bool   `json:"distinct"`
+}
+
+// protocolos y upgrades soportados
+type jsonWeb struct {
+	H2        bool     `json:"h2"`
+	H2C       bool     `json:"h2c"`
+	H3        bool     `json:"h3_advertised"`
+	AltSvc    []string `json:"alt_svc,omitempty"`
+	WebSocket []string `json:"websocket_paths,omitempty"`
+}
+
+// handshake de bases de datos
+type jsonDatabase struct {
+	Protocol     string `json:"protocol,omitempty"`
+	AuthRequired bool   `json:"auth_required"`
+	AuthMethod   string `json:"auth_method,omitempty"`
+	Encryption   string `json:"encryption,omitempty"`
+}
+
+// NEGOTIATE SMB y challenge NTLMSSP
+type jsonSMB struct {
+	Dialects        []string  `json:"dialects,omitempty"`
+	SMB1            bool      `json:"smb1"`
+	SigningEnabled  bool      `json:"signing_enabled"`
+	SigningRequired bool      `json:"signing_required"`
+	ServerGUID      string    `json:"server_guid,omitempty"`
+	NTLM            *jsonNTLM `json:"ntlm,omitempty"`
+}
+
+type jsonNTLM struct {
+	NetBIOSComputer string `json:"netbios_computer,omitempty"`
+	NetBIOSDomain   string `json:"netbios_domain,omitempty"`
+	DNSComputer     string `json:"dns_computer,omitempty"`
+	DNSDomain       string `json:"dns_domain,omitempty"`
+	DNSTree         string `json:"dns_tree,omitempty"`
+	OSVersion       string `json:"os_version,omitempty"`
+}
+
+// negociacion de seguridad RDP
+type jsonRDP struct {
+	Protocols   []string  `json:"protocols,omitempty"`
+	NLARequired bool      `json:"nla_required"`
+	NTLM        *jsonNTLM `json:"ntlm,omitempty"`
+}
+
+// handshake RFB
+type jsonVNC struct {
+	Version       string   `json:"version"`
+	SecurityTypes []string `json:"security_types,omitempty"`
+	NoAuth        bool     `json:"no_auth"`
+}
+
+// consultas DNS de solo lectura
+type jsonDNS struct {
+	Transport     string             `json:"transport"`
+	VersionBind   string             `json:"version_bind,omitempty"`
+	IDServer      string             `json:"id_server,omitempty"`
+	Recursion     bool               `json:"recursion"`
+	ZoneTransfers []jsonZoneTransfer `json:"zone_transfers,omitempty"`
+}
+
+type jsonZoneTransfer struct {
+	Zone    string `json:"zone"`
+	Allowed bool   `json:"allowed"`
+	Records int    `json:"records,omitempty"`
+}
+
+// grupo system y engine SNMPv3
+type jsonSNMP struct {
+	Community    string `json:"community,omitempty"`
+	Version      string `json:"version,omitempty"`
+	SysDescr     string `json:"sys_descr,omitempty"`
+	SysObjectID  string `json:"sys_object_id,omitempty"`
+	SysUpTime    int64  `json:"sys_uptime_ticks,omitempty"` //centesimas de segundo
+	SysContact   string `json:"sys_contact,omitempty"`
+	SysName      string `json:"sys_name,omitempty"`
+	EngineID     string `json:"engine_id,omitempty"`
+	EngineVendor string `json:"engine_vendor,omitempty"`
+	EngineBoots  int64  `json:"engine_boots,omitempty"`
+	EngineTime   int64  `json:"engine_time,omitempty"`
+}
+
+type jsonNTP struct {
+	Version int    `json:"version,omitempty"`
+	Stratum int    `json:"stratum"`
+	RefID   string `json:"refid,omitempty"`
+	System  string `json:"system,omitempty"`
+	Daemon  string `json:"daemon,omitempty"`
+	Monlist bool   `json:"monlist"`
+}
+
+type jsonSIP struct {
+	Status string   `json:"status"`
+	Server string   `json:"server,omitempty"`
+	Allow  []string `json:"allow,omitempty"`
+}
+
+// atributos del rootDSE
+type jsonLDAP struct {
+	NamingContexts       []string `json:"naming_contexts,omitempty"`
+	DefaultNamingContext string   `json:"default_naming_context,omitempty"`
+	DNSHostName          string   `json:"dns_host_name,omitempty"`
+	VendorName           string   `json:"vendor_name,omitempty"`
+	VendorVersion        string   `json:"vendor_version,omitempty"`
+	LDAPVersions         []string `json:"ldap_versions,omitempty"`
+	SASLMechanisms       []string `json:"sasl_mechanisms,omitempty"`
+}
+
+type jsonKerberos struct {
+	Realm      string `json:"realm,omitempty"`
+	ErrorCode  int    `json:"error_code"`
+	ErrorName  string `json:"error_name,omitempty"`
+	ServerTime string `json:"server_time,omitempty"`
+}
+
+type jsonMQTT struct {
+	ProtocolLevel int  `json:"protocol_level"`
+	ReturnCode    int  `json:"return_code"`
+	Anonymous     bool `json:"anonymous"`
+}
+
+type jsonAMQP struct {
+	Protocol   string   `json:"protocol"`
+	Platform   string   `json:"platform,omitempty"`
+	Cluster    string   `json:"cluster,omitempty"`
+	Mechanisms []string `json:"mechanisms,omitempty"`
+}
+
+// identidad de un dispositivo industrial
+type jsonOT struct {
+	Protocol   string `json:"protocol"`
+	Vendor     string `json:"vendor,omitempty"`
+	VendorID   int    `json:"vendor_id,omitempty"`
+	Product    string `json:"product,omitempty"`
+	Model      string `json:"model,omitempty"`
+	Revision   string `json:"revision,omitempty"`
+	Serial     string `json:"serial,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Address    string `json:"address,omitempty"`
+	DeviceType string `json:"device_type,omitempty"`
+}
+
+// hallazgo de un template
+type jsonFinding struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Source      string   `json:"source,omitempty"`
+	Matched     string   `json:"matched,omitempty"`
+	Extracted   []string `json:"extracted,omitempty"`
+}
+
+// CVE del feed local
+type jsonVuln struct {
+	ID       string  `json:"id"`
+	CVSS     float64 `json:"cvss,omitempty"`
+	Severity string  `json:"severity"`
+	Summary  string  `json:"summary,omitempty"`
+	CPE      string  `json:"cpe"`
+}
+
+// tabla de nombres NetBIOS
+type jsonNetBIOS struct {
+	Names     []jsonNetBIOSName `json:"names,omitempty"`
+	Workgroup string            `json:"workgroup,omitempty"`
+	MAC       string            `json:"mac,omitempty"`
+}
+
+type jsonNetBIOSName struct {
+	Name   string `json:"name"`
+	Suffix string `json:"suffix"` //hexadecimal ("20")
+	Group  bool   `json:"group"`
+}
+
+// datos por host que no dependen del puerto
+type jsonHost struct {
+	Host      string   `json:"host"`
+	Hostnames []string `json:"hostnames,omitempty"`
+	Workgroup string   `json:"workgroup,omitempty"`
+}
+
+// handshake TLS y certificado
+type jsonTLS struct {
+	Version     string          `json:"version"`
+	Cipher      string          `json:"cipher"`
+	ALPN        string          `json:"alpn,omitempty"`
+	Versions    map[string]bool `json:"versions,omitempty"` //version -> aceptada
+	Deprecated  []string        `json:"deprecated,omitempty"`
+	Certificate *jsonCert       `json:"certificate,omitempty"`
+}
+
+// upgrade desde texto plano
+type jsonStartTLS struct {
+	Command  string `json:"command"`
+	Offered  bool   `json:"offered"`
+	Required bool   `json:"required"`
+}
+
+type jsonCert struct {
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	SANs       []string  `json:"sans,omitempty"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	KeyType    string    `json:"key_type"`
+	KeyBits    int       `json:"key_bits,omitempty"`
+	SelfSigned bool      `json:"self_signed"`
+	Expired    bool      `json:"expired"`
+	SHA256     string    `json:"sha256"`
+}
+
+type jsonError struct {
+	Phase  string `json:"phase"`
+	Target string `json:"target,omitempty"`
+	Error  string `json:"error"`
+}
+
+// escribe el reporte completo en formato JSON
+func WriteJSON(w io.Writer, r *scan.ScanReport) error {
+	results := make([]scanner.ScanResult, len(r.Results))
+	copy(results, r.Results)
+	sort.SliceStable(results, func(i, j int) bool {
+		return scanner.Less(results[i], results[j])
+	})
+
+	doc := jsonReport{
+		JobID:  r.JobID,
+		Status: string(r.Status),
+		Metadata: jsonMetadata{
+			DurationMs:  r.Metadata.Duration.Milliseconds(),
+			TargetCount: r.Metadata.TargetCount,
+			Profile:     r.Metadata.ProfileUsed,
+			ScanType:    r.Metadata.ScanType,
+			Randomized:  r.Metadata.Randomized,
+			Seed:        r.Metadata.Seed,
+		},
+		Results: make([]jsonResult, 0, len(results)),
+	}
+
+	seenHosts := make(map[string]bool)
+	for _, res := range results {
+		if !seenHosts[res.Host] && res.Metadata != nil {
+			seenHosts[res.Host] = true
+			names, wg := res.Metadata.Hostnames(), res.Metadata.Workgroup()
+			if len(names) > 0 || wg != "" {
+				doc.Hosts = append(doc.Hosts, jsonHost{Host: res.Host, Hostnames: names, Workgroup: wg})
+			}
+		}
+
+		jr := jsonResult{
+			ID:       fmt.Sprintf("%s/%s/%d", res.Host, res.Protocol, res.Port),
+			Host:     res.Host,
+			Protocol: string(res.Protocol),
+			Port:     res.Port,
+			State:    string(res.State),
+			Service:  toJSONService(res),
+			Banner:   res.Banner,
+		}
+		if res.Error != nil {
+			jr.Error = res.Error.Error()
+		}
+		if res.Metadata != nil {
+			jr.Confidence = string(res.Metadata.Confidence)
+		}
+		doc.Results = append(doc.Results, jr)
+	}
+
+	for _, k := range SharedHostKeys(results) {
+		shared := jsonShared{Type: k.Type, Fingerprint: k.Fingerprint}
+		for _, key := range k.Results {
+			shared.Results = append(shared.Results, fmt.Sprintf("%s/%s/%d", key.Host, key.Protocol, key.Port))
+		}
+		doc.Shared = append(doc.Shared, shared)
+	}
+
+	for _, e := range r.Errors {
+		doc.Errors = append(doc.Errors, jsonError{
+			Phase:  e.Phase,
+			Target: e.Target,
+			Error:  e.Error(),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// escribe el reporte JSON en un archivo ("-" para stdout)
+func SaveJSON(path string, r *scan.ScanReport) error {
+	if path == "-" {
+		return WriteJSON(os.Stdout, r)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer f.Close()
+
+	return WriteJSON(f, r)
+}
+
+func toJSONService(res scanner.ScanResult) *jsonService {
+	info := res.ServiceInfo
+	if info == nil {
+		if res.Service == "" {
+			return nil
+		}
+		return &jsonService{Name: res.Service}
+	}
+
+	out := &jsonService{
+		Name:       string(info.Type),
+		Method:     string(info.Method),
+		Product:    info.Product,
+		Version:    info.Version,
+		ExtraInfo:  info.ExtraInfo,
+		OSHint:     info.OSHint,
+		DeviceType: info.DeviceType,
+		CPE:        info.CPE,
+		TLS:        info.TLS,
+		TLSInfo:    toJSONTLS(info.TLSInfo),
+		StartTLS:   toJSONStartTLS(info.StartTLS),
+		Confidence: string(info.Confidence),
+	}
+	if fp := info.TLSFingerprint; fp != nil {
+		out.JARM, out.JA3S, out.Labels = fp.JARM, fp.JA3S, fp.Labels
+	}
+	if s := info.SSH; s != nil {
+		out.SSH = &jsonSSH{
+			Banner:            s.Banner,
+			KexAlgorithms:     s.KexAlgorithms,
+			HostKeyAlgorithms: s.HostKeyAlgorithms,
+			Ciphers:           s.Ciphers,
+			MACs:              s.MACs,
+			Compression:       s.Compression,
+			Weak:              s.Weak,
+		}
+		for _, k := range s.HostKeys {
+			out.SSH.HostKeys = append(out.SSH.HostKeys, jsonHostKey{Type: k.Type, Fingerprint: k.Fingerprint})
+		}
+	}
+	out.HTTP = toJSONHTTP(info.HTTP)
+	if w := info.Web; w != nil {
+		out.Web = &jsonWeb{H2: w.H2, H2C: w.H2C, H3: w.H3, AltSvc: w.AltSvc, WebSocket: w.WebSocket}
+	}
+	if d := info.Database; d != nil {
+		out.Database = &jsonDatabase{Protocol: d.Protocol, AuthRequired: d.AuthRequired, AuthMethod: d.AuthMethod, Encryption: d.Encryption}
+	}
+	if s := info.SMB; s != nil {
+		out.SMB = &jsonSMB{
+			Dialects:        s.Dialects,
+			SMB1:            s.SMB1,
+			SigningEnabled:  s.SigningEnabled,
+			SigningRequired: s.SigningRequired,
+			ServerGUID:      s.ServerGUID,
+		}
+		out.SMB.NTLM = toJSONNTLM(s.NTLM)
+	}
+	if r := info.RDP; r != nil {
+		out.RDP = &jsonRDP{Protocols: r.Protocols, NLARequired: r.NLARequired, NTLM: toJSONNTLM(r.NTLM)}
+	}
+	if v := info.VNC; v != nil {
+		out.VNC = &jsonVNC{Version: v.Version, SecurityTypes: v.SecurityTypes, NoAuth: v.NoAuth}
+	}
+	if s := info.SNMP; s != nil {
+		out.SNMP = &jsonSNMP{
+			Community:    s.Community,
+			Version:      s.Version,
+			SysDescr:     s.SysDescr,
+			SysObjectID:  s.SysObjectID,
+			SysUpTime:    s.SysUpTime,
+			SysContact:   s.SysContact,
+			SysName:      s.SysName,
+			EngineID:     s.EngineID,
+			EngineVendor: s.EngineVendor,
+			EngineBoots:  s.EngineBoots,
+			EngineTime:   s.EngineTime,
+		}
+	}
+	if n := info.NTP; n != nil {
+		out.NTP = &jsonNTP{Version: n.Version, Stratum: n.Stratum, RefID: n.RefID, System: n.System, Daemon: n.Daemon, Monlist: n.Monlist}
+	}
+	if s := info.SIP; s != nil {
+		out.SIP = &jsonSIP{Status: s.Status, Server: s.Server, Allow: s.Allow}
+	}
+	if l := info.LDAP; l != nil {
+		out.LDAP = &jsonLDAP{
+			NamingContexts:       l.NamingContexts,
+			DefaultNamingContext: l.DefaultNamingContext,
+			DNSHostName:          l.DNSHostName,
+			VendorName:           l.VendorName,
+			VendorVersion:        l.VendorVersion,
+			LDAPVersions:         l.LDAPVersions,
+			SASLMechanisms:       l.SASLMechanisms,
+		}
+	}
+	if k := info.Kerberos; k != nil {
+		out.Kerberos = &jsonKerberos{Realm: k.Realm, ErrorCode: k.ErrorCode, ErrorName: k.ErrorName, ServerTime: k.ServerTime}
+	}
+	if m := info.MQTT; m != nil {
+		out.MQTT = &jsonMQTT{ProtocolLevel: m.ProtocolLevel, ReturnCode: m.ReturnCode, Anonymous: m.Anonymous}
+	}
+	if a := info.AMQP; a != nil {
+		out.AMQP = &jsonAMQP{Protocol: a.Protocol, Platform: a.Platform, Cluster: a.Cluster, Mechanisms: a.Mechanisms}
+	}
+	if o := info.OT; o != nil {
+		out.OT = &jsonOT{
+			Protocol:   o.Protocol,
+			Vendor:     o.Vendor,
+			VendorID:   o.VendorID,
+			Product:    o.Product,
+			Model:      o.Model,
+			Revision:   o.Revision,
+			Serial:     o.Serial,
+			Name:       o.Name,
+			Address:    o.Address,
+			DeviceType: o.DeviceType,
+		}
+	}
+	if d := info.DNS; d != nil {
+		out.DNS = &jsonDNS{Transport: d.Transport, VersionBind: d.VersionBind, IDServer: d.IDServer, Recursion: d.Recursion}
+		for _, z := range d.ZoneTransfers {
+			out.DNS.ZoneTransfers = append(out.DNS.ZoneTransfers, jsonZoneTransfer{Zone: z.Zone, Allowed: z.Allowed, Records: z.Records})
+		}
+	}
+	if nb := info.NetBIOS; nb != nil {
+		out.NetBIOS = &jsonNetBIOS{Workgroup: nb.Workgroup, MAC: nb.MAC}
+		for _, n := range nb.Names {
+			out.NetBIOS.Names = append(out.NetBIOS.Names, jsonNetBIOSName{Name: n.Name, Suffix: fmt.Sprintf("%02x", n.Suffix), Group: n.Group})
+		}
+	}
+	for _, f := range sortedFindings(info.Findings) {
+		out.Findings = append(out.Findings, jsonFinding{
+			ID:          f.ID,
+			Name:        f.Name,
+			Severity:    string(f.Severity),
+			Description: f.Description,
+			Tags:        f.Tags,
+			Source:      f.Source,
+			Matched:     f.Matched,
+			Extracted:   f.Extracted,
+		})
+	}
+	for _, v := range sortedVulns(info.Vulns) {
+		out.Vulns = append(out.Vulns, jsonVuln{ID: v.ID, CVSS: v.CVSS, Severity: string(v.Severity), Summary: v.Summary, CPE: v.CPE})
+	}
+	out.Severity = string(info.MaxSeverity())
+	for _, v := range info.VHosts {
+		out.VHosts = append(out.VHosts, jsonVHost{
+			Host:     v.Host,
+			Status:   v.Status,
+			Title:    v.Title,
+			Location: v.Location,
+			Length:   v.Length,
+			Distinct: v.Distinct,
+		})
+	}
+	return out
+}
+
+func toJSONNTLM(n *service.NTLMInfo) *jsonNTLM {
+	if n == nil {
+		return nil
+	}
+	return &jsonNTLM{
+		NetBIOSComputer: n.NetBIOSComputer,
+		NetBIOSDomain:   n.NetBIOSDomain,
+		DNSComputer:     n.DNSComputer,
+		DNSDomain:       n.DNSDomain,
+		DNSTree:         n.DNSTree,
+		OSVersion:       n.OSVersion,
+	}
+}
+
+func toJSONHTTP(h *service.HTTPInfo) *jsonHTTP {
+	if h == nil {
+		return nil
+	}
+
+	out := &jsonHTTP{
+		Status:          h.Status,
+		Title:           h.Title,
+		FinalURL:        h.FinalURL,
+		Redirects:       h.Redirects,
+		SecurityHeaders: h.SecurityHeaders,
+		MissingHeaders:  h.MissingHeaders,
+		FaviconHash:     h.FaviconHash,
+	}
+	for _, c := range h.Cookies {
+		out.Cookies = append(out.Cookies, jsonCookie{Name: c.Name, Secure: c.Secure, HttpOnly: c.HttpOnly, SameSite: c.SameSite})
+	}
+	for _, t := range h.Technologies {
+		out.Technologies = append(out.Technologies, jsonTechnology{Name: t.Name, Category: t.Category, Version: t.Version})
+	}
+	return out
+}
+
+func toJSONStartTLS(st *service.StartTLSInfo) *jsonStartTLS {
+	if st == nil {
+		return nil
+	}
+	return &jsonStartTLS{Command: st.Command, Offered: st.Offered, Required: st.Required}
+}
+
+func toJSONTLS(t *service.TLSInfo) *jsonTLS {
+	if t == nil {
+		return nil
+	}
+
+	out := &jsonTLS{
+		Version:    t.Version,
+		Cipher:     t.Cipher,
+		ALPN:       t.ALPN,
+		Versions:   make(map[string]bool, len(t.Versions)),
+		Deprecated: t.Deprecated(),
+	}
+	for _, v := range t.Versions {
+		out.Versions[v.Name] = v.Accepted
+	}
+
+	if c := t.Certificate; c != nil {
+		out.Certificate = &jsonCert{
+			Subject:    c.Subject,
+			Issuer:     c.Issuer,
+			SANs:       c.SANs,
+			NotBefore:  c.NotBefore,
+			NotAfter:   c.NotAfter,
+			KeyType:    c.KeyType,
+			KeyBits:    c.KeyBits,
+			SelfSigned: c.SelfSigned,
+			Expired:    c.Expired,
+			SHA256:     c.SHA256,
+		}
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package report
+
+//SEVERIDAD -> filtro de hallazgos y vulnerabilidades para los reportes
+import (
+	"go-scanner/internal/scanner"
+	"go-scanner/internal/scanner/service"
+)
+
+// copia de los resultados sin los hallazgos ni vulnerabilidades por debajo de min
+// los puertos se conservan aunque se queden sin ninguno; no modifica los originales
+func FilterSeverity(results []scanner.ScanResult, min service.Severity) []scanner.ScanResult {
+	out := make([]scanner.ScanResult, len(results))
+	copy(out, results)
+	if min.Rank() <= service.SeverityInfo.Rank() {
+		return out
+	}
+
+	for i, res := range out {
+		if res.ServiceInfo == nil || (len(res.ServiceInfo.Findings) == 0 && len(res.ServiceInfo.Vulns) == 0) {
+			continue
+		}
+		info := *res.ServiceInfo
+		info.Findings = nil
+		for _, f := range res.ServiceInfo.Findings {
+			if f.Severity.Rank() >= min.Rank() {
+				info.Findings = append(info.Findings, f)
+			}
+		}
+		info.Vulns = nil
+		for _, v := range res.ServiceInfo.Vulns {
+			if v.Severity.Rank() >= min.Rank() {
+				info.Vulns = append(info.Vulns, v)
+			}
+		}
+		out[i].ServiceInfo = &info
+	}
+	return out
+}
+
+// hallazgos de mayor a menor severidad, sin modificar el original
+func sortedFindings(list []service.Finding) []service.Finding {
+	out := append([]service.Finding(nil), list...)
+	service.SortFindings(out)
+	return out
+}
+
+// vulnerabilidades de mayor a menor severidad, sin modificar el original
+func sortedVulns(list []service.Vulnerability) []service.Vulnerability {
+	out := append([]service.Vulnerability(nil), list...)
+	service.SortVulnerabilities(out)
+	return out
+}
@@ -0,0 +1,146 @@
+package banner
+
+//NULL PROBE: banner no solicitado en cualquier puerto abierto
+import (
+	"bytes"
+	"go-scanner/internal/utils"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// payloads genericos enviados cuando el servicio no habla primero
+var (
+	genericLines = []byte("\r\n\r\n")
+	httpGet      = []byte("GET / HTTP/1.0\r\n\r\n")
+)
+
+// limite de bytes leidos por respuesta
+const maxBanner = 1024
+
+// valores por defecto cuando la policy no los define
+const (
+	DefaultWait   = 2 * time.Second
+	DefaultBudget = 60 * time.Second
+)
+
+// tiempo total de espera compartido por todos los puertos de la campaña
+// evita que cientos de puertos silenciosos multipliquen la duracion del escaneo
+type Budget struct {
+	mu        sync.Mutex
+	remaining time.Duration
+}
+
+// nuevo presupuesto, total <= 0 significa sin limite
+func NewBudget(total time.Duration) *Budget {
+	if total <= 0 {
+		return nil
+	}
+	return &Budget{remaining: total}
+}
+
+// espera concedida para un puerto: hasta want, sin pasar de lo que queda (0 = agotado)
+// no reserva nada: los puertos concurrentes no se quitan presupuesto entre si
+func (b *Budget) Take(want time.Duration) time.Duration {
+	if b == nil {
+		return want
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return min(want, max(b.remaining, 0))
+}
+
+// descuenta el tiempo que realmente se espero
+func (b *Budget) Charge(used time.Duration) {
+	if b == nil || used <= 0 {
+		return
+	}
+	b.mu.Lock()
+	b.remaining -= used
+	b.mu.Unlock()
+}
+
+// captura banners en cualquier puerto, independiente del numero de puerto
+type Grabber struct {
+	Wait     time.Duration //espera maxima por el banner no solicitado
+	Budget   *Budget       //presupuesto total compartido (nil = sin limite)
+	Fallback bool          //enviar probes genericos si el servicio no habla primero
+	Dialer   utils.Dialer  //conexiones de la campaña (nil = directo)
+}
+
+// nuevo grabber, el presupuesto se comparte entre todos los scanners que lo usen
+func NewGrabber(wait, budget time.Duration, fallback bool) *Grabber {
+	if wait <= 0 {
+		wait = DefaultWait
+	}
+	if budget <= 0 {
+		budget = DefaultBudget
+	}
+	return &Grabber{
+		Wait:     wait,
+		Budget:   NewBudget(budget),
+		Fallback: fallback,
+	}
+}
+
+// espera un banner en la conexion ya abierta, si no llega prueba los probes genericos
+// address se usa para abrir una conexion nueva para el GET (la original puede quedar cerrada)
+func (g *Grabber) Grab(conn net.Conn, address string) string {
+	if data := g.read(conn, nil); len(data) > 0 {
+		return clean(data)
+	}
+	if !g.Fallback {
+		return ""
+	}
+
+	//lineas vacias: muchos servicios de texto responden con un error o un prompt
+	if data := g.read(conn, genericLines); len(data) > 0 {
+		return clean(data)
+	}
+
+	//GET HTTP en una conexion nueva
+	timeout := g.Budget.Take(g.Wait)
+	if timeout == 0 {
+		return ""
+	}
+	start := time.Now()
+	fresh, err := utils.DialTimeout(g.Dialer, "tcp", address, timeout)
+	g.Budget.Charge(time.Since(start))
+	if err != nil {
+		return ""
+	}
+	defer fresh.Close()
+
+	return clean(g.read(fresh, httpGet))
+}
+
+// envia el payload (si hay) y lee la respuesta consumiendo del presupuesto
+func (g *Grabber) read(conn net.Conn, payload []byte) []byte {
+	wait := g.Budget.Take(g.Wait)
+	if wait == 0 {
+		return nil
+	}
+	start := time.Now()
+	defer func() { g.Budget.Charge(time.Since(start)) }()
+
+	if err := conn.SetDeadline(start.Add(wait)); err != nil {
+		return nil
+	}
+	if len(payload) > 0 {
+		if _, err := conn.Write(payload); err != nil {
+			return nil
+		}
+	}
+
+	buffer := make([]byte, maxBanner)
+	n, _ := conn.Read(buffer)
+	return buffer[:n]
+}
+
+// normaliza el banner: sin espacios en los extremos ni bytes nulos
+func clean(data []byte) string {
+	data = bytes.Trim(data, "\x00")
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,76 @@
+package banner
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(3 * time.Second)
+
+	//las esperas en curso no le quitan presupuesto a los demas puertos
+	var wg sync.WaitGroup
+	granted := make([]time.Duration, 50)
+	for i := range granted {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			granted[i] = b.Take(2 * time.Second)
+		}()
+	}
+	wg.Wait()
+	for i, g := range granted {
+		if g != 2*time.Second {
+			t.Fatalf("port %d granted %v, want 2s", i, g)
+		}
+	}
+
+	//solo se descuenta lo esperado
+	b.Charge(100 * time.Millisecond)
+	b.Charge(-time.Second)
+	if got := b.Take(time.Hour); got != 2900*time.Millisecond {
+		t.Errorf("Take after charge = %v, want 2.9s", got)
+	}
+
+	b.Charge(5 * time.Second)
+	if got := b.Take(time.Second); got != 0 {
+		t.Errorf("Take on spent budget = %v, want 0", got)
+	}
+
+	var unlimited *Budget
+	unlimited.Charge(time.Hour)
+	if got := unlimited.Take(time.Second); got != time.Second {
+		t.Errorf("nil budget Take = %v, want 1s", got)
+	}
+}
+
+func TestGrab(t *testing.T) {
+	g := NewGrabber(200*time.Millisecond, time.Minute, false)
+
+	//banner inmediato: casi no consume presupuesto
+	client, server := net.Pipe()
+	go func() {
+		server.Write([]byte("\x00SSH-2.0-OpenSSH_9.6\r\n"))
+		server.Close()
+	}()
+	if got := g.Grab(client, ""); got != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("Grab = %q", got)
+	}
+	client.Close()
+	if left := g.Budget.Take(time.Hour); left < time.Minute-100*time.Millisecond {
+		t.Errorf("budget after banner = %v", left)
+	}
+
+	//puerto silencioso: se descuenta la espera completa
+	silent, peer := net.Pipe()
+	defer peer.Close()
+	if got := g.Grab(silent, ""); got != "" {
+		t.Errorf("silent Grab = %q", got)
+	}
+	silent.Close()
+	if left := g.Budget.Take(time.Hour); left > time.Minute-200*time.Millisecond {
+		t.Errorf("budget after silent port = %v, want at most %v", left, time.Minute-200*time.Millisecond)
+	}
+}
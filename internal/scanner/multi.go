@@ -0,0 +1,41 @@
+package scanner
+
+import "sync"
+
+// ensure MultiScanner implements Scanner
+var _ Scanner = (*MultiScanner)(nil)
+
+// ejecuta varios scanners sobre el mismo host (ej. SYN + UDP)
+// y une sus resultados en un unico canal
+type MultiScanner struct {
+	Scanners []Scanner
+}
+
+// nueva instancia de MultiScanner
+func NewMultiScanner(scanners ...Scanner) *MultiScanner {
+	return &MultiScanner{
+		Scanners: scanners,
+	}
+}
+
+// lanza todos los scanners en paralelo y reenvia sus resultados
+func (m *MultiScanner) Scan(results chan<- ScanResult) {
+	defer close(results)
+
+	var wg sync.WaitGroup
+	for _, s := range m.Scanners {
+		ch := make(chan ScanResult)
+		wg.Add(1)
+
+		go s.Scan(ch) //cada scanner cierra su propio canal
+
+		go func() {
+			defer wg.Done()
+			for res := range ch {
+				results <- res
+			}
+		}()
+	}
+
+	wg.Wait()
+}
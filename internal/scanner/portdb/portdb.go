@@ -0,0 +1,142 @@
+package portdb
+
+//TABLA DE SERVICIOS POR PUERTO (embebida)
+import (
+	"bufio"
+	_ "embed"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/services.tsv
+var servicesData string
+
+// protocolos de transporte de la tabla
+const (
+	TCP = "tcp"
+	UDP = "udp"
+)
+
+// entrada de la tabla de servicios
+type Entry struct {
+	Name      string  //nombre del servicio (http, ssh, ...)
+	Port      int     //puerto
+	Proto     string  //tcp o udp
+	Frequency float64 //frecuencia relativa de aparicion
+}
+
+type key struct {
+	port  int
+	proto string
+}
+
+// indices construidos una sola vez a partir de la tabla
+var (
+	loadOnce sync.Once
+	byPort   map[key]Entry
+	byName   map[string][]Entry
+	ranked   map[string][]Entry //por protocolo, ordenado por frecuencia desc
+)
+
+func load() {
+	byPort = make(map[key]Entry)
+	byName = make(map[string][]Entry)
+	ranked = make(map[string][]Entry)
+
+	sc := bufio.NewScanner(strings.NewReader(servicesData))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		portProto := strings.SplitN(fields[1], "/", 2)
+		if len(portProto) != 2 {
+			continue
+		}
+		port, err := strconv.Atoi(portProto[0])
+		if err != nil {
+			continue
+		}
+		freq, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+
+		e := Entry{
+			Name:      strings.ToLower(fields[0]),
+			Port:      port,
+			Proto:     strings.ToLower(portProto[1]),
+			Frequency: freq,
+		}
+
+		k := key{e.Port, e.Proto}
+		if _, dup := byPort[k]; dup {
+			continue
+		}
+		byPort[k] = e
+		byName[e.Name] = append(byName[e.Name], e)
+		ranked[e.Proto] = append(ranked[e.Proto], e)
+	}
+
+	for proto := range ranked {
+		list := ranked[proto]
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Frequency > list[j].Frequency
+		})
+	}
+}
+
+// nombre del servicio asociado a un puerto/protocolo
+func Name(port int, proto string) (string, bool) {
+	loadOnce.Do(load)
+	e, ok := byPort[key{port, strings.ToLower(proto)}]
+	if !ok || e.Name == "unknown" {
+		return "", false
+	}
+	return e.Name, true
+}
+
+// puertos registrados bajo un nombre de servicio para un protocolo
+func Ports(name string, proto string) []int {
+	loadOnce.Do(load)
+	var ports []int
+	for _, e := range byName[strings.ToLower(name)] {
+		if e.Proto == strings.ToLower(proto) {
+			ports = append(ports, e.Port)
+		}
+	}
+	return ports
+}
+
+// cantidad de puertos de un protocolo ordenados por frecuencia (el maximo de Top)
+func Ranked(proto string) int {
+	loadOnce.Do(load)
+	return len(ranked[strings.ToLower(proto)])
+}
+
+// los N puertos mas frecuentes de un protocolo
+// si la tabla tiene menos de N entradas se retornan todas (ver Ranked)
+func Top(n int, proto string) []int {
+	loadOnce.Do(load)
+	list := ranked[strings.ToLower(proto)]
+	if n > len(list) {
+		n = len(list)
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	ports := make([]int, 0, n)
+	for _, e := range list[:n] {
+		ports = append(ports, e.Port)
+	}
+	return ports
+}
@@ -0,0 +1,238 @@
+package ber
+
+//BER -> codificacion y lectura minima de TLVs (SNMP, LDAP, Kerberos)
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// tags universales usados por los probers
+const (
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagOID         = 0x06
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x30
+	TagSet         = 0x31
+)
+
+// un TLV leido; Value apunta al buffer original
+type Element struct {
+	Tag   byte
+	Value []byte
+}
+
+// codifica un TLV con longitud corta o larga
+func TLV(tag byte, value []byte) []byte {
+	out := []byte{tag}
+	switch n := len(value); {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n <= 0xff:
+		out = append(out, 0x81, byte(n))
+	case n <= 0xffff:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x83, byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, value...)
+}
+
+// TLV construido con los hijos concatenados
+func Constructed(tag byte, children ...[]byte) []byte {
+	var value []byte
+	for _, c := range children {
+		value = append(value, c...)
+	}
+	return TLV(tag, value)
+}
+
+func Sequence(children ...[]byte) []byte {
+	return Constructed(TagSequence, children...)
+}
+
+// entero en complemento a dos con la minima cantidad de bytes
+func Integer(v int64) []byte {
+	return TLV(TagInteger, intBytes(v))
+}
+
+func Enumerated(v int64) []byte {
+	return TLV(TagEnumerated, intBytes(v))
+}
+
+func intBytes(v int64) []byte {
+	b := []byte{byte(v)}
+	for v > 127 || v < -128 {
+		v >>= 8
+		b = append([]byte{byte(v)}, b...)
+	}
+	return b
+}
+
+func OctetString(s []byte) []byte {
+	return TLV(TagOctetString, s)
+}
+
+func Null() []byte {
+	return []byte{TagNull, 0x00}
+}
+
+// OID en notacion de puntos ("1.3.6.1.2.1.1.1.0")
+func OID(oid string) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid oid %q", oid)
+	}
+	arcs := make([]uint64, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oid %q: %w", oid, err)
+		}
+		arcs[i] = n
+	}
+
+	value := base128(arcs[0]*40 + arcs[1])
+	for _, a := range arcs[2:] {
+		value = append(value, base128(a)...)
+	}
+	return TLV(TagOID, value), nil
+}
+
+func base128(v uint64) []byte {
+	b := []byte{byte(v & 0x7f)}
+	for v >>= 7; v > 0; v >>= 7 {
+		b = append([]byte{byte(v&0x7f) | 0x80}, b...)
+	}
+	return b
+}
+
+// lee el primer TLV y retorna el resto del buffer
+func Parse(data []byte) (Element, []byte, error) {
+	if len(data) < 2 {
+		return Element{}, nil, errors.New("short ber element")
+	}
+	if data[0]&0x1f == 0x1f {
+		return Element{}, nil, errors.New("unsupported multi-byte ber tag")
+	}
+
+	length, offset := int(data[1]), 2
+	if data[1]&0x80 != 0 {
+		n := int(data[1] & 0x7f)
+		if n == 0 || n > 4 || len(data) < 2+n {
+			return Element{}, nil, errors.New("unsupported ber length")
+		}
+		length = 0
+		for _, b := range data[2 : 2+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+	if length < 0 || offset+length > len(data) {
+		return Element{}, nil, errors.New("truncated ber element")
+	}
+	return Element{Tag: data[0], Value: data[offset : offset+length]}, data[offset+length:], nil
+}
+
+// TLVs contenidos en un elemento construido
+func (e Element) Children() ([]Element, error) {
+	var out []Element
+	for rest := e.Value; len(rest) > 0; {
+		var child Element
+		var err error
+		child, rest, err = Parse(rest)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, child)
+	}
+	return out, nil
+}
+
+// entero con signo (INTEGER, ENUMERATED y tipos de aplicacion como Counter o TimeTicks)
+func (e Element) Int() (int64, error) {
+	if len(e.Value) == 0 || len(e.Value) > 8 {
+		return 0, errors.New("invalid ber integer")
+	}
+	v := int64(int8(e.Value[0]))
+	for _, b := range e.Value[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v, nil
+}
+
+// entero sin signo (los tipos de aplicacion de SNMP no usan signo)
+func (e Element) Uint() (uint64, error) {
+	if len(e.Value) == 0 || len(e.Value) > 9 {
+		return 0, errors.New("invalid ber integer")
+	}
+	var v uint64
+	for _, b := range e.Value {
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+// OID en notacion de puntos
+func (e Element) OID() (string, error) {
+	if e.Tag != TagOID || len(e.Value) == 0 {
+		return "", errors.New("not a ber oid")
+	}
+	var arcs []string
+	var v uint64
+	for i, b := range e.Value {
+		v = v<<7 | uint64(b&0x7f)
+		if b&0x80 != 0 {
+			if i == len(e.Value)-1 {
+				return "", errors.New("truncated ber oid")
+			}
+			continue
+		}
+		if arcs == nil {
+			first := min(v/40, 2)
+			arcs = append(arcs, strconv.FormatUint(first, 10), strconv.FormatUint(v-first*40, 10))
+		} else {
+			arcs = append(arcs, strconv.FormatUint(v, 10))
+		}
+		v = 0
+	}
+	return strings.Join(arcs, "."), nil
+}
+
+// lee un TLV completo desde un stream (tag, largo y valor), limitado a max bytes
+func Read(r io.Reader, max int) ([]byte, error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, err
+	}
+
+	length := int(head[1])
+	if head[1]&0x80 != 0 {
+		n := int(head[1] & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errors.New("unsupported ber length")
+		}
+		extra := make([]byte, n)
+		if _, err := io.ReadFull(r, extra); err != nil {
+			return nil, err
+		}
+		head = append(head, extra...)
+		length = 0
+		for _, b := range extra {
+			length = length<<8 | int(b)
+		}
+	}
+	if length < 0 || length > max {
+		return nil, fmt.Errorf("ber element too large (%d bytes)", length)
+	}
+
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return append(head, value...), nil
+}
@@ -0,0 +1,236 @@
+package ber
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTLVRoundTrip(t *testing.T) {
+	tests := []struct {
+		size   int
+		header []byte //tag y longitud esperados
+	}{
+		{0, []byte{TagOctetString, 0x00}},
+		{1, []byte{TagOctetString, 0x01}},
+		{127, []byte{TagOctetString, 0x7f}},
+		{128, []byte{TagOctetString, 0x81, 0x80}},
+		{255, []byte{TagOctetString, 0x81, 0xff}},
+		{256, []byte{TagOctetString, 0x82, 0x01, 0x00}},
+		{65535, []byte{TagOctetString, 0x82, 0xff, 0xff}},
+		{65536, []byte{TagOctetString, 0x83, 0x01, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		value := bytes.Repeat([]byte{0xab}, tt.size)
+		encoded := OctetString(value)
+		if !bytes.HasPrefix(encoded, tt.header) || len(encoded) != len(tt.header)+tt.size {
+			t.Errorf("size %d: header % x, want % x", tt.size, encoded[:min(len(encoded), len(tt.header))], tt.header)
+			continue
+		}
+
+		//con datos de mas despues del elemento
+		e, rest, err := Parse(append(encoded, 0x05, 0x00))
+		if err != nil {
+			t.Errorf("size %d: Parse: %v", tt.size, err)
+			continue
+		}
+		if e.Tag != TagOctetString || !bytes.Equal(e.Value, value) || !bytes.Equal(rest, []byte{0x05, 0x00}) {
+			t.Errorf("size %d: Parse = tag %#x, %d bytes, rest % x", tt.size, e.Tag, len(e.Value), rest)
+		}
+
+		read, err := Read(bytes.NewReader(encoded), 1<<20)
+		if err != nil || !bytes.Equal(read, encoded) {
+			t.Errorf("size %d: Read = %d bytes, %v", tt.size, len(read), err)
+		}
+	}
+}
+
+func TestIntegerRoundTrip(t *testing.T) {
+	tests := []struct {
+		v    int64
+		want []byte
+	}{
+		{0, []byte{0x02, 0x01, 0x00}},
+		{127, []byte{0x02, 0x01, 0x7f}},
+		{128, []byte{0x02, 0x02, 0x00, 0x80}},
+		{256, []byte{0x02, 0x02, 0x01, 0x00}},
+		{-1, []byte{0x02, 0x01, 0xff}},
+		{-128, []byte{0x02, 0x01, 0x80}},
+		{-129, []byte{0x02, 0x02, 0xff, 0x7f}},
+		{math.MaxInt64, []byte{0x02, 0x08, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{math.MinInt64, []byte{0x02, 0x08, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		encoded := Integer(tt.v)
+		if !bytes.Equal(encoded, tt.want) {
+			t.Errorf("Integer(%d) = % x, want % x", tt.v, encoded, tt.want)
+		}
+		e, _, err := Parse(encoded)
+		if err != nil {
+			t.Errorf("Integer(%d): Parse: %v", tt.v, err)
+			continue
+		}
+		if got, err := e.Int(); err != nil || got != tt.v {
+			t.Errorf("Integer(%d): Int = %d, %v", tt.v, got, err)
+		}
+	}
+}
+
+func TestUint(t *testing.T) {
+	tests := []struct {
+		value []byte
+		want  uint64
+		ok    bool
+	}{
+		{[]byte{0x00}, 0, true},
+		{[]byte{0xff}, 255, true},
+		{[]byte{0x00, 0xff, 0xff, 0xff, 0xff}, math.MaxUint32, true},
+		{[]byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, math.MaxUint64, true},
+		{nil, 0, false},
+		{make([]byte, 10), 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := Element{Tag: 0x41, Value: tt.value}.Uint()
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("Uint(% x) = %d, %v, want %d (ok %v)", tt.value, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOIDRoundTrip(t *testing.T) {
+	tests := []struct {
+		oid  string
+		want []byte
+	}{
+		{"1.3.6.1.2.1.1.1.0", []byte{0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x01, 0x00}},
+		{"1.2.840.113549", []byte{0x06, 0x06, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d}},
+		{"2.999.3", []byte{0x06, 0x03, 0x88, 0x37, 0x03}},
+		{"0.0", []byte{0x06, 0x01, 0x00}},
+	}
+
+	for _, tt := range tests {
+		encoded, err := OID(tt.oid)
+		if err != nil || !bytes.Equal(encoded, tt.want) {
+			t.Errorf("OID(%q) = % x, %v, want % x", tt.oid, encoded, err, tt.want)
+			continue
+		}
+		e, _, _ := Parse(encoded)
+		if got, err := e.OID(); err != nil || got != tt.oid {
+			t.Errorf("OID(%q) round trip = %q, %v", tt.oid, got, err)
+		}
+	}
+
+	//con punto inicial
+	if encoded, err := OID(".1.3.6.1"); err != nil || !bytes.Equal(encoded, []byte{0x06, 0x03, 0x2b, 0x06, 0x01}) {
+		t.Errorf("OID(.1.3.6.1) = % x, %v", encoded, err)
+	}
+
+	for _, bad := range []string{"", "1", "1.x.3", "1.-3"} {
+		if _, err := OID(bad); err == nil {
+			t.Errorf("OID(%q) succeeded, want error", bad)
+		}
+	}
+
+	for _, bad := range []Element{
+		{Tag: TagOID},
+		{Tag: TagOID, Value: []byte{0x2b, 0x86}},
+		{Tag: TagOctetString, Value: []byte{0x2b}},
+	} {
+		if got, err := bad.OID(); err == nil {
+			t.Errorf("OID(% x) = %q, want error", bad.Value, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"tag only", []byte{0x04}},
+		{"multi-byte tag", []byte{0x1f, 0x01, 0x00}},
+		{"indefinite length", []byte{0x30, 0x80, 0x00, 0x00}},
+		{"length of 5 bytes", []byte{0x04, 0x85, 0x00, 0x00, 0x00, 0x00, 0x01, 0xaa}},
+		{"truncated length", []byte{0x04, 0x82, 0x01}},
+		{"truncated value", []byte{0x04, 0x05, 0x01, 0x02}},
+		{"long length beyond data", []byte{0x04, 0x82, 0x01, 0x00, 0xaa}},
+		{"huge length", []byte{0x04, 0x84, 0xff, 0xff, 0xff, 0xff, 0xaa}},
+	}
+
+	for _, tt := range tests {
+		if e, _, err := Parse(tt.data); err == nil {
+			t.Errorf("%s: Parse = %+v, want error", tt.name, e)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	msg := Sequence(
+		Integer(1),
+		OctetString([]byte("public")),
+		Constructed(0xa0, Integer(42), Null()),
+	)
+	e, rest, err := Parse(msg)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("Parse: %v, rest % x", err, rest)
+	}
+
+	children, err := e.Children()
+	if err != nil || len(children) != 3 {
+		t.Fatalf("Children = %d, %v", len(children), err)
+	}
+	if string(children[1].Value) != "public" {
+		t.Errorf("community = %q", children[1].Value)
+	}
+	inner, err := children[2].Children()
+	if err != nil || len(inner) != 2 || inner[1].Tag != TagNull {
+		t.Fatalf("inner Children = %+v, %v", inner, err)
+	}
+	if v, _ := inner[0].Int(); v != 42 {
+		t.Errorf("inner integer = %d", v)
+	}
+
+	//un hijo truncado invalida el elemento
+	broken := Element{Tag: TagSequence, Value: []byte{0x02, 0x01, 0x01, 0x04, 0x05, 'a'}}
+	if _, err := broken.Children(); err == nil {
+		t.Error("Children of truncated child succeeded, want error")
+	}
+}
+
+func TestReadLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		max  int
+	}{
+		{"over max", OctetString(bytes.Repeat([]byte{1}, 300)), 256},
+		{"huge declared length", []byte{0x30, 0x84, 0x7f, 0xff, 0xff, 0xff}, 1 << 20},
+		{"length of 5 bytes", []byte{0x30, 0x85, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00}, 1 << 20},
+		{"indefinite length", []byte{0x30, 0x80}, 1 << 20},
+		{"truncated value", []byte{0x30, 0x05, 0x01}, 1 << 20},
+		{"truncated length", []byte{0x30, 0x82, 0x01}, 1 << 20},
+		{"empty", nil, 1 << 20},
+	}
+
+	for _, tt := range tests {
+		if got, err := Read(bytes.NewReader(tt.data), tt.max); err == nil {
+			t.Errorf("%s: Read = % x, want error", tt.name, got)
+		}
+	}
+
+	//lee solo el primer elemento del stream
+	r := strings.NewReader(string(Integer(7)) + string(Null()))
+	first, err := Read(r, 16)
+	if err != nil || !bytes.Equal(first, Integer(7)) {
+		t.Fatalf("Read first = % x, %v", first, err)
+	}
+	if rest, _ := io.ReadAll(r); !bytes.Equal(rest, Null()) {
+		t.Errorf("rest of stream = % x", rest)
+	}
+}
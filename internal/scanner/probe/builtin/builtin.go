@@ -0,0 +1,87 @@
+package builtin
+
+//CATALOGO DE PROBERS INCLUIDOS -> registro explicito, el orden es el de ejecucion sobre un puerto
+import (
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/database"
+	"go-scanner/internal/scanner/probe/dnsprobe"
+	"go-scanner/internal/scanner/probe/http"
+	"go-scanner/internal/scanner/probe/infra"
+	"go-scanner/internal/scanner/probe/jarm"
+	"go-scanner/internal/scanner/probe/ot"
+	"go-scanner/internal/scanner/probe/remote"
+	"go-scanner/internal/scanner/probe/smb"
+	"go-scanner/internal/scanner/probe/snmp"
+	"go-scanner/internal/scanner/probe/sshprobe"
+	"go-scanner/internal/scanner/probe/starttls"
+	"go-scanner/internal/scanner/probe/tlsprobe"
+)
+
+// registro con todos los probers incluidos
+// cada llamada retorna un registro nuevo, se le pueden agregar probers propios
+func Default() *probe.Registry {
+	r := probe.NewRegistry()
+
+	//tls no depende del servicio, se intenta en cualquier puerto TCP abierto
+	//lo que identifica (HTTPS por ALPN, certificado) habilita a los que lo requieren
+	r.Register(tlsprobe.NewTLSProbe())
+	r.Register(jarm.NewJARMProbe()) //solo sobre puertos donde se detecto TLS
+
+	//web: request ligero, luego analisis y capacidades (opt-in)
+	r.Register(http.NewHTTPProbe())
+	r.Register(http.NewHTTPAnalysisProbe())
+	r.Register(http.NewCapabilitiesProbe())
+
+	r.Register(sshprobe.NewSSHProbe())
+
+	//dns por ambos transportes (AXFR siempre por TCP)
+	r.Register(dnsprobe.NewDNSProbe("udp"))
+	r.Register(dnsprobe.NewDNSProbe("tcp"))
+
+	//snmp: solo GET del grupo system y descubrimiento del engine v3
+	r.Register(snmp.NewSNMPProbe())
+
+	//protocolos en claro con upgrade a TLS
+	r.Register(starttls.NewSMTPProbe())
+	r.Register(starttls.NewIMAPProbe())
+	r.Register(starttls.NewPOP3Probe())
+	r.Register(starttls.NewFTPProbe())
+	//ldap: rootDSE anonimo y su STARTTLS, ambos se ejecutan
+	r.Register(probe.Multi(infra.NewLDAPProbe(), starttls.NewLDAPProbe()))
+
+	//bases de datos: solo el handshake inicial, nunca credenciales
+	//postgresql comparte nombre con su STARTTLS, ambos se ejecutan
+	r.Register(probe.Multi(database.NewPostgresProbe(), starttls.NewPostgresProbe()))
+	r.Register(database.NewMySQLProbe())
+	r.Register(database.NewMSSQLProbe())
+	r.Register(database.NewMongoDBProbe())
+	r.Register(database.NewRedisProbe())
+
+	//redes Windows: NEGOTIATE + challenge NTLMSSP anonimo y tabla de nombres NetBIOS
+	r.Register(smb.NewSMBProbe())
+	r.Register(smb.NewNetBIOSProbe())
+
+	//escritorio remoto: protocolos de seguridad ofrecidos, sin autenticar
+	r.Register(remote.NewRDPProbe())
+	r.Register(remote.NewVNCProbe())
+
+	//infraestructura: consultas de identificacion, sin autenticar ni modificar estado
+	r.Register(infra.NewNTPProbe())
+	r.Register(infra.NewSIPProbe("udp"))
+	r.Register(infra.NewSIPProbe("tcp"))
+	r.Register(infra.NewKerberosProbe("udp"))
+	r.Register(infra.NewKerberosProbe("tcp"))
+	r.Register(infra.NewMQTTProbe())
+	r.Register(infra.NewAMQPProbe())
+
+	//industriales (OT): solo identificacion, nunca escritura ni cambios de modo (ver paquete ot)
+	//explicitos, "all" no los incluye
+	r.Register(ot.NewModbusProbe())
+	r.Register(ot.NewS7Probe())
+	r.Register(ot.NewBACnetProbe())
+	r.Register(ot.NewDNP3Probe())
+	r.Register(ot.NewEtherNetIPProbe("tcp"))
+	r.Register(ot.NewEtherNetIPProbe("udp"))
+
+	return r
+}
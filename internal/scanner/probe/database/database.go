@@ -0,0 +1,46 @@
+package database
+
+//PROBERS DE BASES DE DATOS -> handshake inicial de solo lectura, nunca se envian credenciales
+import (
+	"context"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+)
+
+// conexion con deadline para todo el dialogo
+func dial(ctx context.Context, t probe.Target, opts probe.Options) (net.Conn, error) {
+	return opts.Dial(ctx, "tcp", t.Address())
+}
+
+// el servicio identificado o su puerto habitual, en el grupo "database"
+func spec(name string, svc service.ServiceType, ports ...int) probe.Spec {
+	return probe.Spec{
+		Name:     name,
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{svc},
+		Ports:    ports,
+		Groups:   []string{"database"},
+	}
+}
+
+// fingerprint base de un servidor identificado por su protocolo
+func newInfo(svc service.ServiceType, product string, details *service.DatabaseInfo) *service.ServiceInfo {
+	return &service.ServiceInfo{
+		Type:       svc,
+		Method:     service.MethodProbe,
+		Product:    product,
+		Confidence: model.ConfidenceHigh,
+		Database:   details,
+	}
+}
+
+// agrega el CPE con la version conocida
+func addCPE(info *service.ServiceInfo, vendorProduct string) {
+	c := "cpe:/a:" + vendorProduct
+	if info.Version != "" {
+		c += ":" + info.Version
+	}
+	info.CPE = append(info.CPE, c)
+}
@@ -0,0 +1,273 @@
+package database
+
+//MONGODB -> isMaster, buildInfo y listDatabases (solo lectura, sin autenticar)
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"math"
+	"net"
+	"strconv"
+)
+
+// opcodes del wire protocol
+const (
+	opReply = 1
+	opQuery = 2004
+	opMsg   = 2013
+)
+
+// wire version desde la cual existe OP_MSG (3.6)
+const wireOpMsg = 6
+
+// codigo de error de comando sin autenticar
+const mongoUnauthorized = 13
+
+type MongoDBProbe struct{}
+
+func NewMongoDBProbe() *MongoDBProbe {
+	return &MongoDBProbe{}
+}
+
+func (p *MongoDBProbe) Spec() probe.Spec {
+	return spec("mongodb", service.ServiceMongoDB, 27017)
+}
+
+func (p *MongoDBProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	m := &mongoConn{conn: conn}
+
+	//isMaster por OP_QUERY lo aceptan todas las versiones (es el handshake de los drivers)
+	hello, err := m.command("isMaster", false)
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := hello["ismaster"].(bool); !ok && hello["maxWireVersion"] == nil {
+		return nil, errors.New("not a mongodb isMaster reply")
+	}
+
+	wire, _ := hello["maxWireVersion"].(int32)
+	details := &service.DatabaseInfo{Protocol: "wire " + strconv.Itoa(int(wire))}
+	info := newInfo(service.ServiceMongoDB, "MongoDB", details)
+	if msg, _ := hello["msg"].(string); msg == "isdbgrid" {
+		info.ExtraInfo = "mongos router"
+	} else if set, _ := hello["setName"].(string); set != "" {
+		info.ExtraInfo = "replica set " + set
+	}
+
+	opMsg := wire >= wireOpMsg
+	if build, err := m.command("buildInfo", opMsg); err == nil {
+		info.Version, _ = build["version"].(string)
+	}
+	addCPE(info, "mongodb:mongodb")
+
+	//listDatabases sin sesion: Unauthorized si el control de acceso esta activo
+	if list, err := m.command("listDatabases", opMsg); err == nil {
+		ok, _ := list["ok"].(float64)
+		code, _ := list["code"].(int32)
+		details.AuthRequired = ok != 1 && code == mongoUnauthorized
+	}
+	return info, nil
+}
+
+type mongoConn struct {
+	conn      net.Conn
+	requestID int32
+}
+
+// ejecuta {cmd: 1} sobre admin y retorna el documento de respuesta
+func (m *mongoConn) command(cmd string, useOpMsg bool) (map[string]any, error) {
+	m.requestID++
+
+	var op int32
+	var body []byte
+	if useOpMsg {
+		op = opMsg
+		body = binary.LittleEndian.AppendUint32(nil, 0) //flagBits
+		body = append(body, 0)                          //seccion de cuerpo
+		body = append(body, bsonDoc(cmd, int32(1), "$db", "admin")...)
+	} else {
+		op = opQuery
+		body = binary.LittleEndian.AppendUint32(nil, 0) //flags
+		body = append(body, "admin.$cmd\x00"...)
+		body = binary.LittleEndian.AppendUint32(body, 0)          //numberToSkip
+		body = binary.LittleEndian.AppendUint32(body, 0xffffffff) //numberToReturn -1
+		body = append(body, bsonDoc(cmd, int32(1))...)
+	}
+
+	header := binary.LittleEndian.AppendUint32(nil, uint32(16+len(body)))
+	header = binary.LittleEndian.AppendUint32(header, uint32(m.requestID))
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	header = binary.LittleEndian.AppendUint32(header, uint32(op))
+	if _, err := m.conn.Write(append(header, body...)); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 16)
+	if _, err := io.ReadFull(m.conn, head); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(head[0:4])
+	if length < 16 || length > 1024*1024 {
+		return nil, errors.New("invalid mongodb message length")
+	}
+	reply := make([]byte, length-16)
+	if _, err := io.ReadFull(m.conn, reply); err != nil {
+		return nil, err
+	}
+
+	switch binary.LittleEndian.Uint32(head[12:16]) {
+	case opReply:
+		//responseFlags (4), cursorID (8), startingFrom (4), numberReturned (4)
+		if len(reply) < 20 {
+			return nil, errors.New("short mongodb reply")
+		}
+		return bsonDecode(reply[20:])
+	case opMsg:
+		if len(reply) < 5 || reply[4] != 0 {
+			return nil, errors.New("unexpected mongodb OP_MSG section")
+		}
+		return bsonDecode(reply[5:])
+	default:
+		return nil, errors.New("unexpected mongodb opcode")
+	}
+}
+
+// documento BSON plano con pares clave/valor (int32 o string)
+func bsonDoc(pairs ...any) []byte {
+	var elems []byte
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key := pairs[i].(string)
+		switch v := pairs[i+1].(type) {
+		case int32:
+			elems = append(elems, 0x10)
+			elems = append(elems, key+"\x00"...)
+			elems = binary.LittleEndian.AppendUint32(elems, uint32(v))
+		case string:
+			elems = append(elems, 0x02)
+			elems = append(elems, key+"\x00"...)
+			elems = binary.LittleEndian.AppendUint32(elems, uint32(len(v)+1))
+			elems = append(elems, v+"\x00"...)
+		}
+	}
+	doc := binary.LittleEndian.AppendUint32(nil, uint32(4+len(elems)+1))
+	doc = append(doc, elems...)
+	return append(doc, 0)
+}
+
+// decodifica el primer nivel de un documento (los subdocumentos se omiten)
+func bsonDecode(data []byte) (map[string]any, error) {
+	if len(data) < 5 {
+		return nil, errors.New("short bson document")
+	}
+	size := int(binary.LittleEndian.Uint32(data[:4]))
+	if size > len(data) || size < 5 {
+		return nil, errors.New("invalid bson document size")
+	}
+	data = data[4 : size-1]
+
+	out := make(map[string]any)
+	for len(data) > 0 {
+		kind := data[0]
+		end := bytes.IndexByte(data[1:], 0)
+		if end < 0 {
+			return nil, errors.New("unterminated bson key")
+		}
+		key := string(data[1 : 1+end])
+		data = data[2+end:]
+
+		n, value, err := bsonValue(kind, data)
+		if err != nil {
+			return nil, fmt.Errorf("bson field %q: %w", key, err)
+		}
+		if value != nil {
+			out[key] = value
+		}
+		data = data[n:]
+	}
+	return out, nil
+}
+
+// bytes ocupados por un valor y su valor para los tipos simples
+func bsonValue(kind byte, data []byte) (int, any, error) {
+	need := func(n int) error {
+		if n < 0 || len(data) < n {
+			return errors.New("truncated value")
+		}
+		return nil
+	}
+	lenPrefixed := func(extra int) (int, error) {
+		if err := need(4); err != nil {
+			return 0, err
+		}
+		n := int(int32(binary.LittleEndian.Uint32(data[:4]))) + extra
+		return n, need(n)
+	}
+
+	switch kind {
+	case 0x01: //double
+		if err := need(8); err != nil {
+			return 0, nil, err
+		}
+		return 8, math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+	case 0x02, 0x0d, 0x0e: //string, javascript, symbol
+		n, err := lenPrefixed(4)
+		if err != nil {
+			return 0, nil, err
+		}
+		return n, string(bytes.TrimRight(data[4:n], "\x00")), nil
+	case 0x03, 0x04, 0x0f: //documento, array, code with scope
+		n, err := lenPrefixed(0)
+		return n, nil, err
+	case 0x05: //binario
+		n, err := lenPrefixed(5)
+		return n, nil, err
+	case 0x06, 0x0a, 0xff, 0x7f: //undefined, null, min/max key
+		return 0, nil, nil
+	case 0x07: //ObjectId
+		return 12, nil, need(12)
+	case 0x08: //bool
+		if err := need(1); err != nil {
+			return 0, nil, err
+		}
+		return 1, data[0] == 1, nil
+	case 0x09, 0x11: //datetime, timestamp
+		return 8, nil, need(8)
+	case 0x0b: //regex: dos cstrings
+		first := bytes.IndexByte(data, 0)
+		if first < 0 {
+			return 0, nil, errors.New("truncated regex")
+		}
+		second := bytes.IndexByte(data[first+1:], 0)
+		if second < 0 {
+			return 0, nil, errors.New("truncated regex")
+		}
+		return first + second + 2, nil, nil
+	case 0x0c: //DBPointer
+		n, err := lenPrefixed(4 + 12)
+		return n, nil, err
+	case 0x10: //int32
+		if err := need(4); err != nil {
+			return 0, nil, err
+		}
+		return 4, int32(binary.LittleEndian.Uint32(data)), nil
+	case 0x12: //int64
+		if err := need(8); err != nil {
+			return 0, nil, err
+		}
+		return 8, int64(binary.LittleEndian.Uint64(data)), nil
+	case 0x13: //decimal128
+		return 16, nil, need(16)
+	default:
+		return 0, nil, fmt.Errorf("unknown bson type 0x%02x", kind)
+	}
+}
@@ -0,0 +1,167 @@
+package database
+
+//MSSQL -> paquete PRELOGIN de TDS (version y cifrado, antes del login)
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+)
+
+// tipos de paquete TDS
+const (
+	tdsPrelogin = 0x12
+	tdsResponse = 0x04
+)
+
+// tokens de PRELOGIN
+const (
+	preloginVersion    = 0x00
+	preloginEncryption = 0x01
+	preloginTerminator = 0xff
+)
+
+// valores de ENCRYPTION
+var tdsEncryption = map[byte]string{
+	0x00: "off",
+	0x01: "on",
+	0x02: "unsupported",
+	0x03: "required",
+}
+
+// version mayor -> nombre comercial
+var sqlServerReleases = map[int]string{
+	9:  "2005",
+	10: "2008",
+	11: "2012",
+	12: "2014",
+	13: "2016",
+	14: "2017",
+	15: "2019",
+	16: "2022",
+	17: "2025",
+}
+
+type MSSQLProbe struct{}
+
+func NewMSSQLProbe() *MSSQLProbe {
+	return &MSSQLProbe{}
+}
+
+func (p *MSSQLProbe) Spec() probe.Spec {
+	return spec("mssql", service.ServiceMSSQL, 1433)
+}
+
+func (p *MSSQLProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(prelogin()); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 8)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+	if head[0] != tdsResponse {
+		return nil, fmt.Errorf("unexpected tds packet type 0x%02x", head[0])
+	}
+	length := int(binary.BigEndian.Uint16(head[2:4]))
+	if length < 8 || length > 4096 {
+		return nil, errors.New("invalid tds packet length")
+	}
+	body := make([]byte, length-8)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	return parsePrelogin(body)
+}
+
+// PRELOGIN con VERSION, ENCRYPTION (off), INSTOPT, THREADID y MARS
+func prelogin() []byte {
+	type option struct {
+		token byte
+		data  []byte
+	}
+	options := []option{
+		{preloginVersion, []byte{0, 0, 0, 0, 0, 0}},
+		{preloginEncryption, []byte{0x00}},
+		{0x02, []byte{0x00}},       //INSTOPT: instancia por defecto
+		{0x03, []byte{0, 0, 0, 0}}, //THREADID
+		{0x04, []byte{0x00}},       //MARS
+	}
+
+	offset := 5*len(options) + 1
+	var table, data []byte
+	for _, o := range options {
+		table = append(table, o.token)
+		table = binary.BigEndian.AppendUint16(table, uint16(offset+len(data)))
+		table = binary.BigEndian.AppendUint16(table, uint16(len(o.data)))
+		data = append(data, o.data...)
+	}
+	table = append(table, preloginTerminator)
+	payload := append(table, data...)
+
+	//cabecera: tipo, estado (fin de mensaje), largo, spid, id de paquete, ventana
+	packet := []byte{tdsPrelogin, 0x01}
+	packet = binary.BigEndian.AppendUint16(packet, uint16(8+len(payload)))
+	packet = append(packet, 0, 0, 1, 0)
+	return append(packet, payload...)
+}
+
+func parsePrelogin(body []byte) (*service.ServiceInfo, error) {
+	//el login siempre exige credenciales (SQL o integradas)
+	details := &service.DatabaseInfo{Protocol: "TDS", AuthRequired: true}
+	info := newInfo(service.ServiceMSSQL, "Microsoft SQL Server", details)
+
+	found, release := false, ""
+	for pos := 0; pos+5 <= len(body) && body[pos] != preloginTerminator; pos += 5 {
+		token := body[pos]
+		offset := int(binary.BigEndian.Uint16(body[pos+1 : pos+3]))
+		size := int(binary.BigEndian.Uint16(body[pos+3 : pos+5]))
+		if offset+size > len(body) {
+			return nil, errors.New("truncated prelogin option")
+		}
+		value := body[offset : offset+size]
+
+		switch token {
+		case preloginVersion:
+			if size < 6 {
+				continue
+			}
+			found = true
+			major, minor := int(value[0]), int(value[1])
+			build := binary.BigEndian.Uint16(value[2:4])
+			info.Version = fmt.Sprintf("%d.%d.%d", major, minor, build)
+			if name, ok := sqlServerReleases[major]; ok {
+				release = name
+				if major == 10 && minor >= 50 {
+					release = "2008_r2"
+					name = "2008 R2"
+				}
+				info.ExtraInfo = "SQL Server " + name
+			}
+		case preloginEncryption:
+			if size >= 1 {
+				details.Encryption = tdsEncryption[value[0]]
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("prelogin response without version")
+	}
+	//los CPE de SQL Server usan el nombre comercial, no el build
+	c := "cpe:/a:microsoft:sql_server"
+	if release != "" {
+		c += ":" + release
+	}
+	info.CPE = append(info.CPE, c)
+	return info, nil
+}
@@ -0,0 +1,122 @@
+package database
+
+//MYSQL -> saludo inicial del servidor (HandshakeV10)
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strings"
+)
+
+// el servidor habla primero, basta con leer el saludo
+type MySQLProbe struct{}
+
+func NewMySQLProbe() *MySQLProbe {
+	return &MySQLProbe{}
+}
+
+func (p *MySQLProbe) Spec() probe.Spec {
+	return spec("mysql", service.ServiceMySQL, 3306)
+}
+
+func (p *MySQLProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	payload, err := readMySQLPacket(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseGreeting(payload)
+}
+
+// paquete: largo (3 bytes LE) + secuencia + payload
+func readMySQLPacket(r io.Reader) ([]byte, error) {
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, err
+	}
+	length := int(head[0]) | int(head[1])<<8 | int(head[2])<<16
+	if length == 0 || length > 64*1024 {
+		return nil, errors.New("invalid mysql packet length")
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func parseGreeting(payload []byte) (*service.ServiceInfo, error) {
+	//0xff: el servidor rechaza la conexion antes del saludo ("Host ... is not allowed to connect")
+	if payload[0] == 0xff {
+		if len(payload) < 3 {
+			return nil, errors.New("short mysql error packet")
+		}
+		msg := payload[3:]
+		if len(msg) > 0 && msg[0] == '#' && len(msg) >= 6 {
+			msg = msg[6:] //marcador SQL state
+		}
+		info := newInfo(service.ServiceMySQL, "MySQL", &service.DatabaseInfo{AuthRequired: true})
+		info.ExtraInfo = "rejected: " + string(msg)
+		return info, nil
+	}
+
+	if payload[0] != 10 {
+		return nil, fmt.Errorf("unsupported mysql protocol version %d", payload[0])
+	}
+
+	end := bytes.IndexByte(payload[1:], 0)
+	if end < 0 {
+		return nil, errors.New("truncated mysql greeting")
+	}
+	version := string(payload[1 : 1+end])
+	rest := payload[2+end:]
+
+	//connection id (4), auth data 1 (8), filler (1), capacidades (2), charset (1), estado (2), capacidades (2), largo auth (1), reservado (10)
+	var plugin string
+	if len(rest) >= 31 {
+		capabilities := uint32(binary.LittleEndian.Uint16(rest[13:15])) | uint32(binary.LittleEndian.Uint16(rest[18:20]))<<16
+		authLen := int(rest[20])
+		rest = rest[31:]
+
+		const clientPluginAuth = 0x00080000
+		if capabilities&clientPluginAuth != 0 {
+			skip := max(13, authLen-8) //auth data 2
+			if len(rest) > skip {
+				plugin = string(bytes.TrimRight(rest[skip:], "\x00"))
+			}
+		}
+	}
+
+	//la autenticacion siempre es parte del handshake, no se prueba si hay cuentas sin clave
+	details := &service.DatabaseInfo{Protocol: "10", AuthRequired: true, AuthMethod: plugin}
+
+	product, cpe := "MySQL", "oracle:mysql"
+	if i := strings.Index(version, "-MariaDB"); i >= 0 {
+		//MariaDB antepone "5.5.5-" por compatibilidad con clientes antiguos
+		product, cpe = "MariaDB", "mariadb:mariadb"
+		version = strings.TrimPrefix(version[:i], "5.5.5-")
+	}
+
+	info := newInfo(service.ServiceMySQL, product, details)
+	info.Version, info.ExtraInfo = splitVersion(version)
+	addCPE(info, cpe)
+	return info, nil
+}
+
+// "8.0.36-0ubuntu0.22.04.1" -> "8.0.36", "0ubuntu0.22.04.1"
+func splitVersion(v string) (string, string) {
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
@@ -0,0 +1,156 @@
+package database
+
+//POSTGRESQL -> StartupMessage y respuesta de autenticacion (protocolo v3)
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strings"
+)
+
+// usuario y base de la StartupMessage (solo identifican la sesion, no autentican)
+const pgProbeUser = "postgres"
+
+// tipos de AuthenticationRequest
+var pgAuthMethods = map[uint32]string{
+	2:  "Kerberos V5",
+	3:  "cleartext password",
+	5:  "MD5 password",
+	7:  "GSSAPI",
+	9:  "SSPI",
+	10: "SASL",
+}
+
+// envia la StartupMessage y lee la exigencia de autenticacion
+// si el servidor no la exige (trust), se lee server_version y se cierra con Terminate
+type PostgresProbe struct{}
+
+func NewPostgresProbe() *PostgresProbe {
+	return &PostgresProbe{}
+}
+
+func (p *PostgresProbe) Spec() probe.Spec {
+	return spec("postgresql", service.ServicePostgreSQL, 5432)
+}
+
+func (p *PostgresProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(pgStartup(pgProbeUser)); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	details := &service.DatabaseInfo{Protocol: "3.0"}
+	info := newInfo(service.ServicePostgreSQL, "PostgreSQL", details)
+
+	for i := 0; i < 64; i++ {
+		tag, body, err := pgMessage(r)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			break
+		}
+
+		switch tag {
+		case 'R':
+			if len(body) < 4 {
+				return nil, errors.New("short postgres authentication request")
+			}
+			code := binary.BigEndian.Uint32(body[:4])
+			if code == 0 {
+				continue //AuthenticationOk: siguen ParameterStatus hasta ReadyForQuery
+			}
+			details.AuthRequired = true
+			details.AuthMethod = pgAuthMethods[code]
+			if code == 10 {
+				//SASL: lista de mecanismos terminados en NUL
+				mechs := strings.Split(strings.Trim(string(body[4:]), "\x00"), "\x00")
+				details.AuthMethod = strings.Join(mechs, ", ")
+			}
+			return info, nil
+
+		case 'S':
+			parts := bytes.Split(body, []byte{0})
+			if len(parts) >= 2 && string(parts[0]) == "server_version" {
+				info.Version, info.ExtraInfo = pgVersion(string(parts[1]))
+				addCPE(info, "postgresql:postgresql")
+			}
+
+		case 'Z':
+			conn.Write([]byte{'X', 0, 0, 0, 4}) //Terminate
+			return info, nil
+
+		case 'E':
+			//pg_hba sin entrada, usuario inexistente o SSL obligatorio: igual pide credenciales
+			details.AuthRequired = true
+			info.ExtraInfo = "rejected: " + pgError(body)
+			return info, nil
+
+		default:
+			return nil, fmt.Errorf("unexpected postgres message %q", tag)
+		}
+	}
+	return info, nil
+}
+
+// StartupMessage v3 con user y database
+func pgStartup(user string) []byte {
+	var body bytes.Buffer
+	binary.Write(&body, binary.BigEndian, uint32(196608))
+	for _, kv := range []string{"user", user, "database", user, "application_name", "go-scanner"} {
+		body.WriteString(kv)
+		body.WriteByte(0)
+	}
+	body.WriteByte(0)
+
+	msg := binary.BigEndian.AppendUint32(nil, uint32(4+body.Len()))
+	return append(msg, body.Bytes()...)
+}
+
+// mensaje del backend: tipo (1) + largo (4, incluye el propio largo) + cuerpo
+func pgMessage(r *bufio.Reader) (byte, []byte, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return 0, nil, err
+	}
+	if length < 4 || length > 64*1024 {
+		return 0, nil, errors.New("invalid postgres message length")
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return tag, body, nil
+}
+
+// campo M (mensaje) de un ErrorResponse
+func pgError(body []byte) string {
+	for _, field := range bytes.Split(body, []byte{0}) {
+		if len(field) > 1 && field[0] == 'M' {
+			return string(field[1:])
+		}
+	}
+	return "unknown error"
+}
+
+// "16.2 (Debian 16.2-1.pgdg120+2)" -> "16.2", "Debian 16.2-1.pgdg120+2"
+func pgVersion(v string) (string, string) {
+	version, extra, _ := strings.Cut(v, " ")
+	return version, strings.Trim(extra, "()")
+}
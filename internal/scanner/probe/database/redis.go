@@ -0,0 +1,114 @@
+package database
+
+//REDIS -> PING e INFO server (RESP)
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type RedisProbe struct{}
+
+func NewRedisProbe() *RedisProbe {
+	return &RedisProbe{}
+}
+
+func (p *RedisProbe) Spec() probe.Spec {
+	return spec("redis", service.ServiceRedis, 6379)
+}
+
+func (p *RedisProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		return nil, err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+
+	details := &service.DatabaseInfo{Protocol: "RESP"}
+	info := newInfo(service.ServiceRedis, "Redis", details)
+
+	switch {
+	case line == "+PONG":
+	case strings.HasPrefix(line, "-NOAUTH"), strings.HasPrefix(line, "-WRONGPASS"):
+		details.AuthRequired = true
+		return info, nil
+	case strings.HasPrefix(line, "-DENIED"):
+		//protected mode: sin clave y sin bind, solo acepta loopback
+		details.AuthRequired = true
+		info.ExtraInfo = "protected mode"
+		return info, nil
+	default:
+		return nil, fmt.Errorf("not a redis PING reply: %q", line)
+	}
+
+	if _, err := conn.Write([]byte("INFO server\r\n")); err != nil {
+		return info, nil
+	}
+	fields, err := readRedisInfo(r)
+	if err != nil {
+		return info, nil
+	}
+
+	info.Version = fields["redis_version"]
+	if v := fields["valkey_version"]; v != "" {
+		info.Product, info.Version = "Valkey", v
+	}
+	var extra []string
+	if mode := fields["redis_mode"]; mode != "" {
+		extra = append(extra, mode)
+	}
+	if os := fields["os"]; os != "" {
+		extra = append(extra, os)
+		if strings.HasPrefix(os, "Linux") {
+			info.OSHint = "Linux"
+		}
+	}
+	info.ExtraInfo = strings.Join(extra, "; ")
+	if info.Product == "Redis" {
+		addCPE(info, "redis:redis")
+	}
+	return info, nil
+}
+
+// bulk string "$len\r\n...\r\n" con lineas "clave:valor"
+func readRedisInfo(r *bufio.Reader) (map[string]string, error) {
+	head, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(head, "$") {
+		return nil, errors.New("unexpected INFO reply")
+	}
+	size, err := strconv.Atoi(strings.TrimSpace(head[1:]))
+	if err != nil || size < 0 || size > 64*1024 {
+		return nil, errors.New("invalid INFO length")
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	for _, line := range strings.Split(string(body), "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			fields[k] = v
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,57 @@
+package dnsprobe
+
+//AXFR -> se cuentan los registros hasta el SOA final, la zona no se conserva
+import (
+	"context"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func zoneTransfer(ctx context.Context, opts probe.Options, address, zone string) service.ZoneTransfer {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	result := service.ZoneTransfer{Zone: zone}
+
+	query, id, err := buildQuery(zone+".", dnsmessage.TypeAXFR, dnsmessage.ClassINET, false)
+	if err != nil {
+		return result
+	}
+	conn, err := opts.Dial(ctx, "tcp", address)
+	if err != nil {
+		return result
+	}
+	defer conn.Close()
+
+	if err := writeTCP(conn, query); err != nil {
+		return result
+	}
+
+	//la zona empieza y termina con el SOA, puede venir en varios mensajes
+	soas := 0
+	for soas < 2 && result.Records < maxTransferRecords {
+		raw, err := readTCP(conn)
+		if err != nil {
+			break
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(raw); err != nil || msg.ID != id || msg.RCode != dnsmessage.RCodeSuccess {
+			break
+		}
+		if len(msg.Answers) == 0 {
+			break
+		}
+		for _, a := range msg.Answers {
+			if result.Records == 0 && a.Header.Type != dnsmessage.TypeSOA {
+				return result //no es el inicio de una transferencia
+			}
+			result.Records++
+			if a.Header.Type == dnsmessage.TypeSOA {
+				soas++
+			}
+		}
+	}
+	result.Allowed = result.Records > 0
+	return result
+}
@@ -0,0 +1,189 @@
+package dnsprobe
+
+//PROBER DNS -> version.bind/id.server (CHAOS), recursion y AXFR de zonas dadas
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// nombre externo consultado para comprobar la recursion (consulta inocua)
+const recursionProbeName = "example.com."
+
+// limite de registros leidos en un AXFR (la zona no se guarda, solo se cuenta)
+const maxTransferRecords = 100000
+
+// prober DNS, uno por transporte
+type DNSProbe struct {
+	network string //"udp" o "tcp"
+}
+
+func NewDNSProbe(network string) *DNSProbe {
+	return &DNSProbe{network: network}
+}
+
+func (p *DNSProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     "dns",
+		Protocol: model.Protocol(p.network),
+		Services: []service.ServiceType{service.ServiceDNS},
+		Ports:    []int{53},
+	}
+}
+
+// AXFR de las zonas de la policy; las transferencias siempre van por TCP,
+// aunque el puerto se haya detectado por UDP
+func (p *DNSProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	address := t.Address()
+	details := &service.DNSInfo{Transport: p.network}
+
+	//CHAOS es best-effort: hay filtros que lo descartan y no debe impedir el chequeo de recursion
+	txt, err := p.chaosTXT(ctx, opts, address, "version.bind.")
+	answered := replied(err)
+	details.VersionBind = txt
+	txt, err = p.chaosTXT(ctx, opts, address, "id.server.")
+	answered = answered || replied(err)
+	details.IDServer = txt
+
+	if recursion, err := p.recursion(ctx, opts, address); err == nil {
+		answered = true
+		details.Recursion = recursion
+	}
+	if !answered {
+		return nil, errors.New("no dns response")
+	}
+
+	for _, zone := range opts.DNSZones {
+		details.ZoneTransfers = append(details.ZoneTransfers, zoneTransfer(ctx, opts, address, zone))
+	}
+
+	info := &service.ServiceInfo{
+		Type:       service.ServiceDNS,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		DNS:        details,
+	}
+	identify(info, details.VersionBind)
+	return info, nil
+}
+
+// el servidor respondio pero sin registros (rcode de error o respuesta vacia)
+var errNoAnswer = errors.New("no answer")
+
+// cualquier respuesta DNS bien formada, con el rcode que sea, identifica el servicio
+func replied(err error) bool {
+	return err == nil || errors.Is(err, errNoAnswer)
+}
+
+// TXT en clase CHAOS
+func (p *DNSProbe) chaosTXT(ctx context.Context, opts probe.Options, address, name string) (string, error) {
+	msg, err := p.exchange(ctx, opts, address, name, dnsmessage.TypeTXT, dnsmessage.ClassCHAOS, false)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range msg.Answers {
+		if txt, ok := a.Body.(*dnsmessage.TXTResource); ok && len(txt.TXT) > 0 {
+			return strings.Join(txt.TXT, ""), nil
+		}
+	}
+	return "", errNoAnswer
+}
+
+// recursion disponible: flag RA y respuesta para un nombre que el servidor no aloja
+func (p *DNSProbe) recursion(ctx context.Context, opts probe.Options, address string) (bool, error) {
+	msg, err := p.exchange(ctx, opts, address, recursionProbeName, dnsmessage.TypeA, dnsmessage.ClassINET, true)
+	if err != nil && !errors.Is(err, errNoAnswer) {
+		return false, err
+	}
+	if msg == nil {
+		return false, nil
+	}
+	return msg.RecursionAvailable && msg.RCode == dnsmessage.RCodeSuccess && len(msg.Answers) > 0, nil
+}
+
+// una consulta por el transporte del prober
+// retorna errNoAnswer junto al mensaje si el rcode no es NOERROR
+func (p *DNSProbe) exchange(ctx context.Context, opts probe.Options, address, name string, qtype dnsmessage.Type, class dnsmessage.Class, recursive bool) (*dnsmessage.Message, error) {
+	query, id, err := buildQuery(name, qtype, class, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := opts.Dial(ctx, p.network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var raw []byte
+	if p.network == "tcp" {
+		if err := writeTCP(conn, query); err != nil {
+			return nil, err
+		}
+		raw, err = readTCP(conn)
+	} else {
+		if _, err = conn.Write(query); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 4096)
+		var n int
+		n, err = conn.Read(buf)
+		raw = buf[:n]
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(raw); err != nil {
+		return nil, err
+	}
+	if !msg.Response || msg.ID != id {
+		return nil, errors.New("unexpected dns response")
+	}
+	if msg.RCode != dnsmessage.RCodeSuccess {
+		return &msg, errNoAnswer
+	}
+	return &msg, nil
+}
+
+func buildQuery(name string, qtype dnsmessage.Type, class dnsmessage.Class, recursive bool) ([]byte, uint16, error) {
+	n, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	id := uint16(rand.Intn(1 << 16))
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: recursive},
+		Questions: []dnsmessage.Question{{Name: n, Type: qtype, Class: class}},
+	}
+	packed, err := msg.Pack()
+	return packed, id, err
+}
+
+// por TCP cada mensaje lleva un prefijo de 2 bytes con su largo
+func writeTCP(conn net.Conn, msg []byte) error {
+	_, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...))
+	return err
+}
+
+func readTCP(conn net.Conn) ([]byte, error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(head))
+	if _, err := io.ReadFull(conn, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
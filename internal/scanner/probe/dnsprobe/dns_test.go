@@ -0,0 +1,125 @@
+package dnsprobe
+
+import (
+	"context"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// servidor UDP en memoria: answer completa la respuesta a cada consulta (false = la descarta)
+func fakeServer(t *testing.T, answer func(q dnsmessage.Question, resp *dnsmessage.Message) bool) probe.Target {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if query.Unpack(buf[:n]) != nil || len(query.Questions) != 1 {
+				continue
+			}
+			resp := &dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionDesired: query.RecursionDesired},
+				Questions: query.Questions,
+			}
+			if !answer(query.Questions[0], resp) {
+				continue
+			}
+			if packed, err := resp.Pack(); err == nil {
+				conn.WriteTo(packed, addr)
+			}
+		}
+	}()
+
+	addr := conn.LocalAddr().(*net.UDPAddr)
+	return probe.Target{Host: "127.0.0.1", Port: addr.Port, Service: &service.ServiceInfo{}}
+}
+
+// respuesta A para el nombre de la consulta
+func answerA(q dnsmessage.Question, resp *dnsmessage.Message) {
+	resp.RecursionAvailable = true
+	resp.Answers = []dnsmessage.Resource{{
+		Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+		Body:   &dnsmessage.AResource{A: [4]byte{93, 184, 215, 14}},
+	}}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name      string
+		answer    func(q dnsmessage.Question, resp *dnsmessage.Message) bool
+		ok        bool
+		recursion bool
+		version   string
+	}{
+		{
+			name: "open resolver dropping chaos",
+			answer: func(q dnsmessage.Question, resp *dnsmessage.Message) bool {
+				if q.Class == dnsmessage.ClassCHAOS {
+					return false
+				}
+				answerA(q, resp)
+				return true
+			},
+			ok:        true,
+			recursion: true,
+		},
+		{
+			name: "refuses everything",
+			answer: func(q dnsmessage.Question, resp *dnsmessage.Message) bool {
+				resp.RCode = dnsmessage.RCodeRefused
+				return true
+			},
+			ok: true,
+		},
+		{
+			name: "version.bind and no recursion",
+			answer: func(q dnsmessage.Question, resp *dnsmessage.Message) bool {
+				if q.Name.String() == "version.bind." {
+					resp.Answers = []dnsmessage.Resource{{
+						Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassCHAOS},
+						Body:   &dnsmessage.TXTResource{TXT: []string{"9.18.19-1~deb12u1-Debian"}},
+					}}
+					return true
+				}
+				resp.RCode = dnsmessage.RCodeRefused
+				return true
+			},
+			ok:      true,
+			version: "9.18.19-1~deb12u1-Debian",
+		},
+		{
+			name:   "silent",
+			answer: func(dnsmessage.Question, *dnsmessage.Message) bool { return false },
+		},
+	}
+
+	opts := probe.Options{Timeout: 300 * time.Millisecond}
+	for _, tt := range tests {
+		target := fakeServer(t, tt.answer)
+		info, err := NewDNSProbe("udp").Probe(context.Background(), target, opts)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Probe error = %v, want ok %v", tt.name, err, tt.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if info.Type != service.ServiceDNS || info.DNS.Recursion != tt.recursion || info.DNS.VersionBind != tt.version {
+			t.Errorf("%s: Probe = type %q, dns %+v", tt.name, info.Type, info.DNS)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package dnsprobe
+
+//IDENTIFICACION -> producto y version a partir del TXT de version.bind
+import (
+	"go-scanner/internal/scanner/service"
+	"regexp"
+)
+
+// formatos conocidos de version.bind (el grupo 1 es la version)
+var servers = []struct {
+	re      *regexp.Regexp
+	product string
+	cpe     string //vendor:product
+	os      string
+}{
+	{regexp.MustCompile(`^(\d+\.\d+\.\d+)(?:[-.\s]|$)`), "ISC BIND", "isc:bind", ""},
+	{regexp.MustCompile(`(?i)^dnsmasq-(\S+)`), "dnsmasq", "thekelleys:dnsmasq", ""},
+	{regexp.MustCompile(`(?i)^unbound (\S+)`), "Unbound", "nlnetlabs:unbound", ""},
+	{regexp.MustCompile(`(?i)^PowerDNS Authoritative Server (\S+)`), "PowerDNS Authoritative Server", "powerdns:authoritative", ""},
+	{regexp.MustCompile(`(?i)^PowerDNS Recursor (\S+)`), "PowerDNS Recursor", "powerdns:recursor", ""},
+	{regexp.MustCompile(`(?i)^Knot DNS (\S+)`), "Knot DNS", "nic:knot_dns", ""},
+	{regexp.MustCompile(`(?i)^Knot Resolver (\S+)`), "Knot Resolver", "nic:knot_resolver", ""},
+	{regexp.MustCompile(`(?i)^CoreDNS-(\S+)`), "CoreDNS", "coredns:coredns", ""},
+	{regexp.MustCompile(`(?i)^NSD (\S+)`), "NSD", "nlnetlabs:nsd", ""},
+	{regexp.MustCompile(`(?i)^Microsoft DNS (\S+)`), "Microsoft DNS", "microsoft:dns_server", "Windows"},
+}
+
+// completa producto, version y CPE; si el texto no es conocido queda como informacion extra
+func identify(info *service.ServiceInfo, versionBind string) {
+	if versionBind == "" {
+		return
+	}
+	for _, s := range servers {
+		m := s.re.FindStringSubmatch(versionBind)
+		if m == nil {
+			continue
+		}
+		info.Product = s.product
+		info.Version = m[1]
+		info.OSHint = s.os
+		info.CPE = append(info.CPE, "cpe:/a:"+s.cpe+":"+m[1])
+		return
+	}
+	//muchos servidores ocultan la version con un texto libre
+	info.ExtraInfo = "version.bind: " + versionBind
+}
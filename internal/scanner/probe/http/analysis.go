@@ -0,0 +1,181 @@
+package http
+
+//ANALISIS HTTP PROFUNDO -> titulo, redirects, headers de seguridad, cookies, favicon y tecnologias
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// limites del analisis
+const (
+	maxRedirects = 5
+	maxBody      = 512 * 1024 //bytes leidos de la pagina
+	maxFavicon   = 256 * 1024
+	maxTitle     = 200
+)
+
+// headers de seguridad revisados (HSTS solo aplica sobre HTTPS)
+var securityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"Referrer-Policy",
+	"Permissions-Policy",
+}
+
+var (
+	titleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	iconRe  = regexp.MustCompile(`(?is)<link\s[^>]*rel\s*=\s*["']?[^"'>]*\bicon\b[^>]*>`)
+	hrefRe  = regexp.MustCompile(`(?is)\bhref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// GET con redirects acotados, lectura limitada del body y analisis de la pagina final
+// con Host/SNI del nombre principal del target
+func analyze(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	details := &service.HTTPInfo{}
+	var cookies []*http.Cookie
+
+	host := t.Hostname()
+	client := newClient(ctx, opts, host)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return http.ErrUseLastResponse //se queda con el ultimo redirect
+		}
+		details.Redirects = append(details.Redirects, req.URL.String())
+		if req.Response != nil {
+			cookies = append(cookies, req.Response.Cookies()...)
+		}
+		return nil
+	}
+
+	resp, err := get(client, t.Host, t.Port, host, schemeHint(t))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil && len(body) == 0 {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	cookies = append(cookies, resp.Cookies()...)
+
+	details.Status = resp.StatusCode
+	details.FinalURL = resp.Request.URL.String()
+	details.Title = pageTitle(body)
+	details.SecurityHeaders, details.MissingHeaders = checkSecurityHeaders(resp)
+	details.Cookies = cookieFlags(cookies)
+
+	if icon, err := fetchFavicon(client, resp.Request.URL, body); err == nil {
+		hash := faviconHash(icon)
+		details.FaviconHash = &hash
+	}
+
+	p := page{header: resp.Header, title: details.Title, body: string(body)}
+	for _, c := range details.Cookies {
+		p.cookies = append(p.cookies, c.Name)
+	}
+	details.Technologies = matchTechnologies(p)
+
+	info := responseInfo(resp)
+	info.HTTP = details
+	return info, nil
+}
+
+// <title> sin entidades ni espacios repetidos
+func pageTitle(body []byte) string {
+	m := titleRe.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	title := strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	if len(title) > maxTitle {
+		title = title[:maxTitle]
+	}
+	return title
+}
+
+// headers de seguridad presentes y ausentes
+func checkSecurityHeaders(resp *http.Response) (present, missing []string) {
+	for _, h := range securityHeaders {
+		if h == "Strict-Transport-Security" && resp.TLS == nil {
+			continue
+		}
+		if resp.Header.Get(h) != "" {
+			present = append(present, h)
+		} else {
+			missing = append(missing, h)
+		}
+	}
+	return present, missing
+}
+
+// flags de las cookies recibidas en toda la cadena (una entrada por nombre)
+func cookieFlags(cookies []*http.Cookie) []service.Cookie {
+	var out []service.Cookie
+	seen := make(map[string]bool)
+	for _, c := range cookies {
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+
+		var sameSite string
+		switch c.SameSite {
+		case http.SameSiteStrictMode:
+			sameSite = "Strict"
+		case http.SameSiteLaxMode:
+			sameSite = "Lax"
+		case http.SameSiteNoneMode:
+			sameSite = "None"
+		}
+		out = append(out, service.Cookie{Name: c.Name, Secure: c.Secure, HttpOnly: c.HttpOnly, SameSite: sameSite})
+	}
+	return out
+}
+
+// favicon declarado en la pagina o /favicon.ico
+func fetchFavicon(client *http.Client, base *url.URL, body []byte) ([]byte, error) {
+	ref := "/favicon.ico"
+	if link := iconRe.Find(body); link != nil {
+		if m := hrefRe.FindSubmatch(link); m != nil {
+			ref = html.UnescapeString(string(m[1]) + string(m[2]) + string(m[3]))
+		}
+	}
+
+	u, err := base.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("unsupported favicon reference")
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("favicon status %d", resp.StatusCode)
+	}
+
+	icon, err := io.ReadAll(io.LimitReader(resp.Body, maxFavicon))
+	if err != nil {
+		return nil, err
+	}
+	if len(icon) == 0 {
+		return nil, errors.New("empty favicon")
+	}
+	return icon, nil
+}
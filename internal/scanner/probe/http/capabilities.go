@@ -0,0 +1,185 @@
+package http
+
+//CAPACIDADES WEB -> HTTP/2 (ALPN y h2c), HTTP/3 anunciado en Alt-Svc y WebSocket
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// paths comunes de endpoints WebSocket
+var webSocketPaths = []string{"/", "/ws", "/websocket", "/socket", "/socket.io/?EIO=4&transport=websocket", "/cable"}
+
+// GUID fijo del handshake WebSocket (RFC 6455)
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// SETTINGS vacio en base64url, requerido por el upgrade h2c (RFC 7540 3.2)
+const h2cSettings = "AAMAAABkAAQCAAAAAAIAAAAA"
+
+// prober de capacidades (opt-in, un request por path de WebSocket)
+type CapabilitiesProbe struct{}
+
+func NewCapabilitiesProbe() *CapabilitiesProbe {
+	return &CapabilitiesProbe{}
+}
+
+// opt-in sobre servicios web, despues del request ligero
+func (p *CapabilitiesProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     "http-caps",
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{service.ServiceHTTP, service.ServiceHTTPS},
+		Requires: []string{"http"},
+	}
+}
+
+// con Host/SNI del nombre principal del target
+func (p *CapabilitiesProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	host := t.Hostname()
+	resp, err := get(newClient(ctx, opts, host), t.Host, t.Port, host, schemeHint(t))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	caps := &service.WebCapabilities{}
+	caps.AltSvc, caps.H3 = altSvc(resp.Header)
+
+	w := webConn{ctx: ctx, opts: opts, address: t.Address(), host: host, tls: resp.TLS != nil}
+	if w.tls {
+		caps.H2 = w.alpnH2()
+	} else {
+		caps.H2C = w.upgradeH2C()
+	}
+	for _, path := range webSocketPaths {
+		if w.upgradeWebSocket(path) {
+			caps.WebSocket = append(caps.WebSocket, path)
+		}
+	}
+
+	info := responseInfo(resp)
+	info.Web = caps
+	return info, nil
+}
+
+// entradas de Alt-Svc y si alguna anuncia HTTP/3 (h3, h3-29, ...)
+func altSvc(header http.Header) ([]string, bool) {
+	var entries []string
+	h3 := false
+	for _, value := range header.Values("Alt-Svc") {
+		for _, e := range strings.Split(value, ",") {
+			e = strings.TrimSpace(e)
+			if e == "" || e == "clear" {
+				continue
+			}
+			entries = append(entries, e)
+			if strings.HasPrefix(strings.ToLower(e), "h3") {
+				h3 = true
+			}
+		}
+	}
+	return entries, h3
+}
+
+// conexion cruda al servicio web (los upgrades no pasan por net/http)
+type webConn struct {
+	ctx     context.Context
+	opts    probe.Options
+	address string
+	host    string
+	tls     bool
+}
+
+func (w webConn) tlsConfig(protos ...string) *tls.Config {
+	config := w.opts.TLSConfig(w.host)
+	config.NextProtos = protos
+	return config
+}
+
+func (w webConn) dial() (net.Conn, error) {
+	if w.tls {
+		return w.opts.DialTLS(w.ctx, w.address, w.tlsConfig("http/1.1"))
+	}
+	return w.opts.Dial(w.ctx, "tcp", w.address)
+}
+
+// envia un GET con headers extra y lee solo la cabecera de la respuesta
+func (w webConn) request(path string, headers map[string]string) (*http.Response, error) {
+	conn, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	host := w.host
+	if host == "" {
+		host = w.address
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://"+host+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// ALPN ofreciendo h2 primero
+func (w webConn) alpnH2() bool {
+	conn, err := w.opts.DialTLS(w.ctx, w.address, w.tlsConfig("h2", "http/1.1"))
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	return conn.ConnectionState().NegotiatedProtocol == "h2"
+}
+
+// upgrade HTTP/1.1 -> h2c, el servidor responde 101
+func (w webConn) upgradeH2C() bool {
+	resp, err := w.request("/", map[string]string{
+		"Connection":     "Upgrade, HTTP2-Settings",
+		"Upgrade":        "h2c",
+		"HTTP2-Settings": h2cSettings,
+	})
+	return err == nil && resp.StatusCode == http.StatusSwitchingProtocols &&
+		strings.EqualFold(resp.Header.Get("Upgrade"), "h2c")
+}
+
+// handshake WebSocket, valido solo si Sec-WebSocket-Accept corresponde a la clave enviada
+func (w webConn) upgradeWebSocket(path string) bool {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	resp, err := w.request(path, map[string]string{
+		"Connection":            "Upgrade",
+		"Upgrade":               "websocket",
+		"Sec-WebSocket-Key":     key,
+		"Sec-WebSocket-Version": "13",
+	})
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return resp.Header.Get("Sec-WebSocket-Accept") == base64.StdEncoding.EncodeToString(sum[:])
+}
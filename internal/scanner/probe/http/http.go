@@ -1,60 +1,149 @@
 package http
 
 import (
-	"fmt"
+	"context"
+	"crypto/tls"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
 	"net/http" //cliente http
+	"regexp"
 	"strings"
-	"time"
 )
 
 // interfaz Prober para HTTP/HTTPS
-type HTTPProbe struct{}
+type HTTPProbe struct {
+	Analyze bool //sigue redirects, lee el body y analiza la pagina (ver analysis.go)
+}
 
 // nueva instancia de este
 func NewHTTPProbe() *HTTPProbe {
 	return &HTTPProbe{}
 }
 
-// ejecuta un request ligero HTTP/HTTPS
-func (p *HTTPProbe) Probe(target string, port int, timeout time.Duration) (string, error) {
-	//determinar schema
-	scheme := "http"
-	if port == 443 || port == 8443 {
-		scheme = "https"
-	}
+// prober con analisis profundo (opt-in, mas trafico)
+func NewHTTPAnalysisProbe() *HTTPProbe {
+	return &HTTPProbe{Analyze: true}
+}
+
+// "nginx/1.18.0 (Ubuntu)" -> producto, version, comentario
+var serverHeaderRe = regexp.MustCompile(`^([^/\s]+)(?:/([^\s]+))?(?:\s+\(([^)]+)\))?`)
 
-	url := fmt.Sprintf("%s://%s:%d", scheme, target, port)
+// productos conocidos del header Server -> vendor:product del CPE
+var serverCPE = map[string]string{
+	"nginx":         "nginx:nginx",
+	"apache":        "apache:http_server",
+	"microsoft-iis": "microsoft:internet_information_services",
+	"lighttpd":      "lighttpd:lighttpd",
+	"caddy":         "caddyserver:caddy",
+	"openresty":     "openresty:openresty",
+}
+
+// servicios web, despues de tls (si hubo TLS el esquema ya se conoce)
+// el analisis es opt-in y corre despues del request ligero
+func (p *HTTPProbe) Spec() probe.Spec {
+	if p.Analyze {
+		return probe.Spec{
+			Name:     "http-analysis",
+			Protocol: model.ProtocolTCP,
+			Services: []service.ServiceType{service.ServiceHTTP, service.ServiceHTTPS},
+			Requires: []string{"http"},
+		}
+	}
+	return probe.Spec{
+		Name:     "http",
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{service.ServiceHTTP, service.ServiceHTTPS},
+		Ports:    []int{80, 443, 8000, 8080, 8443},
+		Requires: []string{"tls"},
+		Groups:   []string{"https"},
+	}
+}
 
-	//cliente HTTP con timeout estricto
-	client := &http.Client{
-		Timeout: timeout,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse //no seguir redirects
-		},
+// ejecuta un request ligero HTTP/HTTPS (el esquema se detecta, no depende del puerto)
+// con vhosts usa Host/SNI y compara las respuestas de cada uno
+func (p *HTTPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	if p.Analyze {
+		return analyze(ctx, t, opts)
+	}
+	if len(t.VHosts) > 0 {
+		return compareVHosts(ctx, t, opts)
 	}
 
-	//primero el HEAD, luego mediante GET solo para el body
-	resp, err := client.Get(url)
+	resp, err := get(newClient(ctx, opts, ""), t.Host, t.Port, "", schemeHint(t))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	//construir el banner
-	var info []string
+	//si no hay headers relevantes, tendria que ver otra forma, pero debo tomar onche calmao noma
+	//evitare leer el body de manera innecesaria
 
-	info = append(info, fmt.Sprintf("[%s]", resp.Status))
+	return responseInfo(resp), nil
+}
+
+// tipo, producto y version a partir de la respuesta
+func responseInfo(resp *http.Response) *service.ServiceInfo {
+	info := &service.ServiceInfo{
+		Type:       service.ServiceHTTP,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		TLS:        resp.TLS != nil,
+	}
+	if info.TLS {
+		info.Type = service.ServiceHTTPS
+		info.ExtraInfo = tls.VersionName(resp.TLS.Version)
+	}
 
 	//headers relevantes
 	if server := resp.Header.Get("Server"); server != "" {
-		info = append(info, fmt.Sprintf("Server: %s", server))
+		parseServerHeader(info, server)
 	}
 	if powered := resp.Header.Get("X-Powered-By"); powered != "" {
-		info = append(info, fmt.Sprintf("X-Powered-By: %s", powered))
+		info.ExtraInfo = joinExtra(info.ExtraInfo, "powered by "+powered)
 	}
+	return info
+}
 
-	//si no hay headers relevantes, tendria que ver otra forma, pero debo tomar onche calmao noma
-	//evitare leer el body de manera innecesaria
+// completa producto, version, OS y CPE a partir del header Server
+func parseServerHeader(info *service.ServiceInfo, server string) {
+	m := serverHeaderRe.FindStringSubmatch(strings.TrimSpace(server))
+	if m == nil {
+		info.Product = server
+		return
+	}
 
-	return strings.Join(info, " | "), nil
+	info.Product = m[1]
+	info.Version = m[2]
+	if m[3] != "" {
+		info.ExtraInfo = joinExtra(info.ExtraInfo, m[3])
+		lower := strings.ToLower(m[3])
+		switch {
+		case strings.Contains(lower, "win"):
+			info.OSHint = "Windows"
+		case strings.Contains(lower, "ubuntu"), strings.Contains(lower, "debian"),
+			strings.Contains(lower, "centos"), strings.Contains(lower, "red hat"):
+			info.OSHint = "Linux"
+		case strings.Contains(lower, "unix"):
+			info.OSHint = "Unix"
+		}
+	}
+
+	if vp, ok := serverCPE[strings.ToLower(m[1])]; ok {
+		c := "cpe:/a:" + vp
+		if m[2] != "" {
+			c += ":" + m[2]
+		}
+		info.CPE = append(info.CPE, c)
+	}
+	if strings.EqualFold(m[1], "Microsoft-IIS") {
+		info.OSHint = "Windows"
+	}
+}
+
+func joinExtra(current, add string) string {
+	if current == "" {
+		return add
+	}
+	return current + "; " + add
 }
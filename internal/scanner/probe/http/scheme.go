@@ -0,0 +1,99 @@
+package http
+
+//DETECCION DE ESQUEMA -> TLS o texto plano por puerto, con Host/SNI opcional
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// puertos donde se intenta HTTPS primero (en el resto, HTTP primero)
+var tlsFirstPorts = map[int]bool{443: true, 4443: true, 8443: true, 9443: true, 10443: true}
+
+// servidores HTTPS que responden en claro a un request HTTP (nginx, Apache, Go, ...)
+var plainOnTLSRe = regexp.MustCompile(`(?i)https|ssl|tls`)
+
+// cliente sin redirects con el dialer y la configuracion TLS de la campaña, SNI = host si es un nombre
+// las conexiones quedan atadas a ctx aunque el request no lo lleve
+func newClient(ctx context.Context, opts probe.Options, host string) *http.Client {
+	return &http.Client{
+		Timeout: opts.ConnTimeout(),
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, network, address string) (net.Conn, error) {
+				return opts.Dial(ctx, network, address)
+			},
+			TLSClientConfig:   opts.TLSConfig(host),
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse //no seguir redirects
+		},
+	}
+}
+
+// esquema segun lo que identificaron los probers anteriores: TLS directo -> https ("" = detectar)
+func schemeHint(t probe.Target) string {
+	if t.Service.TLSInfo != nil && t.Service.StartTLS == nil {
+		return "https"
+	}
+	return ""
+}
+
+// GET / probando ambos esquemas (o solo el indicado)
+func get(client *http.Client, target string, port int, host, scheme string) (*http.Response, error) {
+	schemes := []string{"http", "https"}
+	switch {
+	case scheme != "":
+		schemes = []string{scheme}
+	case tlsFirstPorts[port]:
+		schemes = []string{"https", "http"}
+	}
+
+	var lastErr error
+	for _, s := range schemes {
+		resp, err := request(client, s, target, port, host)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if s == "http" && speaksTLS(resp) {
+			resp.Body.Close()
+			lastErr = errors.New("plain HTTP request sent to a TLS port")
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+func request(client *http.Client, scheme, target string, port int, host string) (*http.Response, error) {
+	url := fmt.Sprintf("%s://%s/", scheme, net.JoinHostPort(target, strconv.Itoa(port)))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if host != "" {
+		req.Host = host
+	}
+	return client.Do(req)
+}
+
+// un 400 en claro que menciona HTTPS/SSL/TLS es un servidor TLS quejandose (el body queda intacto)
+func speaksTLS(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	head, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	return plainOnTLSRe.Match(head)
+}
@@ -0,0 +1,135 @@
+package http
+
+//FIRMAS DE TECNOLOGIAS -> frameworks, CMS y paneles de administracion
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"go-scanner/internal/scanner/service"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//go:embed data/technologies.tsv
+var technologiesData string
+
+// una firma: donde buscar y que buscar
+type signature struct {
+	name     string
+	category string
+	where    string //header, cookie, title o body
+	header   string //nombre del header si where == "header"
+	re       *regexp.Regexp
+}
+
+// lo observado de la respuesta final
+type page struct {
+	header  http.Header
+	cookies []string
+	title   string
+	body    string
+}
+
+var (
+	signaturesOnce sync.Once
+	signatures     []signature
+	signaturesErr  error
+)
+
+// firmas embebidas (se compilan una sola vez)
+func loadSignatures() ([]signature, error) {
+	signaturesOnce.Do(func() {
+		signatures, signaturesErr = parseSignatures(technologiesData)
+	})
+	return signatures, signaturesErr
+}
+
+// lee lineas "nombre<TAB>categoria<TAB>donde<TAB>regex"
+func parseSignatures(data string) ([]signature, error) {
+	var out []signature
+	sc := bufio.NewScanner(strings.NewReader(data))
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(text, "\t", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected name, category, location and pattern", line)
+		}
+
+		sig := signature{name: fields[0], category: fields[1], where: fields[2]}
+		if h, ok := strings.CutPrefix(sig.where, "header:"); ok {
+			sig.where, sig.header = "header", h
+		}
+		switch sig.where {
+		case "header", "cookie", "title", "body":
+		default:
+			return nil, fmt.Errorf("line %d: unknown location %q", line, fields[2])
+		}
+
+		re, err := regexp.Compile(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		sig.re = re
+		out = append(out, sig)
+	}
+	return out, sc.Err()
+}
+
+// tecnologias cuyas firmas coinciden con la pagina (una por nombre)
+func matchTechnologies(p page) []service.Technology {
+	sigs, err := loadSignatures()
+	if err != nil {
+		return nil
+	}
+
+	var found []service.Technology
+	index := make(map[string]int)
+	for _, sig := range sigs {
+		version, ok := sig.match(p)
+		if !ok {
+			continue
+		}
+		if i, seen := index[sig.name]; seen {
+			if found[i].Version == "" {
+				found[i].Version = version
+			}
+			continue
+		}
+		index[sig.name] = len(found)
+		found = append(found, service.Technology{Name: sig.name, Category: sig.category, Version: version})
+	}
+	return found
+}
+
+// retorna la version capturada (si la hay) y si hubo coincidencia
+func (s signature) match(p page) (string, bool) {
+	var subjects []string
+	switch s.where {
+	case "header":
+		subjects = p.header.Values(s.header)
+	case "cookie":
+		subjects = p.cookies
+	case "title":
+		subjects = []string{p.title}
+	case "body":
+		subjects = []string{p.body}
+	}
+
+	for _, subject := range subjects {
+		if m := s.re.FindStringSubmatch(subject); m != nil {
+			if len(m) > 1 {
+				return m[1], true
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,71 @@
+package http
+
+//VIRTUAL HOSTS -> misma IP:puerto, distinto Host/SNI
+import (
+	"context"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+)
+
+// bytes leidos por respuesta al comparar virtual hosts
+const maxVHostBody = 64 * 1024
+
+// pide la pagina con cada vhost y la compara con la del host por defecto
+// el fingerprint retornado es el del primer vhost que respondio
+func compareVHosts(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	base, info, scheme, err := fetchVHost(ctx, t, opts, "", schemeHint(t))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []service.VHostResponse{base}
+	named := false
+	for _, host := range t.VHosts {
+		r, vinfo, _, err := fetchVHost(ctx, t, opts, host, scheme)
+		if err != nil {
+			continue
+		}
+		r.Distinct = differs(base, r)
+		responses = append(responses, r)
+		if !named {
+			info, named = vinfo, true
+		}
+	}
+
+	if len(responses) > 1 {
+		info.VHosts = responses
+	}
+	return info, nil
+}
+
+// una respuesta resumida, su fingerprint y el esquema usado
+func fetchVHost(ctx context.Context, t probe.Target, opts probe.Options, host, scheme string) (service.VHostResponse, *service.ServiceInfo, string, error) {
+	resp, err := get(newClient(ctx, opts, host), t.Host, t.Port, host, scheme)
+	if err != nil {
+		return service.VHostResponse{}, nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxVHostBody))
+	r := service.VHostResponse{
+		Host:     host,
+		Status:   resp.StatusCode,
+		Title:    pageTitle(body),
+		Location: resp.Header.Get("Location"),
+		Length:   len(body),
+	}
+	return r, responseInfo(resp), resp.Request.URL.Scheme, nil
+}
+
+// status, redirect o titulo distintos, o un body de largo muy distinto (>10%)
+func differs(a, b service.VHostResponse) bool {
+	if a.Status != b.Status || a.Title != b.Title || a.Location != b.Location {
+		return true
+	}
+	delta := a.Length - b.Length
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta > 64 && delta*10 > max(a.Length, b.Length)
+}
@@ -0,0 +1,205 @@
+package infra
+
+//AMQP -> cabecera de protocolo 0-9-1 y lectura del Connection.Start (no se autentica)
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strings"
+)
+
+var amqpHeader = []byte("AMQP\x00\x00\x09\x01")
+
+type AMQPProbe struct{}
+
+func NewAMQPProbe() *AMQPProbe {
+	return &AMQPProbe{}
+}
+
+func (p *AMQPProbe) Spec() probe.Spec {
+	return spec("amqp", model.ProtocolTCP, service.ServiceAMQP, 5672)
+}
+
+func (p *AMQPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, "tcp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(amqpHeader); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 8)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+
+	info := newInfo(service.ServiceAMQP)
+	//si no soporta 0-9-1 el servidor responde con la cabecera de su version y cierra
+	if bytes.HasPrefix(head, []byte("AMQP")) {
+		details := &service.AMQPInfo{Protocol: amqpVersion(head)}
+		info.AMQP = details
+		info.ExtraInfo = "AMQP " + details.Protocol
+		return info, nil
+	}
+
+	//frame de metodo: tipo 1, canal 0, largo, payload y 0xCE
+	if head[0] != 1 {
+		return nil, fmt.Errorf("not an amqp frame: type %d", head[0])
+	}
+	size := binary.BigEndian.Uint32(head[3:7])
+	if size < 4 || size > 128*1024 {
+		return nil, fmt.Errorf("invalid amqp frame size %d", size)
+	}
+	payload := make([]byte, size+1)
+	payload[0] = head[7]
+	if _, err := io.ReadFull(conn, payload[1:]); err != nil {
+		return nil, err
+	}
+
+	details, props, err := parseConnectionStart(payload[:size])
+	if err != nil {
+		return nil, err
+	}
+	info.AMQP = details
+	info.Product = props["product"]
+	info.Version = props["version"]
+	if strings.EqualFold(info.Product, "RabbitMQ") && info.Version != "" {
+		info.CPE = append(info.CPE, "cpe:/a:vmware:rabbitmq:"+info.Version)
+	}
+	if details.Cluster != "" {
+		info.ExtraInfo = "cluster: " + details.Cluster
+	}
+	return info, nil
+}
+
+// "AMQP" 0 1 0 0 -> "1.0.0", "AMQP" 0 0 9 1 -> "0-9-1"
+func amqpVersion(head []byte) string {
+	if head[4] == 0 && head[5] == 0 {
+		return fmt.Sprintf("%d-%d-%d", head[5], head[6], head[7])
+	}
+	return fmt.Sprintf("%d.%d.%d", head[5], head[6], head[7])
+}
+
+// Connection.Start (clase 10, metodo 10): version, server-properties, mecanismos y locales
+func parseConnectionStart(p []byte) (*service.AMQPInfo, map[string]string, error) {
+	if len(p) < 6 || binary.BigEndian.Uint16(p[0:2]) != 10 || binary.BigEndian.Uint16(p[2:4]) != 10 {
+		return nil, nil, errors.New("not an amqp connection.start")
+	}
+	//el servidor acepto la version pedida en la cabecera
+	details := &service.AMQPInfo{Protocol: amqpVersion(amqpHeader)}
+
+	r := &amqpReader{data: p[6:]}
+	props := make(map[string]string)
+	if err := r.table(props); err != nil {
+		return nil, nil, err
+	}
+	mechanisms, err := r.longString()
+	if err != nil {
+		return nil, nil, err
+	}
+	details.Mechanisms = strings.Fields(mechanisms)
+	details.Platform = props["platform"]
+	details.Cluster = props["cluster_name"]
+	return details, props, nil
+}
+
+// lector de tipos AMQP; solo se guardan los valores de texto del primer nivel
+type amqpReader struct {
+	data []byte
+}
+
+func (r *amqpReader) take(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data) {
+		return nil, errors.New("truncated amqp field")
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+func (r *amqpReader) longString() (string, error) {
+	head, err := r.take(4)
+	if err != nil {
+		return "", err
+	}
+	b, err := r.take(int(binary.BigEndian.Uint32(head)))
+	return string(b), err
+}
+
+// tabla de campos; out es nil para tablas anidadas (solo se saltan)
+func (r *amqpReader) table(out map[string]string) error {
+	head, err := r.take(4)
+	if err != nil {
+		return err
+	}
+	body, err := r.take(int(binary.BigEndian.Uint32(head)))
+	if err != nil {
+		return err
+	}
+
+	t := &amqpReader{data: body}
+	for len(t.data) > 0 {
+		n, err := t.take(1)
+		if err != nil {
+			return err
+		}
+		name, err := t.take(int(n[0]))
+		if err != nil {
+			return err
+		}
+		value, err := t.value()
+		if err != nil {
+			return err
+		}
+		if out != nil && value != "" {
+			out[string(name)] = value
+		}
+	}
+	return nil
+}
+
+// valor de un campo; los tipos que no son texto se saltan
+func (r *amqpReader) value() (string, error) {
+	kind, err := r.take(1)
+	if err != nil {
+		return "", err
+	}
+	switch kind[0] {
+	case 'S', 'x':
+		return r.longString()
+	case 's':
+		n, err := r.take(1)
+		if err != nil {
+			return "", err
+		}
+		b, err := r.take(int(n[0]))
+		return string(b), err
+	case 'F':
+		return "", r.table(nil)
+	case 'A':
+		head, err := r.take(4)
+		if err != nil {
+			return "", err
+		}
+		_, err = r.take(int(binary.BigEndian.Uint32(head)))
+		return "", err
+	case 'V':
+		return "", nil
+	}
+	sizes := map[byte]int{'t': 1, 'b': 1, 'B': 1, 'U': 2, 'u': 2, 'I': 4, 'i': 4, 'f': 4, 'D': 5, 'L': 8, 'l': 8, 'd': 8, 'T': 8}
+	size, ok := sizes[kind[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown amqp field type %q", kind[0])
+	}
+	_, err = r.take(size)
+	return "", err
+}
@@ -0,0 +1,47 @@
+package infra
+
+//PROBERS DE INFRAESTRUCTURA -> identificacion de solo lectura (NTP, SIP, LDAP, Kerberos, MQTT, AMQP)
+import (
+	"context"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"regexp"
+)
+
+// conexion con deadline para todo el dialogo ("tcp" o "udp")
+func dial(ctx context.Context, opts probe.Options, network string, t probe.Target) (net.Conn, error) {
+	return opts.Dial(ctx, network, t.Address())
+}
+
+// el servicio identificado o su puerto habitual, en el grupo "infra"
+func spec(name string, proto model.Protocol, svc service.ServiceType, ports ...int) probe.Spec {
+	return probe.Spec{
+		Name:     name,
+		Protocol: proto,
+		Services: []service.ServiceType{svc},
+		Ports:    ports,
+		Groups:   []string{"infra"},
+	}
+}
+
+// fingerprint base de un servicio identificado por su protocolo
+func newInfo(svc service.ServiceType) *service.ServiceInfo {
+	return &service.ServiceInfo{
+		Type:       svc,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+	}
+}
+
+// "Asterisk PBX 18.0.0", "FreeSWITCH-mod_sofia/1.10.9", "OpenLDAP 2.6"
+var productVersion = regexp.MustCompile(`^([A-Za-z][\w .\-]*?)[/ -]v?(\d+(?:\.\d+)+[\w.\-~]*)`)
+
+// separa producto y version de un texto libre; sin version todo queda como producto
+func splitProduct(s string) (string, string) {
+	if m := productVersion.FindStringSubmatch(s); m != nil {
+		return m[1], m[2]
+	}
+	return s, ""
+}
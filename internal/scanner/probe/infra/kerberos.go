@@ -0,0 +1,216 @@
+package infra
+
+//KERBEROS -> AS-REQ sin pre-autenticacion para un usuario inexistente, se lee el KRB-ERROR
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/ber"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+// tags de aplicacion de los mensajes
+const (
+	krbASReq = 0x6a //[APPLICATION 10]
+	krbASRep = 0x6b //[APPLICATION 11]
+	krbError = 0x7e //[APPLICATION 30]
+)
+
+// realm usado cuando no se conoce ninguno; el KDC responde igual con un KRB-ERROR
+const placeholderRealm = "GO-SCANNER.INVALID"
+
+// codigos de error frecuentes
+var krbErrors = map[int]string{
+	6:  "KDC_ERR_C_PRINCIPAL_UNKNOWN",
+	7:  "KDC_ERR_S_PRINCIPAL_UNKNOWN",
+	14: "KDC_ERR_ETYPE_NOSUPP",
+	18: "KDC_ERR_CLIENT_REVOKED",
+	24: "KDC_ERR_PREAUTH_FAILED",
+	25: "KDC_ERR_PREAUTH_REQUIRED",
+	37: "KRB_AP_ERR_SKEW",
+	68: "KDC_ERR_WRONG_REALM",
+}
+
+// errores con los que el KDC demuestra que atiende el realm pedido
+var realmServed = map[int]bool{6: true, 14: true, 18: true, 24: true, 25: true}
+
+// prober Kerberos, uno por transporte
+type KerberosProbe struct {
+	network string //"udp" o "tcp"
+}
+
+func NewKerberosProbe(network string) *KerberosProbe {
+	return &KerberosProbe{network: network}
+}
+
+func (p *KerberosProbe) Spec() probe.Spec {
+	return spec("kerberos", model.Protocol(p.network), service.ServiceKerberos, 88)
+}
+
+// los realms candidatos salen de los nombres del host ("dc01.corp.example.com" -> "CORP.EXAMPLE.COM")
+func (p *KerberosProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	var details *service.KerberosInfo
+	for _, realm := range candidateRealms(t.VHosts) {
+		got, err := p.asRequest(ctx, opts, t, realm)
+		if err != nil {
+			if details == nil {
+				return nil, err
+			}
+			break
+		}
+		details = got
+		if details.Realm != "" {
+			break
+		}
+	}
+
+	info := newInfo(service.ServiceKerberos)
+	info.Kerberos = details
+	if details.Realm != "" {
+		info.ExtraInfo = "realm: " + details.Realm
+	}
+	return info, nil
+}
+
+func candidateRealms(names []string) []string {
+	var realms []string
+	seen := make(map[string]bool)
+	for _, n := range names {
+		n = strings.ToUpper(strings.TrimSuffix(n, "."))
+		labels := strings.Split(n, ".")
+		//el nombre sin el primer label (dominio) y el nombre completo
+		for _, r := range []string{strings.Join(labels[min(1, len(labels)-1):], "."), n} {
+			if strings.Contains(r, ".") && !seen[r] {
+				seen[r] = true
+				realms = append(realms, r)
+			}
+		}
+	}
+	return append(realms, placeholderRealm)
+}
+
+func (p *KerberosProbe) asRequest(ctx context.Context, opts probe.Options, t probe.Target, realm string) (*service.KerberosInfo, error) {
+	conn, err := dial(ctx, opts, p.network, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	req := asReq(realm)
+	if p.network == "tcp" {
+		req = append(binary.BigEndian.AppendUint32(nil, uint32(len(req))), req...)
+	}
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := readKerberos(conn, p.network)
+	if err != nil {
+		return nil, err
+	}
+	msg, _, err := ber.Parse(resp)
+	if err != nil {
+		return nil, err
+	}
+	switch msg.Tag {
+	case krbASRep:
+		//el usuario existe sin pre-autenticacion: el realm es valido
+		return &service.KerberosInfo{Realm: realm}, nil
+	case krbError:
+		return parseKrbError(msg, realm)
+	}
+	return nil, fmt.Errorf("unexpected kerberos message 0x%02x", msg.Tag)
+}
+
+func readKerberos(conn net.Conn, network string) ([]byte, error) {
+	if network == "udp" {
+		buf := make([]byte, 65535)
+		n, err := conn.Read(buf)
+		return buf[:n], err
+	}
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(head)
+	if length > 64*1024 {
+		return nil, errors.New("kerberos message too large")
+	}
+	buf := make([]byte, length)
+	_, err := io.ReadFull(conn, buf)
+	return buf, err
+}
+
+// AS-REQ para krbtgt/REALM con un cliente aleatorio y sin padata
+func asReq(realm string) []byte {
+	user := fmt.Sprintf("gs%08x", rand.Uint32())
+	kstr := func(s string) []byte { return ber.TLV(0x1b, []byte(s)) } //GeneralString
+	ctx := func(n byte, v []byte) []byte { return ber.Constructed(0xa0|n, v) }
+	principal := func(kind int64, names ...string) []byte {
+		var parts [][]byte
+		for _, n := range names {
+			parts = append(parts, kstr(n))
+		}
+		return ber.Sequence(ctx(0, ber.Integer(kind)), ctx(1, ber.Sequence(parts...)))
+	}
+
+	body := ber.Sequence(
+		ctx(0, ber.TLV(0x03, []byte{0x00, 0x50, 0x80, 0x00, 0x10})), //forwardable, proxiable, renewable, renewable-ok
+		ctx(1, principal(1, user)),                                  //NT-PRINCIPAL
+		ctx(2, kstr(realm)),
+		ctx(3, principal(2, "krbtgt", realm)), //NT-SRV-INST
+		ctx(5, ber.TLV(0x18, []byte(time.Now().UTC().Add(24*time.Hour).Format("20060102150405Z")))),
+		ctx(7, ber.Integer(int64(rand.Uint32()>>1))),
+		ctx(8, ber.Sequence(ber.Integer(18), ber.Integer(17), ber.Integer(23))), //AES256, AES128, RC4
+	)
+	return ber.Constructed(krbASReq, ber.Sequence(
+		ctx(1, ber.Integer(5)),  //pvno
+		ctx(2, ber.Integer(10)), //AS-REQ
+		ctx(4, body),
+	))
+}
+
+// KRB-ERROR: stime [4], error-code [6] y realm [9]
+func parseKrbError(msg ber.Element, requested string) (*service.KerberosInfo, error) {
+	seq, _, err := ber.Parse(msg.Value)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := seq.Children()
+	if err != nil {
+		return nil, err
+	}
+
+	details := &service.KerberosInfo{ErrorCode: -1}
+	for _, f := range fields {
+		inner, _, err := ber.Parse(f.Value)
+		if err != nil {
+			continue
+		}
+		switch f.Tag {
+		case 0xa4:
+			if t, err := time.Parse("20060102150405Z", string(inner.Value)); err == nil {
+				details.ServerTime = t.UTC().Format(time.RFC3339)
+			}
+		case 0xa6:
+			code, _ := inner.Int()
+			details.ErrorCode = int(code)
+		}
+	}
+	if details.ErrorCode < 0 {
+		return nil, errors.New("krb-error without error code")
+	}
+	details.ErrorName = krbErrors[details.ErrorCode]
+	if realmServed[details.ErrorCode] && requested != placeholderRealm {
+		details.Realm = requested
+	}
+	return details, nil
+}
@@ -0,0 +1,156 @@
+package infra
+
+//LDAP -> busqueda anonima del rootDSE (base "", scope base), sin bind
+import (
+	"context"
+	"errors"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/ber"
+	"go-scanner/internal/scanner/service"
+	"strings"
+)
+
+// operaciones LDAP (APPLICATION n)
+const (
+	ldapSearchRequest = 0x63
+	ldapSearchEntry   = 0x64
+	ldapSearchDone    = 0x65
+)
+
+// atributos operacionales pedidos al rootDSE
+var rootDSEAttributes = []string{
+	"namingContexts", "defaultNamingContext", "dnsHostName",
+	"vendorName", "vendorVersion", "supportedLDAPVersion", "supportedSASLMechanisms",
+}
+
+type LDAPProbe struct{}
+
+func NewLDAPProbe() *LDAPProbe {
+	return &LDAPProbe{}
+}
+
+func (p *LDAPProbe) Spec() probe.Spec {
+	return spec("ldap", model.ProtocolTCP, service.ServiceLDAP, 389)
+}
+
+func (p *LDAPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, "tcp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(rootDSERequest()); err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string][]string)
+	for {
+		raw, err := ber.Read(conn, 256*1024)
+		if err != nil {
+			return nil, err
+		}
+		msg, _, err := ber.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := msg.Children()
+		if err != nil || len(fields) < 2 {
+			return nil, errors.New("malformed ldap message")
+		}
+		op := fields[1]
+		if op.Tag == ldapSearchDone {
+			break
+		}
+		if op.Tag == ldapSearchEntry {
+			parseEntry(op, attrs)
+		}
+	}
+	if len(attrs) == 0 {
+		return nil, errors.New("empty rootDSE")
+	}
+
+	details := &service.LDAPInfo{
+		NamingContexts:       attrs["namingcontexts"],
+		DefaultNamingContext: first(attrs["defaultnamingcontext"]),
+		DNSHostName:          first(attrs["dnshostname"]),
+		VendorName:           first(attrs["vendorname"]),
+		VendorVersion:        first(attrs["vendorversion"]),
+		LDAPVersions:         attrs["supportedldapversion"],
+		SASLMechanisms:       attrs["supportedsaslmechanisms"],
+	}
+	info := newInfo(service.ServiceLDAP)
+	info.LDAP = details
+
+	switch {
+	case details.DNSHostName != "":
+		//solo Active Directory publica dnsHostName en el rootDSE
+		info.Product = "Microsoft Active Directory LDAP"
+		info.OSHint = "Windows"
+		info.Hostname = details.DNSHostName
+	case details.VendorName != "":
+		info.Product = details.VendorName
+		if _, v := splitProduct(details.VendorVersion); v != "" {
+			info.Version = v
+		}
+	}
+	if details.DefaultNamingContext != "" {
+		info.ExtraInfo = "Domain: " + details.DefaultNamingContext
+	} else if len(details.NamingContexts) > 0 {
+		info.ExtraInfo = "Context: " + details.NamingContexts[0]
+	}
+	return info, nil
+}
+
+// SearchRequest del rootDSE con filtro (objectClass=*), messageID 1
+func rootDSERequest() []byte {
+	var attrs [][]byte
+	for _, a := range rootDSEAttributes {
+		attrs = append(attrs, ber.OctetString([]byte(a)))
+	}
+	search := ber.Constructed(ldapSearchRequest,
+		ber.OctetString(nil),                 //baseObject ""
+		ber.Enumerated(0),                    //scope baseObject
+		ber.Enumerated(0),                    //derefAliases never
+		ber.Integer(0),                       //sizeLimit
+		ber.Integer(0),                       //timeLimit
+		[]byte{0x01, 0x01, 0x00},             //typesOnly false
+		ber.TLV(0x87, []byte("objectClass")), //filtro present
+		ber.Sequence(attrs...),
+	)
+	return ber.Sequence(ber.Integer(1), search)
+}
+
+// SearchResultEntry: objectName y una lista de (tipo, SET de valores)
+func parseEntry(op ber.Element, attrs map[string][]string) {
+	parts, err := op.Children()
+	if err != nil || len(parts) < 2 {
+		return
+	}
+	list, err := parts[1].Children()
+	if err != nil {
+		return
+	}
+	for _, a := range list {
+		pair, err := a.Children()
+		if err != nil || len(pair) != 2 {
+			continue
+		}
+		values, err := pair[1].Children()
+		if err != nil {
+			continue
+		}
+		name := strings.ToLower(string(pair[0].Value))
+		for _, v := range values {
+			attrs[name] = append(attrs[name], string(v.Value))
+		}
+	}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
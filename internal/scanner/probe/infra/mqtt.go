@@ -0,0 +1,76 @@
+package infra
+
+//MQTT -> CONNECT sin credenciales (3.1.1), se lee el CONNACK y se desconecta sin suscribirse
+import (
+	"context"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"math/rand"
+)
+
+// codigos de retorno del CONNACK
+var mqttReturnCodes = map[int]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad username or password",
+	5: "not authorized",
+}
+
+type MQTTProbe struct{}
+
+func NewMQTTProbe() *MQTTProbe {
+	return &MQTTProbe{}
+}
+
+func (p *MQTTProbe) Spec() probe.Spec {
+	return spec("mqtt", model.ProtocolTCP, service.ServiceMQTT, 1883)
+}
+
+func (p *MQTTProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, "tcp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(mqttConnect(fmt.Sprintf("go-scanner-%04x", rand.Intn(0x10000)))); err != nil {
+		return nil, err
+	}
+
+	//CONNACK: 0x20, largo 2, flags de sesion y codigo de retorno
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		return nil, err
+	}
+	if ack[0] != 0x20 || ack[1] != 0x02 {
+		return nil, fmt.Errorf("not an mqtt connack: % x", ack[:2])
+	}
+
+	details := &service.MQTTInfo{ProtocolLevel: 4, ReturnCode: int(ack[3])}
+	details.Anonymous = details.ReturnCode == 0
+	if details.Anonymous {
+		conn.Write([]byte{0xe0, 0x00}) //DISCONNECT
+	}
+
+	info := newInfo(service.ServiceMQTT)
+	info.MQTT = details
+	switch {
+	case details.Anonymous:
+		info.ExtraInfo = "anonymous access allowed"
+	case mqttReturnCodes[details.ReturnCode] != "":
+		info.ExtraInfo = mqttReturnCodes[details.ReturnCode]
+	}
+	return info, nil
+}
+
+// CONNECT 3.1.1 con clean session y keepalive de 10s
+func mqttConnect(clientID string) []byte {
+	body := []byte{0x00, 0x04, 'M', 'Q', 'T', 'T', 0x04, 0x02, 0x00, 0x0a}
+	body = append(body, byte(len(clientID)>>8), byte(len(clientID)))
+	body = append(body, clientID...)
+	return append([]byte{0x10, byte(len(body))}, body...)
+}
@@ -0,0 +1,209 @@
+package infra
+
+//NTP -> modo 3 (cliente), READVAR de modo 6 y MON_GETLIST de modo 7 (solo lectura)
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// modos y codigos NTP
+const (
+	ntpModeServer  = 4
+	ntpModeControl = 6
+	ntpModePrivate = 7
+
+	ntpOpReadVar      = 2
+	ntpImplXNTPD      = 3
+	ntpReqMonGetList1 = 42
+)
+
+// variables de READVAR ("version=\"ntpd 4.2.8p15\", system=\"Linux/5.15\"")
+var ntpVariable = regexp.MustCompile(`(\w+)=("([^"]*)"|[^,\s]*)`)
+
+type NTPProbe struct{}
+
+func NewNTPProbe() *NTPProbe {
+	return &NTPProbe{}
+}
+
+func (p *NTPProbe) Spec() probe.Spec {
+	return spec("ntp", model.ProtocolUDP, service.ServiceNTP, 123)
+}
+
+// las tres consultas son independientes y cada una espera el timeout si no hay respuesta
+func (p *NTPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	details := &service.NTPInfo{}
+	var vars map[string]string
+	var clientErr error
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		vars, _ = ntpReadVar(ctx, opts, t)
+	}()
+	go func() {
+		defer wg.Done()
+		details.Monlist = ntpMonlist(ctx, opts, t)
+	}()
+	clientErr = ntpClient(ctx, opts, t, details)
+	wg.Wait()
+
+	if clientErr != nil && vars == nil && !details.Monlist {
+		return nil, clientErr
+	}
+
+	info := newInfo(service.ServiceNTP)
+	info.NTP = details
+	if vars != nil {
+		details.System = vars["system"]
+		details.Daemon = vars["version"]
+		if strings.HasPrefix(details.Daemon, "ntpd ") {
+			info.Product = "NTP"
+			//"ntpd 4.2.8p15@1.3728-o Wed Sep 23 11:46:38 UTC 2020 (1)"
+			version, _, _ := strings.Cut(strings.TrimPrefix(details.Daemon, "ntpd "), " ")
+			info.Version, _, _ = strings.Cut(version, "@")
+			info.CPE = append(info.CPE, "cpe:/a:ntp:ntp:"+info.Version)
+		}
+		if strings.HasPrefix(details.System, "Linux") {
+			info.OSHint = "Linux"
+		}
+	}
+	if details.Version > 0 {
+		info.ExtraInfo = fmt.Sprintf("v%d, stratum %d", details.Version, details.Stratum)
+	}
+	return info, nil
+}
+
+// modo 3: la respuesta trae version, stratum y referencia
+func ntpClient(ctx context.Context, opts probe.Options, t probe.Target, details *service.NTPInfo) error {
+	conn, err := dial(ctx, opts, "udp", t)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := make([]byte, 48)
+	req[0] = 0<<6 | 4<<3 | 3 //LI 0, VN 4, modo cliente
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return err
+	}
+	if n < 48 || buf[0]&0x07 != ntpModeServer {
+		return errors.New("not an ntp server response")
+	}
+
+	details.Version = int(buf[0]>>3) & 0x07
+	details.Stratum = int(buf[1])
+	ref := buf[12:16]
+	if details.Stratum <= 1 {
+		details.RefID = strings.TrimRight(string(ref), "\x00")
+	} else {
+		details.RefID = net.IP(ref).String()
+	}
+	return nil
+}
+
+// modo 6 READVAR sin asociacion: variables del sistema, puede venir en varios fragmentos
+func ntpReadVar(ctx context.Context, opts probe.Options, t probe.Target) (map[string]string, error) {
+	conn, err := dial(ctx, opts, "udp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	req := make([]byte, 12)
+	req[0] = 0<<6 | 2<<3 | ntpModeControl
+	req[1] = ntpOpReadVar
+	binary.BigEndian.PutUint16(req[2:4], 1) //sequence
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	type fragment struct {
+		offset int
+		data   []byte
+	}
+	var fragments []fragment
+	received, total := 0, -1 //total se conoce al llegar el ultimo fragmento (sin bit "more")
+	buf := make([]byte, 2048)
+	for total < 0 || received < total {
+		n, err := conn.Read(buf)
+		if err != nil {
+			if len(fragments) == 0 {
+				return nil, err
+			}
+			break
+		}
+		if n < 12 || buf[0]&0x07 != ntpModeControl || buf[1]&0x80 == 0 || buf[1]&0x1f != ntpOpReadVar {
+			continue
+		}
+		if buf[1]&0x40 != 0 {
+			return nil, errors.New("readvar error")
+		}
+		offset := int(binary.BigEndian.Uint16(buf[8:10]))
+		count := int(binary.BigEndian.Uint16(buf[10:12]))
+		if 12+count > n {
+			count = n - 12
+		}
+		if buf[1]&0x20 == 0 {
+			total = offset + count
+		}
+		received += count
+		fragments = append(fragments, fragment{offset, append([]byte(nil), buf[12:12+count]...)})
+	}
+	sort.Slice(fragments, func(i, j int) bool { return fragments[i].offset < fragments[j].offset })
+
+	var text strings.Builder
+	for _, f := range fragments {
+		text.Write(f.data)
+	}
+	vars := make(map[string]string)
+	for _, m := range ntpVariable.FindAllStringSubmatch(text.String(), -1) {
+		v := m[2]
+		if strings.HasPrefix(v, `"`) {
+			v = m[3]
+		}
+		vars[m[1]] = v
+	}
+	return vars, nil
+}
+
+// modo 7 MON_GETLIST_1: basta con que el servidor responda sin error (no se lee la lista)
+func ntpMonlist(ctx context.Context, opts probe.Options, t probe.Target) bool {
+	conn, err := dial(ctx, opts, "udp", t)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	req := make([]byte, 48)
+	req[0] = 0<<6 | 2<<3 | ntpModePrivate
+	req[2] = ntpImplXNTPD
+	req[3] = ntpReqMonGetList1
+	if _, err := conn.Write(req); err != nil {
+		return false
+	}
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil || n < 8 {
+		return false
+	}
+	isResponse := buf[0]&0x80 != 0 && buf[0]&0x07 == ntpModePrivate
+	return isResponse && buf[3] == ntpReqMonGetList1 && buf[4]>>4 == 0
+}
@@ -0,0 +1,111 @@
+package infra
+
+//SIP -> OPTIONS (no inicia llamadas ni registra), por UDP o TCP
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"math/rand"
+	"net"
+	"net/textproto"
+	"strings"
+)
+
+// prober SIP, uno por transporte
+type SIPProbe struct {
+	network string //"udp" o "tcp"
+}
+
+func NewSIPProbe(network string) *SIPProbe {
+	return &SIPProbe{network: network}
+}
+
+func (p *SIPProbe) Spec() probe.Spec {
+	return spec("sip", model.Protocol(p.network), service.ServiceSIP, 5060)
+}
+
+func (p *SIPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, p.network, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(sipOptions(conn, p.network, t.Host))); err != nil {
+		return nil, err
+	}
+
+	var r *bufio.Reader
+	if p.network == "udp" {
+		buf := make([]byte, 8192)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		r = bufio.NewReader(strings.NewReader(string(buf[:n])))
+	} else {
+		r = bufio.NewReader(conn)
+	}
+
+	tp := textproto.NewReader(r)
+	status, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(status, "SIP/2.0 ") {
+		return nil, fmt.Errorf("not a sip response: %q", status)
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil && len(header) == 0 {
+		return nil, errors.New("malformed sip headers")
+	}
+
+	details := &service.SIPInfo{Status: strings.TrimPrefix(status, "SIP/2.0 ")}
+	details.Server = header.Get("Server")
+	if details.Server == "" {
+		details.Server = header.Get("User-Agent")
+	}
+	for _, m := range strings.Split(header.Get("Allow"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			details.Allow = append(details.Allow, m)
+		}
+	}
+
+	info := newInfo(service.ServiceSIP)
+	info.SIP = details
+	if details.Server != "" {
+		info.Product, info.Version = splitProduct(details.Server)
+	}
+	return info, nil
+}
+
+// OPTIONS minimo con Via/From/To/Call-ID/CSeq (RFC 3261)
+func sipOptions(conn net.Conn, network, target string) string {
+	local := conn.LocalAddr().String()
+	branch := fmt.Sprintf("z9hG4bK%08x", rand.Uint32())
+	tag := fmt.Sprintf("%08x", rand.Uint32())
+	callID := fmt.Sprintf("%016x@go-scanner", rand.Uint64())
+	host := target
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	lines := []string{
+		"OPTIONS sip:" + host + " SIP/2.0",
+		"Via: SIP/2.0/" + strings.ToUpper(network) + " " + local + ";branch=" + branch + ";rport",
+		"Max-Forwards: 70",
+		"From: <sip:scanner@" + host + ">;tag=" + tag,
+		"To: <sip:" + host + ">",
+		"Call-ID: " + callID,
+		"CSeq: 1 OPTIONS",
+		"Contact: <sip:scanner@" + local + ">",
+		"Accept: application/sdp",
+		"User-Agent: go-scanner",
+		"Content-Length: 0",
+	}
+	return strings.Join(lines, "\r\n") + "\r\n\r\n"
+}
@@ -0,0 +1,245 @@
+package jarm
+
+//CLIENT HELLOS DE JARM (compatibles con la implementacion de referencia)
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mrand "math/rand/v2"
+)
+
+// parametros de cada uno de los 10 ClientHello
+type helloSpec struct {
+	version     string //TLS_1.1, TLS_1.2, TLS_1.3
+	ciphers     string //ALL o NO1.3
+	cipherOrder string //FORWARD, REVERSE, TOP_HALF, BOTTOM_HALF, MIDDLE_OUT
+	grease      bool
+	rareALPN    bool
+	support     string //1.2_SUPPORT, 1.3_SUPPORT, NO_SUPPORT
+	extOrder    string //orden de ALPN y supported_versions
+}
+
+// orden fijo de los probes, el hash depende de el
+var specs = []helloSpec{
+	{"TLS_1.2", "ALL", "FORWARD", false, false, "1.2_SUPPORT", "REVERSE"},
+	{"TLS_1.2", "ALL", "REVERSE", false, false, "1.2_SUPPORT", "FORWARD"},
+	{"TLS_1.2", "ALL", "TOP_HALF", false, false, "NO_SUPPORT", "FORWARD"},
+	{"TLS_1.2", "ALL", "BOTTOM_HALF", false, true, "NO_SUPPORT", "FORWARD"},
+	{"TLS_1.2", "ALL", "MIDDLE_OUT", true, true, "NO_SUPPORT", "REVERSE"},
+	{"TLS_1.1", "ALL", "FORWARD", false, false, "NO_SUPPORT", "FORWARD"},
+	{"TLS_1.3", "ALL", "FORWARD", false, false, "1.3_SUPPORT", "REVERSE"},
+	{"TLS_1.3", "ALL", "REVERSE", false, false, "1.3_SUPPORT", "FORWARD"},
+	{"TLS_1.3", "NO1.3", "FORWARD", false, false, "1.3_SUPPORT", "FORWARD"},
+	{"TLS_1.3", "ALL", "MIDDLE_OUT", true, false, "1.3_SUPPORT", "REVERSE"},
+}
+
+// suites ofrecidas (orden FORWARD)
+var allCiphers = []uint16{
+	0x0016, 0x0033, 0x0067, 0xc09e, 0xc0a2, 0x009e, 0x0039, 0x006b, 0xc09f, 0xc0a3,
+	0x009f, 0x0045, 0x00be, 0x0088, 0x00c4, 0x009a, 0xc008, 0xc009, 0xc023, 0xc0ac,
+	0xc0ae, 0xc02b, 0xc00a, 0xc024, 0xc0ad, 0xc0af, 0xc02c, 0xc072, 0xc073, 0xcca9,
+	0x1302, 0x1301, 0xcc14, 0xc007, 0xc012, 0xc013, 0xc027, 0xc02f, 0xc014, 0xc028,
+	0xc030, 0xc060, 0xc061, 0xc076, 0xc077, 0xcca8, 0x1305, 0x1304, 0x1303, 0xcc13,
+	0xc011, 0x000a, 0x002f, 0x003c, 0xc09c, 0xc0a0, 0x009c, 0x0035, 0x003d, 0xc09d,
+	0xc0a1, 0x009d, 0x0041, 0x00ba, 0x0084, 0x00c0, 0x0007, 0x0004, 0x0005,
+}
+
+// ALPN comunes y raros
+var (
+	commonALPN = []string{"http/0.9", "http/1.0", "http/1.1", "spdy/1", "spdy/2", "spdy/3", "h2", "h2c", "hq"}
+	rareALPN   = []string{"http/0.9", "http/1.0", "spdy/1", "spdy/2", "spdy/3", "h2c", "hq"}
+)
+
+// valores GREASE (RFC 8701)
+func grease() []byte {
+	b := byte(mrand.IntN(16))<<4 | 0x0a
+	return []byte{b, b}
+}
+
+// reordena una lista segun el modo de JARM
+func mung[T any](items []T, order string) []T {
+	n := len(items)
+	var out []T
+
+	switch order {
+	case "REVERSE":
+		for i := n - 1; i >= 0; i-- {
+			out = append(out, items[i])
+		}
+	case "BOTTOM_HALF":
+		if n%2 == 1 {
+			out = append(out, items[n/2+1:]...)
+		} else {
+			out = append(out, items[n/2:]...)
+		}
+	case "TOP_HALF":
+		if n%2 == 1 {
+			out = append(out, items[n/2])
+		}
+		out = append(out, mung(mung(items, "REVERSE"), "BOTTOM_HALF")...)
+	case "MIDDLE_OUT":
+		middle := n / 2
+		if n%2 == 1 {
+			out = append(out, items[middle])
+			for i := 1; i <= middle; i++ {
+				out = append(out, items[middle+i], items[middle-i])
+			}
+		} else {
+			for i := 1; i <= middle; i++ {
+				out = append(out, items[middle-1+i], items[middle-i])
+			}
+		}
+	default:
+		out = append(out, items...)
+	}
+	return out
+}
+
+func u16(v int) []byte {
+	return binary.BigEndian.AppendUint16(nil, uint16(v))
+}
+
+func random(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// construye el registro TLS con el ClientHello del spec
+func buildHello(spec helloSpec, host string) []byte {
+	record, hello := []byte{0x16, 0x03, 0x03}, []byte{0x03, 0x03}
+	switch spec.version {
+	case "TLS_1.3":
+		record, hello = []byte{0x16, 0x03, 0x01}, []byte{0x03, 0x03}
+	case "TLS_1.1":
+		record, hello = []byte{0x16, 0x03, 0x02}, []byte{0x03, 0x02}
+	}
+
+	hello = append(hello, random(32)...)
+	hello = append(hello, 32)
+	hello = append(hello, random(32)...) //session id
+
+	suites := buildCiphers(spec)
+	hello = append(hello, u16(len(suites))...)
+	hello = append(hello, suites...)
+	hello = append(hello, 0x01, 0x00) //compresion: null
+	hello = append(hello, buildExtensions(spec, host)...)
+
+	handshake := append([]byte{0x01, 0x00}, u16(len(hello))...)
+	handshake = append(handshake, hello...)
+
+	record = append(record, u16(len(handshake))...)
+	return append(record, handshake...)
+}
+
+func buildCiphers(spec helloSpec) []byte {
+	list := allCiphers
+	if spec.ciphers == "NO1.3" {
+		list = nil
+		for _, c := range allCiphers {
+			if c>>8 != 0x13 {
+				list = append(list, c)
+			}
+		}
+	}
+	list = mung(list, spec.cipherOrder)
+
+	var out []byte
+	if spec.grease {
+		out = append(out, grease()...)
+	}
+	for _, c := range list {
+		out = append(out, u16(int(c))...)
+	}
+	return out
+}
+
+func buildExtensions(spec helloSpec, host string) []byte {
+	var ext []byte
+	if spec.grease {
+		ext = append(ext, grease()...)
+		ext = append(ext, 0x00, 0x00)
+	}
+
+	//server_name
+	ext = append(ext, 0x00, 0x00)
+	ext = append(ext, u16(len(host)+5)...)
+	ext = append(ext, u16(len(host)+3)...)
+	ext = append(ext, 0x00)
+	ext = append(ext, u16(len(host))...)
+	ext = append(ext, host...)
+
+	//extended_master_secret, max_fragment_length, renegotiation_info
+	ext = append(ext, 0x00, 0x17, 0x00, 0x00)
+	ext = append(ext, 0x00, 0x01, 0x00, 0x01, 0x01)
+	ext = append(ext, 0xff, 0x01, 0x00, 0x01, 0x00)
+	//supported_groups (x25519, secp256r1, secp384r1, secp521r1), ec_point_formats, session_ticket
+	ext = append(ext, 0x00, 0x0a, 0x00, 0x0a, 0x00, 0x08, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18, 0x00, 0x19)
+	ext = append(ext, 0x00, 0x0b, 0x00, 0x02, 0x01, 0x00)
+	ext = append(ext, 0x00, 0x23, 0x00, 0x00)
+	ext = append(ext, buildALPN(spec)...)
+	//signature_algorithms
+	ext = append(ext, 0x00, 0x0d, 0x00, 0x14, 0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01,
+		0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01, 0x02, 0x01)
+	ext = append(ext, buildKeyShare(spec.grease)...)
+	ext = append(ext, 0x00, 0x2d, 0x00, 0x02, 0x01, 0x01) //psk_key_exchange_modes
+	if spec.version == "TLS_1.3" || spec.support == "1.2_SUPPORT" {
+		ext = append(ext, buildSupportedVersions(spec)...)
+	}
+
+	return append(u16(len(ext)), ext...)
+}
+
+func buildALPN(spec helloSpec) []byte {
+	names := commonALPN
+	if spec.rareALPN {
+		names = rareALPN
+	}
+	names = mung(names, spec.extOrder)
+
+	var list []byte
+	for _, n := range names {
+		list = append(list, byte(len(n)))
+		list = append(list, n...)
+	}
+
+	out := []byte{0x00, 0x10}
+	out = append(out, u16(len(list)+2)...)
+	out = append(out, u16(len(list))...)
+	return append(out, list...)
+}
+
+func buildKeyShare(withGrease bool) []byte {
+	var share []byte
+	if withGrease {
+		share = append(share, grease()...)
+		share = append(share, 0x00, 0x01, 0x00)
+	}
+	share = append(share, 0x00, 0x1d, 0x00, 0x20) //x25519
+	share = append(share, random(32)...)
+
+	out := []byte{0x00, 0x33}
+	out = append(out, u16(len(share)+2)...)
+	out = append(out, u16(len(share))...)
+	return append(out, share...)
+}
+
+func buildSupportedVersions(spec helloSpec) []byte {
+	versions := [][]byte{{0x03, 0x01}, {0x03, 0x02}, {0x03, 0x03}, {0x03, 0x04}}
+	if spec.support == "1.2_SUPPORT" {
+		versions = versions[:3]
+	}
+	versions = mung(versions, spec.extOrder)
+	if spec.grease {
+		versions = append([][]byte{grease()}, versions...)
+	}
+
+	var list []byte
+	for _, v := range versions {
+		list = append(list, v...)
+	}
+
+	out := []byte{0x00, 0x2b}
+	out = append(out, u16(len(list)+1)...)
+	out = append(out, byte(len(list)))
+	return append(out, list...)
+}
@@ -0,0 +1,107 @@
+package jarm
+
+//JA3S -> md5 de "version,cipher,extensiones" del ServerHello
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// conexion que guarda lo recibido hasta el ServerHello
+type recordingConn struct {
+	net.Conn
+	received []byte
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if len(c.received) < 16*1024 {
+		c.received = append(c.received, b[:n]...)
+	}
+	return n, err
+}
+
+// JA3S del ServerHello ante un ClientHello estandar (el de crypto/tls)
+func JA3S(ctx context.Context, opts probe.Options, address, target string) (string, error) {
+	raw, err := opts.Dial(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer raw.Close()
+
+	conn := &recordingConn{Conn: raw}
+	config := opts.TLSConfig(target)
+	config.MinVersion = tls.VersionTLS10
+
+	//el handshake puede fallar despues del ServerHello, lo grabado alcanza
+	tls.Client(conn, config).Handshake()
+
+	hello, err := serverHello(conn.received)
+	if err != nil {
+		return "", err
+	}
+	return ja3sFromHello(hello)
+}
+
+// junta los registros de handshake y retorna el cuerpo del primer mensaje (ServerHello)
+func serverHello(data []byte) ([]byte, error) {
+	var handshake []byte
+	for len(data) >= 5 {
+		length := int(binary.BigEndian.Uint16(data[3:5]))
+		if len(data) < 5+length {
+			break
+		}
+		if data[0] == 0x16 {
+			handshake = append(handshake, data[5:5+length]...)
+		}
+		data = data[5+length:]
+
+		if len(handshake) >= 4 {
+			msgLen := int(handshake[1])<<16 | int(handshake[2])<<8 | int(handshake[3])
+			if handshake[0] != 0x02 {
+				return nil, errors.New("first handshake message is not a server hello")
+			}
+			if len(handshake) >= 4+msgLen {
+				return handshake[4 : 4+msgLen], nil
+			}
+		}
+	}
+	return nil, errors.New("no server hello received")
+}
+
+// version,cipher,ext1-ext2-... en decimal
+func ja3sFromHello(h []byte) (string, error) {
+	if len(h) < 38 {
+		return "", errors.New("short server hello")
+	}
+	version := binary.BigEndian.Uint16(h[0:2])
+	sidLen := int(h[34])
+	pos := 35 + sidLen
+	if len(h) < pos+3 {
+		return "", errors.New("short server hello")
+	}
+	cipher := binary.BigEndian.Uint16(h[pos : pos+2])
+	pos += 3 //cipher + compresion
+
+	var exts []string
+	if len(h) >= pos+2 {
+		end := pos + 2 + int(binary.BigEndian.Uint16(h[pos:pos+2]))
+		pos += 2
+		for pos+4 <= end && pos+4 <= len(h) {
+			exts = append(exts, strconv.Itoa(int(binary.BigEndian.Uint16(h[pos:pos+2]))))
+			pos += 4 + int(binary.BigEndian.Uint16(h[pos+2:pos+4]))
+		}
+	}
+
+	text := fmt.Sprintf("%d,%d,%s", version, cipher, strings.Join(exts, "-"))
+	sum := md5.Sum([]byte(text))
+	return hex.EncodeToString(sum[:]), nil
+}
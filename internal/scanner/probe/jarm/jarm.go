@@ -0,0 +1,220 @@
+package jarm
+
+//FINGERPRINT JARM Y JA3S DEL STACK TLS DEL SERVIDOR
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strings"
+)
+
+// fingerprint vacio: ningun ClientHello obtuvo ServerHello
+var emptyJARM = strings.Repeat("0", 62)
+
+// suites en el orden usado para codificar el hash
+var hashCiphers = []uint16{
+	0x0004, 0x0005, 0x0007, 0x000a, 0x0016, 0x002f, 0x0033, 0x0035, 0x0039, 0x003c,
+	0x003d, 0x0041, 0x0045, 0x0067, 0x006b, 0x0084, 0x0088, 0x009a, 0x009c, 0x009d,
+	0x009e, 0x009f, 0x00ba, 0x00be, 0x00c0, 0x00c4, 0xc007, 0xc008, 0xc009, 0xc00a,
+	0xc011, 0xc012, 0xc013, 0xc014, 0xc023, 0xc024, 0xc027, 0xc028, 0xc02b, 0xc02c,
+	0xc02f, 0xc030, 0xc060, 0xc061, 0xc072, 0xc073, 0xc076, 0xc077, 0xc09c, 0xc09d,
+	0xc09e, 0xc09f, 0xc0a0, 0xc0a1, 0xc0a2, 0xc0a3, 0xc0ac, 0xc0ad, 0xc0ae, 0xc0af,
+	0xcc13, 0xcc14, 0xcca8, 0xcca9, 0x1301, 0x1302, 0x1303, 0x1304, 0x1305,
+}
+
+// prober de fingerprint TLS (JARM + JA3S)
+type JARMProbe struct{}
+
+func NewJARMProbe() *JARMProbe {
+	return &JARMProbe{}
+}
+
+// despues de tls, sobre cualquier puerto TCP donde se detecto TLS
+func (p *JARMProbe) Spec() probe.Spec {
+	return probe.Spec{Name: "jarm", Protocol: model.ProtocolTCP, Requires: []string{"tls"}}
+}
+
+// solo TLS directo: tras un STARTTLS los ClientHello crudos no tienen sentido
+func (p *JARMProbe) Match(t probe.Target) bool {
+	return t.Service.TLSInfo != nil && t.Service.StartTLS == nil
+}
+
+// envia los 10 ClientHello de JARM y un handshake estandar para JA3S
+// los hashes se etiquetan con la lista de conocidos (incluida y la del usuario)
+func (p *JARMProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	address := t.Address()
+	host := t.Hostname()
+	if host == "" {
+		host = t.Host
+	}
+
+	raw := make([]string, len(specs))
+	for i, spec := range specs {
+		raw[i] = parseServerHello(exchange(ctx, opts, address, buildHello(spec, host)))
+	}
+
+	fp := &service.TLSFingerprint{JARM: Hash(raw)}
+	fp.JA3S, _ = JA3S(ctx, opts, address, host)
+
+	if fp.JARM == emptyJARM && fp.JA3S == "" {
+		return nil, errors.New("no tls server hello received")
+	}
+	if known, err := LoadKnown(opts.TLSFingerprints); err == nil {
+		fp.Labels = append(known.Labels(fp.JARM), known.Labels(fp.JA3S)...)
+	}
+
+	return &service.ServiceInfo{
+		Method:         service.MethodProbe,
+		TLS:            true,
+		TLSFingerprint: fp,
+		Confidence:     model.ConfidenceHigh,
+	}, nil
+}
+
+// envia un ClientHello crudo y lee el primer registro de respuesta
+func exchange(ctx context.Context, opts probe.Options, address string, hello []byte) []byte {
+	conn, err := opts.Dial(ctx, "tcp", address)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(hello); err != nil {
+		return nil
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil
+	}
+	body := make([]byte, binary.BigEndian.Uint16(head[3:5]))
+	n, _ := io.ReadFull(conn, body)
+	return append(head, body[:n]...)
+}
+
+// "cipher|version|alpn|extensiones" del ServerHello, "|||" si no hubo
+// las posiciones replican a la implementacion de referencia para que el hash coincida
+func parseServerHello(data []byte) (out string) {
+	defer func() {
+		if recover() != nil {
+			out = "|||"
+		}
+	}()
+
+	if len(data) < 6 || data[0] != 0x16 || data[5] != 0x02 {
+		return "|||"
+	}
+
+	helloLen := int(binary.BigEndian.Uint16(data[3:5]))
+	counter := int(data[43]) //largo del session id
+	cipher := hex.EncodeToString(data[counter+44 : counter+46])
+	version := hex.EncodeToString(data[9:11])
+
+	return cipher + "|" + version + "|" + extensionInfo(data, counter, helloLen)
+}
+
+func extensionInfo(data []byte, counter, helloLen int) (out string) {
+	defer func() {
+		if recover() != nil {
+			out = "|"
+		}
+	}()
+
+	if data[counter+47] == 11 {
+		return "|"
+	}
+	if string(clip(data, counter+50, counter+53)) == "\x0e\xac\x0b" || string(clip(data, 82, 85)) == "\x0f\xf0\x0b" {
+		return "|"
+	}
+	if counter+42 >= helloLen {
+		return "|"
+	}
+
+	count := 49 + counter
+	maximum := int(binary.BigEndian.Uint16(data[counter+47:counter+49])) + count - 1
+
+	var types []string
+	alpn := ""
+	for count < maximum {
+		extType := data[count : count+2]
+		extLen := int(binary.BigEndian.Uint16(data[count+2 : count+4]))
+		value := clip(data, count+4, count+4+extLen)
+		if extType[0] == 0x00 && extType[1] == 0x10 && alpn == "" && len(value) > 3 {
+			alpn = string(value[3:])
+		}
+		types = append(types, hex.EncodeToString(extType))
+		count += extLen + 4
+	}
+
+	return alpn + "|" + strings.Join(types, "-")
+}
+
+// slice truncado al largo de data (como los slices de python)
+func clip(data []byte, from, to int) []byte {
+	if from > len(data) {
+		return nil
+	}
+	if to > len(data) {
+		to = len(data)
+	}
+	return data[from:to]
+}
+
+// hash JARM de las 10 respuestas: 30 caracteres de cipher/version + 32 del sha256 de ALPN y extensiones
+func Hash(raw []string) string {
+	allEmpty := true
+	var fuzzy, rest strings.Builder
+	for _, r := range raw {
+		if r != "|||" {
+			allEmpty = false
+		}
+		parts := strings.SplitN(r, "|", 4)
+		for len(parts) < 4 {
+			parts = append(parts, "")
+		}
+		fuzzy.WriteString(cipherByte(parts[0]))
+		fuzzy.WriteString(versionByte(parts[1]))
+		rest.WriteString(parts[2])
+		rest.WriteString(parts[3])
+	}
+	if allEmpty {
+		return emptyJARM
+	}
+
+	sum := sha256.Sum256([]byte(rest.String()))
+	return fuzzy.String() + hex.EncodeToString(sum[:])[:32]
+}
+
+// posicion (1-based) de la suite en la tabla, en dos digitos hex
+func cipherByte(cipher string) string {
+	if cipher == "" {
+		return "00"
+	}
+	count := 1
+	for _, c := range hashCiphers {
+		if fmt.Sprintf("%04x", c) == cipher {
+			break
+		}
+		count++
+	}
+	return fmt.Sprintf("%02x", count)
+}
+
+// "0303" -> 'd' (a = SSLv3 ... f = TLS 1.3)
+func versionByte(version string) string {
+	if len(version) < 4 {
+		return "0"
+	}
+	i := int(version[3] - '0')
+	if i < 0 || i > 5 {
+		return "0"
+	}
+	return string("abcdef"[i])
+}
@@ -0,0 +1,282 @@
+package jarm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// vectores generados con read_packet y jarm_hash de la implementacion de referencia (salesforce/jarm)
+
+// extension TLS con su largo
+func ext(kind uint16, value []byte) []byte {
+	out := binary.BigEndian.AppendUint16(nil, kind)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(value)))
+	return append(out, value...)
+}
+
+// extension ALPN con un solo protocolo
+func alpnExt(name string) []byte {
+	list := binary.BigEndian.AppendUint16(nil, uint16(len(name)+1))
+	list = append(list, byte(len(name)))
+	return ext(0x0010, append(list, name...))
+}
+
+// registro TLS con un ServerHello (exts nil = sin bloque de extensiones)
+func serverHelloRecord(version, cipher uint16, sid []byte, exts [][]byte) []byte {
+	body := binary.BigEndian.AppendUint16(nil, version)
+	body = append(body, make([]byte, 32)...)
+	body = append(body, byte(len(sid)))
+	body = append(body, sid...)
+	body = binary.BigEndian.AppendUint16(body, cipher)
+	body = append(body, 0x00)
+	if exts != nil {
+		e := bytes.Join(exts, nil)
+		body = binary.BigEndian.AppendUint16(body, uint16(len(e)))
+		body = append(body, e...)
+	}
+
+	handshake := []byte{0x02, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))}
+	handshake = append(handshake, body...)
+	record := binary.BigEndian.AppendUint16([]byte{0x16, 0x03, 0x03}, uint16(len(handshake)))
+	return append(record, handshake...)
+}
+
+func sessionID() []byte {
+	sid := make([]byte, 32)
+	for i := range sid {
+		sid[i] = byte(i)
+	}
+	return sid
+}
+
+var (
+	rawTLS12   = "c02f|0303|h2|ff01-0000-000b-0010-0017"
+	rawTLS13   = "1301|0303||002b-0033"
+	rawTLS10   = "009c|0301|http/1.1|0010-ff01"
+	rawUnknown = "00ff|0302||ff01"
+	rawNoExt   = "c030|0303||"
+)
+
+func TestParseServerHello(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "tls 1.2 with alpn",
+			data: serverHelloRecord(0x0303, 0xc02f, sessionID(), [][]byte{
+				ext(0xff01, []byte{0}), ext(0x0000, nil), ext(0x000b, []byte{3, 0, 1, 2}), alpnExt("h2"), ext(0x0017, nil),
+			}),
+			want: rawTLS12,
+		},
+		{
+			name: "tls 1.3",
+			data: serverHelloRecord(0x0303, 0x1301, sessionID(), [][]byte{
+				ext(0x002b, []byte{0x03, 0x04}), ext(0x0033, append([]byte{0x00, 0x1d, 0x00, 0x20}, make([]byte, 32)...)),
+			}),
+			want: rawTLS13,
+		},
+		{
+			name: "empty session id",
+			data: serverHelloRecord(0x0301, 0x009c, nil, [][]byte{alpnExt("http/1.1"), ext(0xff01, []byte{0})}),
+			want: rawTLS10,
+		},
+		{
+			name: "cipher outside the table",
+			data: serverHelloRecord(0x0302, 0x00ff, sessionID(), [][]byte{ext(0xff01, []byte{0})}),
+			want: rawUnknown,
+		},
+		{"no extensions", serverHelloRecord(0x0303, 0xc030, sessionID(), nil), rawNoExt},
+		{"alert", []byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28}, "|||"},
+		{"client hello", []byte{0x16, 0x03, 0x01, 0x00, 0x04, 0x01, 0x00, 0x00, 0x00}, "|||"},
+		{"truncated", serverHelloRecord(0x0303, 0xc02f, sessionID(), [][]byte{})[:40], "|||"},
+		{"no response", nil, "|||"},
+	}
+
+	for _, tt := range tests {
+		if got := parseServerHello(tt.data); got != tt.want {
+			t.Errorf("%s: parseServerHello = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	const empty = "|||"
+
+	tests := []struct {
+		name string
+		raw  []string
+		want string
+	}{
+		{
+			name: "same server",
+			raw:  []string{rawTLS12, rawTLS12, rawTLS12, rawTLS12, rawTLS12, rawTLS12, rawTLS13, rawTLS13, rawTLS13, rawTLS13},
+			want: "29d29d29d29d29d29d41d41d41d41d9cb00285185f378b2648c480ff82c32d",
+		},
+		{
+			name: "mixed",
+			raw:  []string{rawTLS12, rawTLS12, empty, rawTLS10, empty, rawUnknown, rawTLS13, rawTLS13, rawNoExt, empty},
+			want: "29d29d00013b00046c41d41d2ad000628fda4b50ea088043fd0d29522f3f97",
+		},
+		{
+			name: "single answer",
+			raw:  []string{empty, empty, empty, empty, empty, empty, rawTLS13, empty, empty, empty},
+			want: "00000000000000000041d00000000022a14908b59e70935d7a5c2f786c3606",
+		},
+		{
+			name: "all failed",
+			raw:  slices.Repeat([]string{empty}, 10),
+			want: emptyJARM,
+		},
+	}
+
+	for _, tt := range tests {
+		got := Hash(tt.raw)
+		if got != tt.want {
+			t.Errorf("%s: Hash = %s, want %s", tt.name, got, tt.want)
+		}
+		if len(got) != 62 {
+			t.Errorf("%s: len(Hash) = %d, want 62", tt.name, len(got))
+		}
+	}
+}
+
+func TestCipherVersionBytes(t *testing.T) {
+	ciphers := map[string]string{"": "00", "0004": "01", "c02f": "29", "1301": "41", "1305": "45", "00ff": "46"}
+	for cipher, want := range ciphers {
+		if got := cipherByte(cipher); got != want {
+			t.Errorf("cipherByte(%q) = %q, want %q", cipher, got, want)
+		}
+	}
+
+	versions := map[string]string{"": "0", "0300": "a", "0301": "b", "0302": "c", "0303": "d", "0304": "e", "03": "0"}
+	for version, want := range versions {
+		if got := versionByte(version); got != want {
+			t.Errorf("versionByte(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
+
+func TestMung(t *testing.T) {
+	odd := []int{1, 2, 3, 4, 5, 6, 7}
+	even := []int{1, 2, 3, 4, 5, 6}
+
+	tests := []struct {
+		order     string
+		odd, even []int
+	}{
+		{"FORWARD", odd, even},
+		{"REVERSE", []int{7, 6, 5, 4, 3, 2, 1}, []int{6, 5, 4, 3, 2, 1}},
+		{"BOTTOM_HALF", []int{5, 6, 7}, []int{4, 5, 6}},
+		{"TOP_HALF", []int{4, 3, 2, 1}, []int{3, 2, 1}},
+		{"MIDDLE_OUT", []int{4, 5, 3, 6, 2, 7, 1}, []int{4, 3, 5, 2, 6, 1}},
+	}
+
+	for _, tt := range tests {
+		if got := mung(odd, tt.order); !reflect.DeepEqual(got, tt.odd) {
+			t.Errorf("mung(odd, %s) = %v, want %v", tt.order, got, tt.odd)
+		}
+		if got := mung(even, tt.order); !reflect.DeepEqual(got, tt.even) {
+			t.Errorf("mung(even, %s) = %v, want %v", tt.order, got, tt.even)
+		}
+	}
+}
+
+func TestBuildHello(t *testing.T) {
+	for i, spec := range specs {
+		record := buildHello(spec, "example.com")
+		if record[0] != 0x16 || int(binary.BigEndian.Uint16(record[3:5])) != len(record)-5 {
+			t.Errorf("spec %d: record header % x for %d bytes", i, record[:5], len(record))
+			continue
+		}
+		handshake := record[5:]
+		if handshake[0] != 0x01 || int(binary.BigEndian.Uint16(handshake[2:4])) != len(handshake)-4 {
+			t.Errorf("spec %d: handshake header % x", i, handshake[:4])
+			continue
+		}
+
+		hello := handshake[4:]
+		pos := 2 + 32 + 1 + int(hello[34])
+		suitesLen := int(binary.BigEndian.Uint16(hello[pos:]))
+		suites := hello[pos+2 : pos+2+suitesLen]
+		pos += 2 + suitesLen + 2 //compresion
+		if extLen := int(binary.BigEndian.Uint16(hello[pos:])); extLen != len(hello)-pos-2 {
+			t.Errorf("spec %d: extensions length %d, remaining %d", i, extLen, len(hello)-pos-2)
+		}
+		if !bytes.Contains(hello[pos:], []byte("example.com")) {
+			t.Errorf("spec %d: server_name missing", i)
+		}
+
+		tls13Suites := 0
+		for j := 0; j+1 < len(suites); j += 2 {
+			if suites[j] == 0x13 {
+				tls13Suites++
+			}
+		}
+		if spec.ciphers == "NO1.3" && tls13Suites != 0 {
+			t.Errorf("spec %d: NO1.3 offers %d tls 1.3 suites", i, tls13Suites)
+		}
+	}
+}
+
+func TestJA3S(t *testing.T) {
+	hello := func(record []byte) []byte {
+		h, err := serverHello(record)
+		if err != nil {
+			t.Fatalf("serverHello: %v", err)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		record []byte
+		want   string //md5 de "771,49199,65281-0-11-16-23" etc.
+	}{
+		{
+			name: "tls 1.2",
+			record: serverHelloRecord(0x0303, 0xc02f, sessionID(), [][]byte{
+				ext(0xff01, []byte{0}), ext(0x0000, nil), ext(0x000b, []byte{3, 0, 1, 2}), alpnExt("h2"), ext(0x0017, nil),
+			}),
+			want: "5d79edf64e03689ff559a54e9d9487bc",
+		},
+		{
+			name:   "tls 1.3",
+			record: serverHelloRecord(0x0303, 0x1301, sessionID(), [][]byte{ext(0x002b, []byte{0x03, 0x04}), ext(0x0033, make([]byte, 36))}),
+			want:   "f4febc55ea12b31ae17cfb7e614afda8",
+		},
+		{"no extensions", serverHelloRecord(0x0303, 0xc030, sessionID(), nil), "7770094a92b1cbfa5a6de2017cfb682a"},
+	}
+
+	for _, tt := range tests {
+		if got, err := ja3sFromHello(hello(tt.record)); err != nil || got != tt.want {
+			t.Errorf("%s: ja3s = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	//el ServerHello partido en dos registros
+	record := serverHelloRecord(0x0303, 0x1301, sessionID(), [][]byte{ext(0x002b, []byte{0x03, 0x04})})
+	body := record[5:]
+	split := append(binary.BigEndian.AppendUint16([]byte{0x16, 0x03, 0x03}, 20), body[:20]...)
+	split = append(binary.BigEndian.AppendUint16(append(split, 0x16, 0x03, 0x03), uint16(len(body)-20)), body[20:]...)
+	if h, err := serverHello(split); err != nil || !bytes.Equal(h, body[4:]) {
+		t.Errorf("split serverHello = % x, %v", h, err)
+	}
+
+	for name, data := range map[string][]byte{
+		"alert":        {0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28},
+		"client hello": {0x16, 0x03, 0x01, 0x00, 0x04, 0x01, 0x00, 0x00, 0x00},
+		"truncated":    record[:30],
+	} {
+		if h, err := serverHello(data); err == nil {
+			t.Errorf("%s: serverHello = % x, want error", name, h)
+		}
+	}
+	if _, err := ja3sFromHello(make([]byte, 20)); err == nil {
+		t.Error("ja3sFromHello(short) succeeded, want error")
+	}
+}
@@ -0,0 +1,90 @@
+package jarm
+
+//LISTA DE FINGERPRINTS CONOCIDOS
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+//go:embed data/known.tsv
+var knownData string
+
+// hash (jarm o ja3s) -> etiquetas
+type Known struct {
+	labels map[string][]string
+}
+
+// etiquetas que corresponden a un fingerprint
+func (k *Known) Labels(fp string) []string {
+	if k == nil || fp == "" {
+		return nil
+	}
+	return k.labels[strings.ToLower(fp)]
+}
+
+// lee lineas "tipo<TAB>hash<TAB>etiqueta" (el tipo es informativo)
+func parseKnown(r io.Reader, into *Known) error {
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(text, "\t", 3)
+		if len(fields) != 3 {
+			return fmt.Errorf("line %d: expected type, hash and label", line)
+		}
+		kind := strings.ToLower(strings.TrimSpace(fields[0]))
+		if kind != "jarm" && kind != "ja3s" {
+			return fmt.Errorf("line %d: unknown fingerprint type %q", line, fields[0])
+		}
+
+		hash := strings.ToLower(strings.TrimSpace(fields[1]))
+		into.labels[hash] = append(into.labels[hash], strings.TrimSpace(fields[2]))
+	}
+	return sc.Err()
+}
+
+// listas ya cargadas por ruta
+var (
+	knownMu sync.Mutex
+	known   = make(map[string]*Known)
+)
+
+// lista embebida mas un archivo del usuario (opcional)
+func LoadKnown(path string) (*Known, error) {
+	knownMu.Lock()
+	defer knownMu.Unlock()
+
+	if k, ok := known[path]; ok {
+		return k, nil
+	}
+
+	k := &Known{labels: make(map[string][]string)}
+	if err := parseKnown(strings.NewReader(knownData), k); err != nil {
+		return nil, fmt.Errorf("embedded fingerprints: %w", err)
+	}
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open fingerprints file: %w", err)
+		}
+		defer f.Close()
+
+		if err := parseKnown(f, k); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	known[path] = k
+	return k, nil
+}
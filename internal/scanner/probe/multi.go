@@ -0,0 +1,80 @@
+package probe
+
+import (
+	"context"
+	"go-scanner/internal/scanner/service"
+)
+
+// varios probers sobre un mismo servicio (ej. handshake de la base y su STARTTLS)
+type multiProbe []Prober
+
+// combina probers bajo un solo nombre del registro, lo identificado se integra en orden
+// la declaracion es la del primero, con los grupos de todos
+func Multi(probers ...Prober) Prober {
+	return multiProbe(probers)
+}
+
+func (m multiProbe) Spec() Spec {
+	spec := m[0].Spec()
+	spec.Groups = append([]string(nil), spec.Groups...)
+	for _, p := range m[1:] {
+		for _, g := range p.Spec().Groups {
+			if !contains(spec.Groups, g) {
+				spec.Groups = append(spec.Groups, g)
+			}
+		}
+	}
+	return spec
+}
+
+// aplica si alguno de los probers aplica (los que no declaran condiciones siempre aplican)
+func (m multiProbe) Match(target Target) bool {
+	for _, p := range m {
+		if matches(p, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// corre los probers cuyas condiciones se cumplen, falla solo si ninguno identifico algo
+func (m multiProbe) Probe(ctx context.Context, target Target, opts Options) (*service.ServiceInfo, error) {
+	var info *service.ServiceInfo
+	var firstErr error
+	for _, p := range m {
+		if !matches(p, target) {
+			continue
+		}
+		got, err := p.Probe(ctx, target, opts)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if info == nil {
+			info = got
+		} else {
+			info.Merge(got)
+		}
+	}
+	if info == nil {
+		return nil, firstErr
+	}
+	return info, nil
+}
+
+// condiciones propias del prober (Matcher) sobre el target
+func matches(p Prober, target Target) bool {
+	m, ok := p.(Matcher)
+	return !ok || m.Match(target)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
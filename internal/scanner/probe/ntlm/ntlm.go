@@ -0,0 +1,138 @@
+package ntlm
+
+//NTLMSSP -> NEGOTIATE anonimo y lectura del CHALLENGE (nombres, dominio y version de Windows)
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/scanner/service"
+	"unicode/utf16"
+)
+
+// firma de los mensajes NTLMSSP
+var signature = []byte("NTLMSSP\x00")
+
+// flags del NEGOTIATE: unicode, OEM, request target, NTLM, always sign, extended session security,
+// target info, version, 128 y 56 bits
+const negotiateFlags = 0x00000001 | 0x00000002 | 0x00000004 | 0x00000200 | 0x00008000 |
+	0x00080000 | 0x00800000 | 0x02000000 | 0x20000000 | 0x80000000
+
+// flag del CHALLENGE que indica que trae la version del sistema
+const flagVersion = 0x02000000
+
+// tipos de AV_PAIR en TargetInfo
+const (
+	avEOL             = 0
+	avNbComputerName  = 1
+	avNbDomainName    = 2
+	avDNSComputerName = 3
+	avDNSDomainName   = 4
+	avDNSTreeName     = 5
+)
+
+// mensaje NEGOTIATE (tipo 1) sin dominio ni workstation
+func Negotiate() []byte {
+	msg := append([]byte{}, signature...)
+	msg = binary.LittleEndian.AppendUint32(msg, 1)
+	msg = binary.LittleEndian.AppendUint32(msg, negotiateFlags)
+	msg = append(msg, make([]byte, 16)...)             //domain y workstation vacios
+	msg = append(msg, 6, 1, 0xb1, 0x1d, 0, 0, 0, 0x0f) //version del cliente (6.1.7601, NTLM rev 15)
+	return msg
+}
+
+// busca el CHALLENGE dentro de un buffer (puede venir envuelto en SPNEGO) y lo interpreta
+func ParseChallenge(buf []byte) (*service.NTLMInfo, error) {
+	i := bytes.Index(buf, signature)
+	if i < 0 {
+		return nil, errors.New("no ntlmssp message found")
+	}
+	msg := buf[i:]
+	if len(msg) < 48 || binary.LittleEndian.Uint32(msg[8:12]) != 2 {
+		return nil, errors.New("not an ntlmssp challenge")
+	}
+
+	flags := binary.LittleEndian.Uint32(msg[20:24])
+	info := &service.NTLMInfo{}
+
+	infoLen := int(binary.LittleEndian.Uint16(msg[40:42]))
+	infoOff := int(binary.LittleEndian.Uint32(msg[44:48]))
+	if infoLen > 0 {
+		if infoOff > len(msg) || infoLen > len(msg)-infoOff {
+			return nil, errors.New("truncated ntlmssp target info")
+		}
+		parseTargetInfo(msg[infoOff:infoOff+infoLen], info)
+	}
+
+	if flags&flagVersion != 0 && len(msg) >= 56 {
+		v := msg[48:56]
+		info.OSVersion = fmt.Sprintf("%d.%d.%d", v[0], v[1], binary.LittleEndian.Uint16(v[2:4]))
+	}
+	return info, nil
+}
+
+// AV_PAIRs: id (2), largo (2), valor UTF-16LE
+func parseTargetInfo(data []byte, info *service.NTLMInfo) {
+	for len(data) >= 4 {
+		id := binary.LittleEndian.Uint16(data[0:2])
+		size := int(binary.LittleEndian.Uint16(data[2:4]))
+		if id == avEOL || 4+size > len(data) {
+			return
+		}
+		value := decodeUTF16(data[4 : 4+size])
+		switch id {
+		case avNbComputerName:
+			info.NetBIOSComputer = value
+		case avNbDomainName:
+			info.NetBIOSDomain = value
+		case avDNSComputerName:
+			info.DNSComputer = value
+		case avDNSDomainName:
+			info.DNSDomain = value
+		case avDNSTreeName:
+			info.DNSTree = value
+		}
+		data = data[4+size:]
+	}
+}
+
+func decodeUTF16(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// nombre comercial aproximado a partir del build (solo para el OS hint)
+func WindowsRelease(version string) string {
+	var major, minor, build int
+	if _, err := fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &build); err != nil {
+		return ""
+	}
+	switch {
+	case major == 10 && build >= 26100:
+		return "Windows 11 24H2 / Server 2025"
+	case major == 10 && build >= 22000:
+		return "Windows 11"
+	case major == 10 && build == 20348:
+		return "Windows Server 2022"
+	case major == 10 && build == 17763:
+		return "Windows 10 1809 / Server 2019"
+	case major == 10 && build == 14393:
+		return "Windows 10 1607 / Server 2016"
+	case major == 10:
+		return "Windows 10"
+	case major == 6 && minor == 3:
+		return "Windows 8.1 / Server 2012 R2"
+	case major == 6 && minor == 2:
+		return "Windows 8 / Server 2012"
+	case major == 6 && minor == 1:
+		return "Windows 7 / Server 2008 R2"
+	case major == 6 && minor == 0:
+		return "Windows Vista / Server 2008"
+	case major == 5:
+		return "Windows XP / Server 2003"
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package ntlm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"go-scanner/internal/scanner/service"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// CHALLENGE_MESSAGE del ejemplo de MS-NLMP 4.2.4.3 (NTLMv2)
+const specChallenge = "4e544c4d53535000020000000c000c003800000033828ae20123456789abcdef" +
+	"00000000000000002400240044000000060070170000000f53006500720076006500720002000c00" +
+	"44006f006d00610069006e0001000c0053006500720076006500720000000000"
+
+// AV_PAIR con el valor en UTF-16LE
+func avPair(id uint16, value string) []byte {
+	out := binary.LittleEndian.AppendUint16(nil, id)
+	units := utf16.Encode([]rune(value))
+	out = binary.LittleEndian.AppendUint16(out, uint16(2*len(units)))
+	for _, u := range units {
+		out = binary.LittleEndian.AppendUint16(out, u)
+	}
+	return out
+}
+
+// CHALLENGE con la version y el TargetInfo dados (sin TargetName)
+func challenge(flags uint32, version []byte, targetInfo []byte) []byte {
+	msg := append([]byte{}, signature...)
+	msg = binary.LittleEndian.AppendUint32(msg, 2)
+	msg = append(msg, make([]byte, 8)...) //TargetName vacio
+	msg = binary.LittleEndian.AppendUint32(msg, flags)
+	msg = append(msg, make([]byte, 16)...) //ServerChallenge y Reserved
+	msg = binary.LittleEndian.AppendUint16(msg, uint16(len(targetInfo)))
+	msg = binary.LittleEndian.AppendUint16(msg, uint16(len(targetInfo)))
+	msg = binary.LittleEndian.AppendUint32(msg, 56)
+	msg = append(msg, version...)
+	return append(msg, targetInfo...)
+}
+
+func TestParseChallengeSpecVector(t *testing.T) {
+	buf, _ := hex.DecodeString(specChallenge)
+	info, err := ParseChallenge(buf)
+	if err != nil {
+		t.Fatalf("ParseChallenge: %v", err)
+	}
+	want := service.NTLMInfo{NetBIOSComputer: "Server", NetBIOSDomain: "Domain", OSVersion: "6.0.6000"}
+	if *info != want {
+		t.Errorf("ParseChallenge = %+v, want %+v", *info, want)
+	}
+}
+
+func TestParseChallengeAVPairs(t *testing.T) {
+	version := []byte{10, 0, 0x63, 0x45, 0, 0, 0, 0x0f} //10.0.17763
+	fullInfo := bytes.Join([][]byte{
+		avPair(avNbDomainName, "CORP"),
+		avPair(avNbComputerName, "DC01"),
+		avPair(avDNSDomainName, "corp.example.com"),
+		avPair(avDNSComputerName, "dc01.corp.example.com"),
+		avPair(avDNSTreeName, "example.com"),
+		avPair(7, "\x00\x00\x00\x00"), //MsvAvTimestamp, se ignora
+		{0, 0, 0, 0},
+	}, nil)
+
+	tests := []struct {
+		name string
+		buf  []byte
+		want service.NTLMInfo
+	}{
+		{
+			name: "all pairs",
+			buf:  challenge(flagVersion, version, fullInfo),
+			want: service.NTLMInfo{
+				NetBIOSComputer: "DC01", NetBIOSDomain: "CORP",
+				DNSComputer: "dc01.corp.example.com", DNSDomain: "corp.example.com", DNSTree: "example.com",
+				OSVersion: "10.0.17763",
+			},
+		},
+		{
+			name: "wrapped in spnego",
+			buf:  append([]byte{0xa1, 0x81, 0xff, 0x30, 0x81, 0xfc, 0xa2, 0x81, 0xf9, 0x04, 0x81, 0xf6}, challenge(flagVersion, version, fullInfo)...),
+			want: service.NTLMInfo{
+				NetBIOSComputer: "DC01", NetBIOSDomain: "CORP",
+				DNSComputer: "dc01.corp.example.com", DNSDomain: "corp.example.com", DNSTree: "example.com",
+				OSVersion: "10.0.17763",
+			},
+		},
+		{
+			name: "no version flag",
+			buf:  challenge(0, version, avPair(avNbComputerName, "HOST")),
+			want: service.NTLMInfo{NetBIOSComputer: "HOST"},
+		},
+		{
+			name: "stops at eol",
+			buf:  challenge(0, version, append(append(avPair(avNbComputerName, "HOST"), 0, 0, 0, 0), avPair(avNbDomainName, "IGNORED")...)),
+			want: service.NTLMInfo{NetBIOSComputer: "HOST"},
+		},
+		{
+			name: "pair longer than target info",
+			buf:  challenge(0, version, append(avPair(avNbComputerName, "HOST"), 0x02, 0x00, 0x40, 0x00, 'x', 0)),
+			want: service.NTLMInfo{NetBIOSComputer: "HOST"},
+		},
+		{
+			name: "non-ascii names",
+			buf:  challenge(0, version, avPair(avNbDomainName, "ÑANDÚ")),
+			want: service.NTLMInfo{NetBIOSDomain: "ÑANDÚ"},
+		},
+		{
+			name: "empty target info",
+			buf:  challenge(flagVersion, version, nil),
+			want: service.NTLMInfo{OSVersion: "10.0.17763"},
+		},
+	}
+
+	for _, tt := range tests {
+		info, err := ParseChallenge(tt.buf)
+		if err != nil {
+			t.Errorf("%s: ParseChallenge: %v", tt.name, err)
+			continue
+		}
+		if *info != tt.want {
+			t.Errorf("%s: ParseChallenge = %+v, want %+v", tt.name, *info, tt.want)
+		}
+	}
+}
+
+func TestParseChallengeErrors(t *testing.T) {
+	version := []byte{10, 0, 0x63, 0x45, 0, 0, 0, 0x0f}
+	valid := challenge(flagVersion, version, avPair(avNbComputerName, "HOST"))
+
+	//TargetInfo declarado mas alla del mensaje
+	truncated := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint16(truncated[40:42], 0x400)
+
+	offsetOut := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint32(offsetOut[44:48], 0xfffffff0)
+
+	negotiate := Negotiate()
+
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{"empty", nil},
+		{"no signature", []byte(strings.Repeat("x", 64))},
+		{"negotiate message", negotiate},
+		{"short challenge", valid[:40]},
+		{"truncated target info", truncated},
+		{"target info offset out of range", offsetOut},
+	}
+
+	for _, tt := range tests {
+		if info, err := ParseChallenge(tt.buf); err == nil {
+			t.Errorf("%s: ParseChallenge = %+v, want error", tt.name, info)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	msg := Negotiate()
+	if len(msg) != 40 {
+		t.Fatalf("len(Negotiate) = %d, want 40", len(msg))
+	}
+	if !bytes.HasPrefix(msg, signature) || binary.LittleEndian.Uint32(msg[8:12]) != 1 {
+		t.Errorf("Negotiate header = % x", msg[:12])
+	}
+	if flags := binary.LittleEndian.Uint32(msg[12:16]); flags != negotiateFlags || flags&flagVersion == 0 {
+		t.Errorf("Negotiate flags = %#x", flags)
+	}
+}
+
+func TestWindowsRelease(t *testing.T) {
+	tests := []struct {
+		version, want string
+	}{
+		{"10.0.26100", "Windows 11 24H2 / Server 2025"},
+		{"10.0.22631", "Windows 11"},
+		{"10.0.20348", "Windows Server 2022"},
+		{"10.0.17763", "Windows 10 1809 / Server 2019"},
+		{"10.0.14393", "Windows 10 1607 / Server 2016"},
+		{"10.0.19045", "Windows 10"},
+		{"6.3.9600", "Windows 8.1 / Server 2012 R2"},
+		{"6.1.7601", "Windows 7 / Server 2008 R2"},
+		{"6.0.6000", "Windows Vista / Server 2008"},
+		{"5.2.3790", "Windows XP / Server 2003"},
+		{"4.0.1381", ""},
+		{"", ""},
+		{"windows", ""},
+	}
+
+	for _, tt := range tests {
+		if got := WindowsRelease(tt.version); got != tt.want {
+			t.Errorf("WindowsRelease(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package probe
+
+//OPCIONES DE CONEXION -> dialer y TLS de la campaña, timeout y datos de la policy para los probers
+import (
+	"context"
+	"crypto/tls"
+	"go-scanner/internal/utils"
+	"net"
+	"time"
+)
+
+// timeout por defecto de una conexion de prober
+const DefaultTimeout = 3 * time.Second
+
+// dialer de la campaña (el mismo que usan los scanners)
+type Dialer = utils.Dialer
+
+// lo que el engine entrega a cada prober
+type Options struct {
+	Dialer  Dialer        //nil = net.Dialer directo
+	TLS     *tls.Config   //base de los handshakes (nil = sin verificar el certificado)
+	Timeout time.Duration //por conexion, cubre el dialogo completo (0 = DefaultTimeout)
+
+	DNSZones        []string //zonas para las que se prueba AXFR
+	SNMPCommunities []string //comunidades SNMP (vacio = las del prober)
+	TLSFingerprints string   //archivo de hashes JARM/JA3S conocidos del usuario
+}
+
+// timeout efectivo
+func (o Options) ConnTimeout() time.Duration {
+	if o.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return o.Timeout
+}
+
+// abre una conexion ("tcp" o "udp") con el dialer de la campaña
+// el deadline cubre todo el dialogo y cancelar ctx cierra la conexion
+func (o Options) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	var dialer Dialer = &net.Dialer{}
+	if o.Dialer != nil {
+		dialer = o.Dialer
+	}
+
+	deadline := time.Now().Add(o.ConnTimeout())
+	dialCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	conn, err := dialer.DialContext(dialCtx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	return &ctxConn{Conn: conn, stop: stop}, nil
+}
+
+// conexion que deja de vigilar el contexto al cerrarse
+type ctxConn struct {
+	net.Conn
+	stop func() bool
+}
+
+func (c *ctxConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
+
+// copia de la configuracion TLS de la campaña con el SNI dado (solo si es un nombre)
+// sin configuracion base el certificado se inspecciona pero no se valida
+func (o Options) TLSConfig(serverName string) *tls.Config {
+	config := &tls.Config{InsecureSkipVerify: true}
+	if o.TLS != nil {
+		config = o.TLS.Clone()
+	}
+	if config.ServerName == "" && serverName != "" && net.ParseIP(serverName) == nil {
+		config.ServerName = serverName
+	}
+	return config
+}
+
+// conexion TCP con handshake TLS completo
+func (o Options) DialTLS(ctx context.Context, address string, config *tls.Config) (*tls.Conn, error) {
+	conn, err := o.Dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
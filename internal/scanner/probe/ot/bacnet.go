@@ -0,0 +1,240 @@
+package ot
+
+//BACNET/IP -> Who-Is unicast y ReadProperty del objeto device
+//paquetes enviados: Who-Is (sin confirmacion) y ReadProperty (servicio 12) de propiedades de identificacion;
+//nunca WriteProperty, ReinitializeDevice, DeviceCommunicationControl ni servicios de archivos
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"strconv"
+)
+
+const (
+	bacnetDeviceObject = 8
+	bacnetWildcard     = 4194303 //instancia comodin: "el device que responde"
+
+	bacnetReadProperty = 0x0c
+	bacnetIAm          = 0x00
+	bacnetWhoIs        = 0x08
+)
+
+// propiedades del device leidas
+const (
+	propAppSoftware = 12
+	propFirmware    = 44
+	propModelName   = 70
+	propObjectName  = 77
+	propVendorID    = 120
+	propVendorName  = 121
+)
+
+var bacnetProperties = []byte{propVendorID, propVendorName, propModelName, propFirmware, propAppSoftware, propObjectName}
+
+type BACnetProbe struct{}
+
+func NewBACnetProbe() *BACnetProbe {
+	return &BACnetProbe{}
+}
+
+func (p *BACnetProbe) Spec() probe.Spec {
+	return spec("bacnet", model.ProtocolUDP, service.ServiceBACnet, 47808)
+}
+
+// todos los requests salen juntos por el mismo socket; cada respuesta se asocia por invoke id
+func (p *BACnetProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, "udp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(bvlc([]byte{0x01, 0x00, 0x10, bacnetWhoIs})); err != nil {
+		return nil, err
+	}
+	for i, prop := range bacnetProperties {
+		if _, err := conn.Write(readPropertyRequest(byte(i+1), prop)); err != nil {
+			return nil, err
+		}
+	}
+
+	details := &service.OTInfo{Protocol: "BACnet"}
+	values := make(map[byte]string)
+	answered, pending := false, len(bacnetProperties)
+	buf := make([]byte, 1500)
+	for pending > 0 {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		apdu, ok := bacnetAPDU(buf[:n])
+		if !ok || len(apdu) < 2 {
+			continue
+		}
+		answered = true
+		switch apdu[0] & 0xf0 {
+		case 0x10: //unconfirmed: I-Am
+			if apdu[1] == bacnetIAm {
+				parseIAm(apdu[2:], details)
+			}
+		case 0x30: //ComplexACK
+			if len(apdu) >= 3 && apdu[2] == bacnetReadProperty {
+				if prop, value, ok := parseReadPropertyAck(apdu[3:]); ok {
+					values[prop] = value
+					if details.Address == "" {
+						//el ACK trae la instancia real en lugar del comodin
+						details.Address = "device " + strconv.Itoa(int(binary.BigEndian.Uint32(apdu[4:8])&0x3fffff))
+					}
+				}
+				pending--
+			}
+		case 0x50, 0x60, 0x70: //Error, Reject, Abort: la propiedad no existe o no es legible
+			pending--
+		}
+	}
+	if !answered {
+		return nil, errors.New("no bacnet response")
+	}
+
+	details.Vendor = values[propVendorName]
+	details.Product = values[propModelName]
+	details.Revision = values[propFirmware]
+	if details.Revision == "" {
+		details.Revision = values[propAppSoftware]
+	}
+	details.Name = values[propObjectName]
+	if id, err := strconv.Atoi(values[propVendorID]); err == nil {
+		details.VendorID = id
+	}
+
+	info := newInfo(service.ServiceBACnet, details)
+	if details.Name != "" {
+		info.ExtraInfo = details.Name
+	}
+	return info, nil
+}
+
+// BVLC Original-Unicast-NPDU
+func bvlc(npdu []byte) []byte {
+	out := []byte{0x81, 0x0a, 0x00, 0x00}
+	binary.BigEndian.PutUint16(out[2:4], uint16(len(npdu)+4))
+	return append(out, npdu...)
+}
+
+// ReadProperty confirmado del device comodin; NPDU con "expecting reply"
+func readPropertyRequest(invoke, prop byte) []byte {
+	object := make([]byte, 4)
+	binary.BigEndian.PutUint32(object, bacnetDeviceObject<<22|bacnetWildcard)
+	apdu := []byte{0x00, 0x05, invoke, bacnetReadProperty, 0x0c}
+	apdu = append(apdu, object...)
+	apdu = append(apdu, 0x19, prop)
+	return bvlc(append([]byte{0x01, 0x04}, apdu...))
+}
+
+// quita BVLC y NPDU (con direcciones de red opcionales) y retorna el APDU
+func bacnetAPDU(pkt []byte) ([]byte, bool) {
+	if len(pkt) < 6 || pkt[0] != 0x81 {
+		return nil, false
+	}
+	offset := 4
+	if pkt[1] == 0x04 { //Forwarded-NPDU: IP y puerto de origen
+		offset += 6
+	}
+	npdu := pkt[offset:]
+	if len(npdu) < 2 || npdu[0] != 0x01 || npdu[1]&0x80 != 0 {
+		return nil, false
+	}
+	control, pos := npdu[1], 2
+	if control&0x20 != 0 { //DNET, DLEN, DADR
+		if len(npdu) < pos+3 {
+			return nil, false
+		}
+		pos += 3 + int(npdu[pos+2])
+	}
+	if control&0x08 != 0 { //SNET, SLEN, SADR
+		if len(npdu) < pos+3 {
+			return nil, false
+		}
+		pos += 3 + int(npdu[pos+2])
+	}
+	if control&0x20 != 0 { //hop count
+		pos++
+	}
+	if pos >= len(npdu) {
+		return nil, false
+	}
+	return npdu[pos:], true
+}
+
+// I-Am: object id del device, max APDU, segmentacion y vendor id
+func parseIAm(data []byte, details *service.OTInfo) {
+	for i := 0; len(data) > 0; i++ {
+		tag, value, rest, ok := bacnetTag(data)
+		if !ok {
+			return
+		}
+		switch {
+		case i == 0 && tag == 12 && len(value) == 4:
+			details.Address = "device " + strconv.Itoa(int(binary.BigEndian.Uint32(value)&0x3fffff))
+		case i == 3 && tag == 2:
+			details.VendorID = int(bacnetUnsigned(value))
+		}
+		data = rest
+	}
+}
+
+// ComplexACK de ReadProperty: [0] object id, [1] propiedad, [3] valor
+func parseReadPropertyAck(data []byte) (byte, string, bool) {
+	if len(data) < 9 || data[0] != 0x0c || data[5] != 0x19 || data[7] != 0x3e {
+		return 0, "", false
+	}
+	prop := data[6]
+	tag, value, _, ok := bacnetTag(data[8:])
+	if !ok {
+		return 0, "", false
+	}
+	switch tag {
+	case 2: //Unsigned
+		return prop, strconv.FormatUint(bacnetUnsigned(value), 10), true
+	case 7: //CharacterString: primer byte es el juego de caracteres (0 = UTF-8)
+		if len(value) > 0 && value[0] == 0 {
+			return prop, cleanString(value[1:]), true
+		}
+	}
+	return prop, "", true
+}
+
+// tag de aplicacion: numero, valor y resto
+func bacnetTag(data []byte) (byte, []byte, []byte, bool) {
+	if len(data) < 1 || data[0]&0x08 != 0 {
+		return 0, nil, nil, false
+	}
+	tag, length, pos := data[0]>>4, int(data[0]&0x07), 1
+	if length == 5 {
+		if len(data) < 2 {
+			return 0, nil, nil, false
+		}
+		length, pos = int(data[1]), 2
+		if length == 254 {
+			if len(data) < 4 {
+				return 0, nil, nil, false
+			}
+			length, pos = int(binary.BigEndian.Uint16(data[2:4])), 4
+		}
+	}
+	if len(data) < pos+length {
+		return 0, nil, nil, false
+	}
+	return tag, data[pos : pos+length], data[pos+length:], true
+}
+
+func bacnetUnsigned(b []byte) uint64 {
+	var v uint64
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return v
+}
@@ -0,0 +1,114 @@
+package ot
+
+//DNP3 -> Request Link Status (capa de enlace, funcion 9) a las direcciones de outstation habituales
+//paquete enviado: solo tramas de enlace REQUEST_LINK_STATUS sin capa de aplicacion;
+//nunca se envian funciones de aplicacion (read, write, operate, cold/warm restart)
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strconv"
+)
+
+const (
+	dnp3Start1          = 0x05
+	dnp3Start2          = 0x64
+	dnp3RequestLinkStat = 0xc9 //DIR=1 (maestro), PRM=1, funcion 9
+	dnp3LinkStatus      = 0x0b //respuesta secundaria LINK_STATUS
+	dnp3MasterAddress   = 1023 //direccion origen, fuera del rango consultado
+	dnp3MaxAddress      = 100  //outstations 0-99
+)
+
+type DNP3Probe struct{}
+
+func NewDNP3Probe() *DNP3Probe {
+	return &DNP3Probe{}
+}
+
+func (p *DNP3Probe) Spec() probe.Spec {
+	return spec("dnp3", model.ProtocolTCP, service.ServiceDNP3, 20000)
+}
+
+// las tramas van juntas; solo responde la outstation con esa direccion
+func (p *DNP3Probe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, "tcp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var batch []byte
+	for addr := 0; addr < dnp3MaxAddress; addr++ {
+		batch = append(batch, linkStatusRequest(uint16(addr))...)
+	}
+	if _, err := conn.Write(batch); err != nil {
+		return nil, err
+	}
+
+	//cabecera de enlace: 0x05 0x64, largo, control, destino, origen y CRC
+	//cualquier trama valida confirma DNP3; LINK_STATUS ademas da la direccion de la outstation
+	source := -1
+	frame := make([]byte, 10)
+	for valid := false; ; {
+		if _, err := io.ReadFull(conn, frame); err != nil {
+			if valid {
+				break
+			}
+			return nil, err
+		}
+		if frame[0] != dnp3Start1 || frame[1] != dnp3Start2 {
+			return nil, errors.New("not a dnp3 frame")
+		}
+		if binary.LittleEndian.Uint16(frame[8:10]) != dnp3CRC(frame[:8]) {
+			return nil, errors.New("invalid dnp3 header crc")
+		}
+		valid = true
+		//datos de usuario en bloques de 16 bytes con CRC cada uno
+		if extra := int(frame[2]) - 5; extra > 0 {
+			skip := extra + 2*((extra+15)/16)
+			if _, err := io.CopyN(io.Discard, conn, int64(skip)); err != nil {
+				break
+			}
+		}
+		//DIR=0 y PRM=0 (respuesta de la outstation), DFC ignorado
+		if frame[3]&0xcf == dnp3LinkStatus {
+			source = int(binary.LittleEndian.Uint16(frame[6:8]))
+			break
+		}
+	}
+
+	details := &service.OTInfo{Protocol: "DNP3"}
+	if source >= 0 {
+		details.Address = "outstation " + strconv.Itoa(source)
+	}
+	info := newInfo(service.ServiceDNP3, details)
+	info.ExtraInfo = details.Address
+	return info, nil
+}
+
+func linkStatusRequest(dest uint16) []byte {
+	frame := []byte{dnp3Start1, dnp3Start2, 0x05, dnp3RequestLinkStat}
+	frame = binary.LittleEndian.AppendUint16(frame, dest)
+	frame = binary.LittleEndian.AppendUint16(frame, dnp3MasterAddress)
+	return binary.LittleEndian.AppendUint16(frame, dnp3CRC(frame))
+}
+
+// CRC-16/DNP: polinomio 0x3D65 reflejado, resultado complementado
+func dnp3CRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa6bc
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return ^crc
+}
@@ -0,0 +1,143 @@
+package ot
+
+//ETHERNET/IP -> comando de encapsulacion ListIdentity (0x0063)
+//paquete enviado: solo ListIdentity, sin RegisterSession ni mensajes CIP (no hay lectura ni escritura de tags)
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+	"strconv"
+)
+
+const (
+	enipListIdentity = 0x0063
+	enipIdentityItem = 0x000c
+)
+
+// fabricantes CIP frecuentes
+var cipVendors = map[uint16]string{
+	1:  "Rockwell Automation/Allen-Bradley",
+	47: "Omron",
+}
+
+// tipos de dispositivo CIP frecuentes
+var cipDeviceTypes = map[uint16]string{
+	0x02: "AC Drive",
+	0x07: "General Purpose Discrete I/O",
+	0x0c: "Communications Adapter",
+	0x0e: "Programmable Logic Controller",
+	0x18: "Human-Machine Interface",
+	0x2b: "Generic Device",
+}
+
+// prober EtherNet/IP, uno por transporte
+type EtherNetIPProbe struct {
+	network string //"udp" o "tcp"
+}
+
+func NewEtherNetIPProbe(network string) *EtherNetIPProbe {
+	return &EtherNetIPProbe{network: network}
+}
+
+func (p *EtherNetIPProbe) Spec() probe.Spec {
+	return spec("ethernet-ip", model.Protocol(p.network), service.ServiceEtherNetIP, 44818)
+}
+
+func (p *EtherNetIPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, opts, p.network, t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	//cabecera de 24 bytes: comando, largo 0, sesion 0, estado 0, contexto y opciones en cero
+	req := make([]byte, 24)
+	binary.LittleEndian.PutUint16(req[0:2], enipListIdentity)
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := readENIP(conn, p.network)
+	if err != nil {
+		return nil, err
+	}
+	details, err := parseListIdentity(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	info := newInfo(service.ServiceEtherNetIP, details)
+	if details.DeviceType == "Programmable Logic Controller" {
+		info.DeviceType = "PLC"
+	}
+	if details.Serial != "" {
+		info.ExtraInfo = "serial " + details.Serial
+	}
+	return info, nil
+}
+
+// respuesta completa (cabecera y datos); por UDP llega en un datagrama
+func readENIP(conn net.Conn, network string) ([]byte, error) {
+	if network == "udp" {
+		buf := make([]byte, 1500)
+		n, err := conn.Read(buf)
+		return buf[:n], err
+	}
+	head := make([]byte, 24)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+	length := int(binary.LittleEndian.Uint16(head[2:4]))
+	if length > 4096 {
+		return nil, errors.New("enip response too large")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	return append(head, body...), nil
+}
+
+// item Identity: version, sockaddr, vendor, tipo, codigo, revision, estado, serie, nombre y estado
+func parseListIdentity(resp []byte) (*service.OTInfo, error) {
+	if len(resp) < 24 || binary.LittleEndian.Uint16(resp[0:2]) != enipListIdentity {
+		return nil, errors.New("not an enip listidentity response")
+	}
+	if status := binary.LittleEndian.Uint32(resp[8:12]); status != 0 {
+		return nil, fmt.Errorf("enip status 0x%x", status)
+	}
+	items := resp[24:]
+	if len(items) < 6 || binary.LittleEndian.Uint16(items[0:2]) == 0 {
+		return nil, errors.New("empty enip identity list")
+	}
+	kind := binary.LittleEndian.Uint16(items[2:4])
+	item := items[6:]
+	if kind != enipIdentityItem || len(item) < 33 {
+		return nil, errors.New("malformed enip identity item")
+	}
+
+	vendor := binary.LittleEndian.Uint16(item[18:20])
+	deviceType := binary.LittleEndian.Uint16(item[20:22])
+	details := &service.OTInfo{
+		Protocol:   "EtherNet/IP",
+		Vendor:     cipVendors[vendor],
+		VendorID:   int(vendor),
+		Model:      "product code " + strconv.Itoa(int(binary.LittleEndian.Uint16(item[22:24]))),
+		Revision:   fmt.Sprintf("%d.%d", item[24], item[25]),
+		Serial:     fmt.Sprintf("0x%08x", binary.LittleEndian.Uint32(item[28:32])),
+		DeviceType: cipDeviceTypes[deviceType],
+	}
+	if details.DeviceType == "" {
+		details.DeviceType = fmt.Sprintf("0x%02x", deviceType)
+	}
+	if size := int(item[32]); len(item) >= 33+size {
+		details.Product = cleanString(item[33 : 33+size])
+	}
+	return details, nil
+}
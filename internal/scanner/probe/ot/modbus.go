@@ -0,0 +1,183 @@
+package ot
+
+//MODBUS/TCP -> Read Device Identification (funcion 43, MEI 14)
+//paquete enviado: solo 0x2B/0x0E, de lectura; nunca funciones de escritura (5, 6, 15, 16, 22, 23)
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+	"strconv"
+)
+
+const (
+	modbusEncapsulated = 0x2b //Encapsulated Interface Transport
+	modbusDeviceID     = 0x0e //MEI Read Device Identification
+	modbusRegularID    = 0x02 //objetos basicos y regulares (0x00-0x06)
+	modbusMaxRequests  = 4    //respuestas fragmentadas (more follows)
+	modbusMaxStale     = 4    //respuestas atrasadas descartadas por request
+)
+
+// objetos de identificacion
+const (
+	modbusVendorName  = 0x00
+	modbusProductCode = 0x01
+	modbusRevision    = 0x02
+	modbusProductName = 0x04
+	modbusModelName   = 0x05
+)
+
+// unit ids probados: 0 y 255 son los habituales en equipos TCP nativos, 1 en gateways seriales
+var modbusUnits = []byte{0, 1, 255}
+
+// excepciones mas comunes
+var modbusExceptions = map[byte]string{
+	0x01: "illegal function",
+	0x02: "illegal data address",
+	0x03: "illegal data value",
+	0x0a: "gateway path unavailable",
+	0x0b: "gateway target failed to respond",
+}
+
+type ModbusProbe struct{}
+
+func NewModbusProbe() *ModbusProbe {
+	return &ModbusProbe{}
+}
+
+func (p *ModbusProbe) Spec() probe.Spec {
+	return spec("modbus", model.ProtocolTCP, service.ServiceModbus, 502)
+}
+
+func (p *ModbusProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	raw, err := dial(ctx, opts, "tcp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Close()
+	conn := &modbusConn{Conn: raw}
+
+	var exception string
+	for _, unit := range modbusUnits {
+		objects, err := conn.readDeviceID(unit)
+		var exc modbusException
+		if errors.As(err, &exc) {
+			//respondio en Modbus aunque no soporte la identificacion para este unit
+			exception = exc.Error()
+			continue
+		}
+		if err != nil {
+			if exception != "" {
+				break
+			}
+			return nil, err
+		}
+
+		details := &service.OTInfo{
+			Protocol: "Modbus",
+			Vendor:   objects[modbusVendorName],
+			Product:  objects[modbusProductName],
+			Model:    objects[modbusModelName],
+			Revision: objects[modbusRevision],
+			Address:  "unit " + strconv.Itoa(int(unit)),
+		}
+		if details.Product == "" {
+			details.Product = objects[modbusProductCode]
+		}
+		return newInfo(service.ServiceModbus, details), nil
+	}
+
+	info := newInfo(service.ServiceModbus, &service.OTInfo{Protocol: "Modbus"})
+	info.ExtraInfo = "device identification not supported: " + exception
+	return info, nil
+}
+
+type modbusException byte
+
+func (e modbusException) Error() string {
+	if s, ok := modbusExceptions[byte(e)]; ok {
+		return s
+	}
+	return fmt.Sprintf("exception 0x%02x", byte(e))
+}
+
+// conexion Modbus/TCP con un contador de transacciones unico para todos los units
+type modbusConn struct {
+	net.Conn
+	transaction uint16
+}
+
+// lee los objetos 0x00-0x06 siguiendo "more follows"
+func (c *modbusConn) readDeviceID(unit byte) (map[byte]string, error) {
+	objects := make(map[byte]string)
+	next := byte(0)
+	for i := 0; i < modbusMaxRequests; i++ {
+		pdu := []byte{modbusEncapsulated, modbusDeviceID, modbusRegularID, next}
+		resp, err := c.call(unit, pdu)
+		if err != nil {
+			return nil, err
+		}
+		if resp[0] == modbusEncapsulated|0x80 {
+			if len(resp) < 2 {
+				return nil, errors.New("short modbus exception")
+			}
+			return nil, modbusException(resp[1])
+		}
+		//0x2B 0x0E code conformity more next count, luego (id, largo, valor)
+		if len(resp) < 7 || resp[0] != modbusEncapsulated || resp[1] != modbusDeviceID {
+			return nil, errors.New("unexpected modbus response")
+		}
+		more, count := resp[4] == 0xff, int(resp[6])
+		next = resp[5]
+		rest := resp[7:]
+		for j := 0; j < count && len(rest) >= 2; j++ {
+			id, size := rest[0], int(rest[1])
+			if len(rest) < 2+size {
+				break
+			}
+			objects[id] = cleanString(rest[2 : 2+size])
+			rest = rest[2+size:]
+		}
+		if !more {
+			break
+		}
+	}
+	return objects, nil
+}
+
+// un request MBAP (transaccion, protocolo 0, largo, unit) y su respuesta
+// las respuestas de otra transaccion o de otro unit (atrasadas de un request anterior) se descartan
+func (c *modbusConn) call(unit byte, pdu []byte) ([]byte, error) {
+	c.transaction++
+	req := make([]byte, 7, 7+len(pdu))
+	binary.BigEndian.PutUint16(req[0:2], c.transaction)
+	binary.BigEndian.PutUint16(req[4:6], uint16(len(pdu)+1))
+	req[6] = unit
+	if _, err := c.Write(append(req, pdu...)); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i <= modbusMaxStale; i++ {
+		head := make([]byte, 7)
+		if _, err := io.ReadFull(c, head); err != nil {
+			return nil, err
+		}
+		length := int(binary.BigEndian.Uint16(head[4:6]))
+		if binary.BigEndian.Uint16(head[2:4]) != 0 || length < 2 || length > 260 {
+			return nil, errors.New("not a modbus response")
+		}
+		resp := make([]byte, length-1)
+		if _, err := io.ReadFull(c, resp); err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint16(head[0:2]) == c.transaction && head[6] == unit {
+			return resp, nil
+		}
+	}
+	return nil, errors.New("no modbus response for the transaction")
+}
@@ -0,0 +1,99 @@
+package ot
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// frame MBAP de respuesta
+func mbap(transaction uint16, unit byte, pdu []byte) []byte {
+	out := binary.BigEndian.AppendUint16(nil, transaction)
+	out = binary.BigEndian.AppendUint16(out, 0)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(pdu)+1))
+	out = append(out, unit)
+	return append(out, pdu...)
+}
+
+// respuesta de Read Device Identification con VendorName y ProductName
+func deviceID(vendor, product string) []byte {
+	pdu := []byte{modbusEncapsulated, modbusDeviceID, modbusRegularID, 0x02, 0x00, 0x00, 2}
+	pdu = append(pdu, modbusVendorName, byte(len(vendor)))
+	pdu = append(pdu, vendor...)
+	pdu = append(pdu, modbusProductName, byte(len(product)))
+	return append(pdu, product...)
+}
+
+// dispositivo en memoria: reply arma las respuestas de cada request (transaccion y unit recibidos)
+func fakeDevice(t *testing.T, reply func(transaction uint16, unit byte) [][]byte) (*modbusConn, *[]uint16) {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var transactions []uint16
+	go func() {
+		defer server.Close()
+		for {
+			head := make([]byte, 7)
+			if _, err := io.ReadFull(server, head); err != nil {
+				return
+			}
+			pdu := make([]byte, binary.BigEndian.Uint16(head[4:6])-1)
+			if _, err := io.ReadFull(server, pdu); err != nil {
+				return
+			}
+			transaction := binary.BigEndian.Uint16(head[0:2])
+			transactions = append(transactions, transaction)
+			for _, frame := range reply(transaction, head[6]) {
+				if _, err := server.Write(frame); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return &modbusConn{Conn: client}, &transactions
+}
+
+func TestModbusLateReply(t *testing.T) {
+	conn, transactions := fakeDevice(t, func(transaction uint16, unit byte) [][]byte {
+		if unit == 0 {
+			return [][]byte{mbap(transaction, unit, []byte{modbusEncapsulated | 0x80, 0x0b})}
+		}
+		//primero la respuesta atrasada de otro unit y de la transaccion anterior
+		return [][]byte{
+			mbap(transaction-1, 0, deviceID("Wrong", "Unit 0")),
+			mbap(transaction, 0, deviceID("Wrong", "Unit 0")),
+			mbap(transaction, unit, deviceID("Schneider Electric", "BMX P34 2020")),
+		}
+	})
+
+	if _, err := conn.readDeviceID(0); err == nil || err.Error() != "gateway target failed to respond" {
+		t.Fatalf("unit 0: %v, want gateway exception", err)
+	}
+	objects, err := conn.readDeviceID(1)
+	if err != nil {
+		t.Fatalf("unit 1: %v", err)
+	}
+	if objects[modbusVendorName] != "Schneider Electric" || objects[modbusProductName] != "BMX P34 2020" {
+		t.Errorf("unit 1 objects = %q", objects)
+	}
+	if got := *transactions; len(got) != 2 || got[0] == got[1] {
+		t.Errorf("transactions = %v, want one per request without repeats", got)
+	}
+}
+
+func TestModbusStaleLimit(t *testing.T) {
+	conn, _ := fakeDevice(t, func(transaction uint16, unit byte) [][]byte {
+		var frames [][]byte
+		for i := 0; i <= modbusMaxStale; i++ {
+			frames = append(frames, mbap(transaction+1, unit, deviceID("Wrong", "Other")))
+		}
+		return frames
+	})
+	if objects, err := conn.readDeviceID(1); err == nil {
+		t.Errorf("readDeviceID = %q, want error after stale replies", objects)
+	}
+}
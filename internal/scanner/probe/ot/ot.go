@@ -0,0 +1,53 @@
+package ot
+
+//PROBERS INDUSTRIALES (OT) -> solo identificacion del dispositivo
+//ningun paquete de este paquete escribe, fuerza salidas, cambia el modo del equipo ni lee el proceso;
+//cada request usa solo funciones de identificacion o diagnostico documentadas junto al paquete
+import (
+	"context"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"strings"
+)
+
+// conexion con deadline para todo el dialogo ("tcp" o "udp")
+func dial(ctx context.Context, opts probe.Options, network string, t probe.Target) (net.Conn, error) {
+	return opts.Dial(ctx, network, t.Address())
+}
+
+// el servicio identificado o su puerto habitual, en el grupo "ot"
+// explicitos: "all" no los incluye, la policy debe nombrarlos (ningun perfil incluido lo hace)
+func spec(name string, proto model.Protocol, svc service.ServiceType, ports ...int) probe.Spec {
+	return probe.Spec{
+		Name:     name,
+		Protocol: proto,
+		Services: []service.ServiceType{svc},
+		Ports:    ports,
+		Groups:   []string{"ot"},
+		Explicit: true,
+	}
+}
+
+// fingerprint base con el detalle OT
+func newInfo(svc service.ServiceType, details *service.OTInfo) *service.ServiceInfo {
+	info := &service.ServiceInfo{
+		Type:       svc,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		OT:         details,
+	}
+	if details.Vendor != "" {
+		info.Product = strings.TrimSpace(details.Vendor + " " + details.Product)
+	} else {
+		info.Product = details.Product
+	}
+	info.Version = details.Revision
+	return info
+}
+
+// texto ASCII de un campo de largo fijo (relleno con espacios o ceros)
+func cleanString(b []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+}
@@ -0,0 +1,224 @@
+package ot
+
+//S7COMM -> lectura de las listas SZL 0x0011 (identificacion del modulo) y 0x001C (componentes)
+//paquetes enviados: COTP Connection Request, Setup Communication y userdata "read SZL" (grupo 4, subfuncion 1);
+//no se usan read/write var, download, PLC stop ni ninguna otra funcion de job
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+)
+
+// TSAP destino: rack 0 slot 2 (S7-300/400) y luego el usado por S7-1200/1500
+var s7DestTSAPs = []uint16{0x0102, 0x0200}
+
+// indices de SZL 0x0011
+const (
+	szlModule   = 0x0001
+	szlHardware = 0x0006
+	szlFirmware = 0x0007
+)
+
+// indices de SZL 0x001C
+const (
+	szlPLCName    = 0x0001
+	szlModuleName = 0x0002
+	szlSerial     = 0x0005
+	szlModuleType = 0x0007
+)
+
+type S7Probe struct{}
+
+func NewS7Probe() *S7Probe {
+	return &S7Probe{}
+}
+
+func (p *S7Probe) Spec() probe.Spec {
+	return spec("s7", model.ProtocolTCP, service.ServiceS7, 102)
+}
+
+func (p *S7Probe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	var lastErr error
+	for _, tsap := range s7DestTSAPs {
+		details, err := s7Identify(ctx, opts, t, tsap)
+		if err == nil {
+			info := newInfo(service.ServiceS7, details)
+			info.DeviceType = "PLC"
+			if details.Name != "" {
+				info.ExtraInfo = details.Name
+			}
+			return info, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func s7Identify(ctx context.Context, opts probe.Options, t probe.Target, tsap uint16) (*service.OTInfo, error) {
+	conn, err := dial(ctx, opts, "tcp", t)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	//COTP CR: TPDU size 1024, TSAP origen 0x0100 y destino
+	cr := []byte{0xe0, 0x00, 0x00, 0x00, 0x01, 0x00, 0xc0, 0x01, 0x0a, 0xc1, 0x02, 0x01, 0x00, 0xc2, 0x02, byte(tsap >> 8), byte(tsap)}
+	if err := tpktWrite(conn, append([]byte{byte(len(cr))}, cr...)); err != nil {
+		return nil, err
+	}
+	resp, err := tpktRead(conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 || resp[1]&0xf0 != 0xd0 {
+		return nil, errors.New("cotp connection refused")
+	}
+
+	//Setup Communication: 1 job en paralelo por lado, PDU de 480 bytes
+	setup := []byte{0xf0, 0x00, 0x00, 0x01, 0x00, 0x01, 0x01, 0xe0}
+	if _, err := s7Call(conn, 0x01, setup, nil); err != nil {
+		return nil, fmt.Errorf("s7 setup communication: %w", err)
+	}
+
+	details := &service.OTInfo{Protocol: "S7comm", Vendor: "Siemens"}
+	records, err := readSZL(conn, 0x0011)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if len(r) < 28 {
+			continue
+		}
+		switch binary.BigEndian.Uint16(r[0:2]) {
+		case szlModule:
+			details.Model = cleanString(r[2:22])
+		case szlFirmware:
+			if r[24] == 'V' {
+				details.Revision = fmt.Sprintf("%d.%d.%d", r[25], r[26], r[27])
+			}
+		case szlHardware:
+			if details.Model == "" {
+				details.Model = cleanString(r[2:22])
+			}
+		}
+	}
+
+	//componentes: no todos los CPU la exponen
+	if records, err := readSZL(conn, 0x001c); err == nil {
+		for _, r := range records {
+			if len(r) < 3 {
+				continue
+			}
+			text := cleanString(r[2:])
+			switch binary.BigEndian.Uint16(r[0:2]) {
+			case szlPLCName:
+				details.Name = text
+			case szlModuleName:
+				if details.Name == "" {
+					details.Name = text
+				}
+			case szlSerial:
+				details.Serial = text
+			case szlModuleType:
+				details.Product = text
+			}
+		}
+	}
+	return details, nil
+}
+
+// userdata "read SZL" con indice 0 (todos los registros) y los registros de la respuesta
+func readSZL(conn net.Conn, id uint16) ([][]byte, error) {
+	params := []byte{0x00, 0x01, 0x12, 0x04, 0x11, 0x44, 0x01, 0x00}
+	data := []byte{0xff, 0x09, 0x00, 0x04, byte(id >> 8), byte(id), 0x00, 0x00}
+	resp, err := s7Call(conn, 0x07, params, data)
+	if err != nil {
+		return nil, err
+	}
+
+	//return code, transport size, largo, id, indice, largo de registro, cantidad
+	if len(resp) < 12 || resp[0] != 0xff {
+		return nil, fmt.Errorf("szl 0x%04x not available", id)
+	}
+	size := int(binary.BigEndian.Uint16(resp[8:10]))
+	count := int(binary.BigEndian.Uint16(resp[10:12]))
+	if size == 0 {
+		return nil, errors.New("invalid szl record size")
+	}
+	var records [][]byte
+	for rest := resp[12:]; count > 0 && len(rest) >= size; count-- {
+		records = append(records, rest[:size])
+		rest = rest[size:]
+	}
+	return records, nil
+}
+
+// PDU S7 (job o userdata); retorna la seccion de datos de la respuesta
+func s7Call(conn net.Conn, rosctr byte, params, data []byte) ([]byte, error) {
+	pdu := []byte{0x02, 0xf0, 0x80, 0x32, rosctr, 0x00, 0x00, 0x00, 0x01}
+	pdu = binary.BigEndian.AppendUint16(pdu, uint16(len(params)))
+	pdu = binary.BigEndian.AppendUint16(pdu, uint16(len(data)))
+	pdu = append(append(pdu, params...), data...)
+	if err := tpktWrite(conn, pdu); err != nil {
+		return nil, err
+	}
+
+	resp, err := tpktRead(conn)
+	if err != nil {
+		return nil, err
+	}
+	//COTP DT (3 bytes) y cabecera S7: los ack de job traen 2 bytes de error mas
+	if len(resp) < 13 || resp[3] != 0x32 {
+		return nil, errors.New("not an s7comm response")
+	}
+	header := 13
+	switch resp[4] {
+	case 0x03:
+		header = 15
+		if len(resp) < header {
+			return nil, errors.New("short s7comm ack")
+		}
+		if resp[13] != 0 || resp[14] != 0 {
+			return nil, fmt.Errorf("s7comm error class 0x%02x code 0x%02x", resp[13], resp[14])
+		}
+	case 0x07:
+	default:
+		return nil, fmt.Errorf("unexpected s7comm rosctr %d", resp[4])
+	}
+	paramLen := int(binary.BigEndian.Uint16(resp[9:11]))
+	dataLen := int(binary.BigEndian.Uint16(resp[11:13]))
+	if header+paramLen+dataLen > len(resp) {
+		return nil, errors.New("truncated s7comm response")
+	}
+	return resp[header+paramLen : header+paramLen+dataLen], nil
+}
+
+// TPKT (RFC 1006): version 3, reservado, largo total
+func tpktWrite(conn net.Conn, payload []byte) error {
+	head := []byte{0x03, 0x00, 0x00, 0x00}
+	binary.BigEndian.PutUint16(head[2:4], uint16(len(payload)+4))
+	_, err := conn.Write(append(head, payload...))
+	return err
+}
+
+func tpktRead(conn net.Conn) ([]byte, error) {
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint16(head[2:4]))
+	if head[0] != 0x03 || length < 7 {
+		return nil, errors.New("not a tpkt packet")
+	}
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
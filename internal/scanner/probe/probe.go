@@ -1,9 +1,80 @@
 package probe
 
-import "time"
+//INTERFAZ DE PROBERS -> contexto, opciones de conexion de la campaña, servicios declarados y dependencias
+import (
+	"context"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"strconv"
+)
 
 // definer el comportamiento de un prober
 type Prober interface {
-	//ejecutar la prueba activa sobre una direccion y puerto
-	Probe(target string, port int, timeout time.Duration) (string, error)
+	//lo que el prober declara para que el engine lo programe
+	Spec() Spec
+	//ejecutar la prueba activa sobre el servicio del target
+	//retorna lo identificado del servicio (producto, version, TLS, detalle por protocolo)
+	Probe(ctx context.Context, target Target, opts Options) (*service.ServiceInfo, error)
+}
+
+// prober con condiciones sobre lo ya identificado, ademas del servicio y puerto (ej. jarm solo con TLS directo)
+type Matcher interface {
+	Match(target Target) bool
+}
+
+// declaracion de un prober: a que servicios y puertos aplica y que debe correr antes
+type Spec struct {
+	Name     string                //nombre en la policy (--probe-types) y en Requires
+	Protocol model.Protocol        //transporte del puerto
+	Services []service.ServiceType //servicios a los que aplica (vacio = cualquiera)
+	Ports    []int                 //puertos donde se intenta si el servicio no se identifico
+	Requires []string              //probers que se ejecutan antes cuando aplican al mismo puerto (ej. tls antes de http)
+	Groups   []string              //grupos de la policy que lo incluyen ("starttls", "database", ...)
+	Explicit bool                  //solo corre si la policy lo nombra a el o a su grupo ("all" no lo incluye)
+}
+
+// el prober aplica al servicio identificado, o al puerto si el servicio es desconocido
+func (s Spec) Matches(t Target) bool {
+	if s.Protocol != t.Protocol {
+		return false
+	}
+	if len(s.Services) == 0 && len(s.Ports) == 0 {
+		return true
+	}
+	for _, svc := range s.Services {
+		if svc == t.Service.Type {
+			return true
+		}
+	}
+	if t.Service.Type == service.ServiceUnknown {
+		for _, port := range s.Ports {
+			if port == t.Port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// servicio sobre el que corre un prober
+type Target struct {
+	Host     string               //IP o nombre a conectar
+	Port     int                  //puerto del servicio
+	Protocol model.Protocol       //transporte
+	Service  *service.ServiceInfo //lo identificado hasta ahora (deteccion y probers anteriores), nunca nil
+	VHosts   []string             //nombres del host para Host/SNI/realm, el primero es el principal
+}
+
+// host:puerto del servicio
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// nombre principal del host ("" si solo se conoce la IP)
+func (t Target) Hostname() string {
+	if len(t.VHosts) == 0 {
+		return ""
+	}
+	return t.VHosts[0]
 }
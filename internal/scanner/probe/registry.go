@@ -1,31 +1,98 @@
 package probe
 
-//REGISTRO DE PROBERS
+//REGISTRO DE PROBERS -> catalogo explicito (sin init) y planificacion por servicio detectado
 import (
-	"go-scanner/internal/scanner/probe/http"
+	"go-scanner/internal/model"
 	"strings"
 )
 
-// registro global de probers
-var registry = make(map[string]Prober)
+// catalogo de probers de una campaña, el orden de registro es el orden de ejecucion
+// (ver probe/builtin para los incluidos)
+type Registry struct {
+	probers []Prober
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
 
-// registrar prober
-func Register(name string, p Prober) {
-	registry[strings.ToLower(name)] = p
+// agrega un prober; si ya hay uno con el mismo nombre y protocolo lo reemplaza en su lugar
+func (r *Registry) Register(p Prober) {
+	spec := p.Spec()
+	for i, existing := range r.probers {
+		if s := existing.Spec(); s.Protocol == spec.Protocol && strings.EqualFold(s.Name, spec.Name) {
+			r.probers[i] = p
+			return
+		}
+	}
+	r.probers = append(r.probers, p)
 }
 
-// obtener prober mediante nombre
-func Get(name string) (Prober, bool) {
-	p, ok := registry[strings.ToLower(name)]
-	return p, ok
+// probers registrados, en orden
+func (r *Registry) Probers() []Prober {
+	return r.probers
 }
 
-// default probers
-func init() {
-	//http/s apuntan al mismo prober
-	h := http.NewHTTPProbe()
-	Register("http", h)
-	Register("https", h)
+// obtener prober mediante protocolo y nombre
+func (r *Registry) Get(proto model.Protocol, name string) (Prober, bool) {
+	for _, p := range r.probers {
+		if s := p.Spec(); s.Protocol == proto && strings.EqualFold(s.Name, name) {
+			return p, true
+		}
+	}
+	return nil, false
 }
 
+// la policy habilita el prober: por nombre, por grupo o con "all" (salvo los explicitos)
+func Allowed(spec Spec, allowed []string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == strings.ToLower(spec.Name) {
+			return true
+		}
+		for _, g := range spec.Groups {
+			if a == strings.ToLower(g) {
+				return true
+			}
+		}
+		if a == "all" && !spec.Explicit {
+			return true
+		}
+	}
+	return false
+}
+
+// siguiente prober a ejecutar sobre el target
+// aplica al servicio/puerto, esta habilitado y no corrio aun (done, por nombre);
+// si requiere otro que tambien aplica y no corrio, espera a que corra primero.
+// el engine vuelve a llamar tras integrar cada resultado, asi un prober puede
+// habilitar a otros (ej. tls identifica HTTPS y entonces aplica http)
+func (r *Registry) Next(t Target, allowed func(Spec) bool, done map[string]bool) (Prober, bool) {
+	applies := func(p Prober) bool {
+		spec := p.Spec()
+		if done[strings.ToLower(spec.Name)] || !spec.Matches(t) || !allowed(spec) {
+			return false
+		}
+		if m, ok := p.(Matcher); ok && !m.Match(t) {
+			return false
+		}
+		return true
+	}
 
+	for _, p := range r.probers {
+		if !applies(p) {
+			continue
+		}
+		blocked := false
+		for _, name := range p.Spec().Requires {
+			if req, ok := r.Get(t.Protocol, name); ok && applies(req) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			return p, true
+		}
+	}
+	return nil, false
+}
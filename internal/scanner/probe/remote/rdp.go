@@ -0,0 +1,196 @@
+package remote
+
+//RDP -> X.224 Connection Request con RDP_NEG_REQ y challenge NTLM de CredSSP
+import (
+	"context"
+	"crypto/tls"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/ntlm"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+)
+
+// requestedProtocols de RDP_NEG_REQ
+const (
+	protocolRDP    = 0x00000000
+	protocolSSL    = 0x00000001
+	protocolHybrid = 0x00000002
+)
+
+// tipos de la respuesta de negociacion
+const (
+	negResponse = 0x02
+	negFailure  = 0x03
+)
+
+// codigo de RDP_NEG_FAILURE: el servidor exige CredSSP
+const hybridRequiredByServer = 0x00000005
+
+// cada protocolo se pide por separado para saber cuales acepta el servidor
+var rdpProtocols = []struct {
+	name      string
+	requested uint32
+	selected  uint32
+}{
+	{"RDP", protocolRDP, protocolRDP},
+	{"TLS", protocolSSL, protocolSSL},
+	{"CredSSP", protocolSSL | protocolHybrid, protocolHybrid},
+}
+
+type RDPProbe struct{}
+
+func NewRDPProbe() *RDPProbe {
+	return &RDPProbe{}
+}
+
+func (p *RDPProbe) Spec() probe.Spec {
+	return spec("rdp", service.ServiceRDP, 3389)
+}
+
+func (p *RDPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	details := &service.RDPInfo{}
+	answered := false
+
+	for _, proto := range rdpProtocols {
+		conn, err := dial(ctx, t, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		neg, err := connectionRequest(conn, proto.requested)
+		switch {
+		case err != nil:
+			//sin X.224 valido: no es RDP (o cerro la conexion)
+		case neg == nil:
+			//servidor previo a la negociacion (RDP 5): solo seguridad estandar
+			answered = true
+			if proto.requested == protocolRDP {
+				details.Protocols = append(details.Protocols, proto.name)
+			}
+		case neg.kind == negResponse && neg.value == proto.selected:
+			answered = true
+			details.Protocols = append(details.Protocols, proto.name)
+			if proto.selected == protocolHybrid {
+				details.NTLM = credSSPChallenge(conn)
+			}
+		case neg.kind == negFailure:
+			answered = true
+			if proto.requested == protocolRDP && neg.value == hybridRequiredByServer {
+				details.NLARequired = true
+			}
+		default:
+			answered = true
+		}
+		conn.Close()
+	}
+	if !answered {
+		return nil, errors.New("no x.224 connection confirm")
+	}
+
+	info := &service.ServiceInfo{
+		Type:       service.ServiceRDP,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		RDP:        details,
+	}
+	if n := details.NTLM; n != nil {
+		info.Product = "Microsoft Terminal Services"
+		info.Hostname = n.DNSComputer
+		if info.Hostname == "" {
+			info.Hostname = n.NetBIOSComputer
+		}
+		if n.OSVersion != "" {
+			info.OSHint = "Windows"
+			info.ExtraInfo = n.OSVersion
+			if release := ntlm.WindowsRelease(n.OSVersion); release != "" {
+				info.ExtraInfo = release + " " + n.OSVersion
+			}
+		}
+	}
+	return info, nil
+}
+
+// resultado de la negociacion (RDP_NEG_RSP o RDP_NEG_FAILURE)
+type negotiation struct {
+	kind  byte
+	value uint32 //protocolo seleccionado o codigo de error
+}
+
+// envia el Connection Request y lee el Connection Confirm
+// retorna nil sin error si el servidor no incluye datos de negociacion
+func connectionRequest(conn net.Conn, requested uint32) (*negotiation, error) {
+	//X.224: LI, CR, dst-ref, src-ref, clase 0 + RDP_NEG_REQ (tipo, flags, largo 8, protocolos)
+	x224 := []byte{14, 0xe0, 0, 0, 0, 0, 0}
+	x224 = append(x224, 0x01, 0x00, 0x08, 0x00)
+	x224 = binary.LittleEndian.AppendUint32(x224, requested)
+
+	//TPKT: version 3, reservado, largo total
+	packet := []byte{0x03, 0x00}
+	packet = binary.BigEndian.AppendUint16(packet, uint16(4+len(x224)))
+	if _, err := conn.Write(append(packet, x224...)); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint16(head[2:4]))
+	if head[0] != 0x03 || length < 11 || length > 1024 {
+		return nil, errors.New("not a tpkt response")
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	if body[1]&0xf0 != 0xd0 {
+		return nil, fmt.Errorf("unexpected x.224 code 0x%02x", body[1])
+	}
+	if int(body[0]) < 14 || len(body) < 15 {
+		return nil, nil
+	}
+	return &negotiation{kind: body[7], value: binary.LittleEndian.Uint32(body[11:15])}, nil
+}
+
+// TSRequest de CredSSP con un token NTLM (la respuesta trae el CHALLENGE)
+type tsRequest struct {
+	Version    int         `asn1:"explicit,tag:0"`
+	NegoTokens []negoToken `asn1:"explicit,tag:1"`
+}
+
+type negoToken struct {
+	Token []byte `asn1:"explicit,tag:0"`
+}
+
+// TLS sobre la misma conexion y NEGOTIATE NTLM dentro de un TSRequest
+func credSSPChallenge(conn net.Conn) *service.NTLMInfo {
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true}) //solo se lee el challenge
+	if err := tlsConn.Handshake(); err != nil {
+		return nil
+	}
+
+	req, err := asn1.Marshal(tsRequest{Version: 2, NegoTokens: []negoToken{{Token: ntlm.Negotiate()}}})
+	if err != nil {
+		return nil
+	}
+	if _, err := tlsConn.Write(req); err != nil {
+		return nil
+	}
+
+	buf := make([]byte, 4096)
+	n, err := tlsConn.Read(buf)
+	if err != nil {
+		return nil
+	}
+	info, err := ntlm.ParseChallenge(buf[:n])
+	if err != nil {
+		return nil
+	}
+	return info
+}
@@ -0,0 +1,25 @@
+package remote
+
+//PROBERS DE ESCRITORIO REMOTO -> solo la negociacion inicial, nunca se autentica
+import (
+	"context"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+)
+
+// conexion con deadline para todo el dialogo
+func dial(ctx context.Context, t probe.Target, opts probe.Options) (net.Conn, error) {
+	return opts.Dial(ctx, "tcp", t.Address())
+}
+
+// el servicio identificado o su puerto habitual
+func spec(name string, svc service.ServiceType, ports ...int) probe.Spec {
+	return probe.Spec{
+		Name:     name,
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{svc},
+		Ports:    ports,
+	}
+}
@@ -0,0 +1,138 @@
+package remote
+
+//VNC -> version RFB y tipos de seguridad ofrecidos (no se elige ninguno)
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// "RFB 003.008\n"
+var rfbVersion = regexp.MustCompile(`^RFB (\d{3})\.(\d{3})\n$`)
+
+// tipo de seguridad sin autenticacion
+const securityNone = 1
+
+// tipos de seguridad conocidos (registro IANA de RFB)
+var securityTypes = map[byte]string{
+	1:   "None",
+	2:   "VNC Authentication",
+	5:   "RA2",
+	6:   "RA2ne",
+	16:  "Tight",
+	17:  "Ultra",
+	18:  "TLS",
+	19:  "VeNCrypt",
+	20:  "SASL",
+	21:  "MD5",
+	22:  "xvp",
+	30:  "Apple Remote Desktop",
+	35:  "Apple Remote Desktop (SRP)",
+	113: "MS-Logon II",
+	129: "Unix Login",
+}
+
+type VNCProbe struct{}
+
+func NewVNCProbe() *VNCProbe {
+	return &VNCProbe{}
+}
+
+func (p *VNCProbe) Spec() probe.Spec {
+	return spec("vnc", service.ServiceVNC, 5900, 5901)
+}
+
+func (p *VNCProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := dial(ctx, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	greeting := make([]byte, 12)
+	if _, err := io.ReadFull(conn, greeting); err != nil {
+		return nil, err
+	}
+	m := rfbVersion.FindSubmatch(greeting)
+	if m == nil {
+		return nil, fmt.Errorf("not an rfb greeting: %q", greeting)
+	}
+	major, _ := strconv.Atoi(string(m[1]))
+	minor, _ := strconv.Atoi(string(m[2]))
+
+	details := &service.VNCInfo{Version: fmt.Sprintf("%d.%d", major, minor)}
+	info := &service.ServiceInfo{
+		Type:       service.ServiceVNC,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		VNC:        details,
+	}
+	if major == 3 && minor == 889 {
+		info.Product = "Apple Remote Desktop"
+		info.OSHint = "macOS"
+	}
+
+	//se responde con la version mas alta que ambos soportan (3.3, 3.7 o 3.8)
+	reply := "RFB 003.008\n"
+	switch {
+	case major == 3 && minor < 7:
+		reply = "RFB 003.003\n"
+	case major == 3 && minor == 7:
+		reply = "RFB 003.007\n"
+	}
+	if _, err := conn.Write([]byte(reply)); err != nil {
+		return info, nil
+	}
+
+	types, err := readSecurityTypes(conn, reply == "RFB 003.003\n")
+	if err != nil {
+		return info, nil
+	}
+	for _, t := range types {
+		name, ok := securityTypes[t]
+		if !ok {
+			name = strconv.Itoa(int(t))
+		}
+		details.SecurityTypes = append(details.SecurityTypes, name)
+		if t == securityNone {
+			details.NoAuth = true
+			info.ExtraInfo = "no authentication"
+		}
+	}
+	return info, nil
+}
+
+// 3.3: el servidor elige un tipo (uint32); 3.7+: lista de tipos (o 0 y un motivo)
+func readSecurityTypes(r io.Reader, v33 bool) ([]byte, error) {
+	if v33 {
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		t := binary.BigEndian.Uint32(buf)
+		if t == 0 || t > 255 {
+			return nil, errors.New("connection refused by server")
+		}
+		return []byte{byte(t)}, nil
+	}
+
+	count := make([]byte, 1)
+	if _, err := io.ReadFull(r, count); err != nil {
+		return nil, err
+	}
+	if count[0] == 0 {
+		return nil, errors.New("connection refused by server")
+	}
+	types := make([]byte, count[0])
+	if _, err := io.ReadFull(r, types); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
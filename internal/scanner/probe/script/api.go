@@ -0,0 +1,427 @@
+package script
+
+//API DEL HOST -> lo unico que un script puede hacer: conectarse al target, regex y reportar
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// claves de thread.Local
+const (
+	declKey       = "script.decl"       //carga del archivo: acepta probe(...)
+	invocationKey = "script.invocation" //ejecucion de run(target)
+)
+
+// lectura maxima por recv
+const maxRecv = 64 * 1024
+
+// estado de una ejecucion de run(target)
+type invocation struct {
+	ctx    context.Context
+	script *Script
+	target probe.Target
+	opts   probe.Options
+	conns  []*conn
+	info   *service.ServiceInfo //lo reportado con finding() y service()
+}
+
+// cierra las conexiones que el script dejo abiertas
+func (inv *invocation) closeAll() {
+	for _, c := range inv.conns {
+		c.Close()
+	}
+}
+
+// resultado acumulado de la invocacion
+func (inv *invocation) result() *service.ServiceInfo {
+	if inv.info == nil {
+		inv.info = &service.ServiceInfo{}
+	}
+	return inv.info
+}
+
+// nombres disponibles en los scripts
+func predeclared() starlark.StringDict {
+	return starlark.StringDict{
+		"probe":   starlark.NewBuiltin("probe", declare),
+		"connect": starlark.NewBuiltin("connect", connect),
+		"finding": starlark.NewBuiltin("finding", finding),
+		"service": starlark.NewBuiltin("service", serviceFact),
+		"hex":     starlark.NewBuiltin("hex", hexEncode),
+		"unhex":   starlark.NewBuiltin("unhex", hexDecode),
+		"regex": &starlarkstruct.Module{
+			Name: "regex",
+			Members: starlark.StringDict{
+				"match":   starlark.NewBuiltin("regex.match", regexMatch),
+				"search":  starlark.NewBuiltin("regex.search", regexSearch),
+				"findall": starlark.NewBuiltin("regex.findall", regexFindAll),
+			},
+		},
+	}
+}
+
+// la invocacion en curso, las funciones de red y de reporte solo existen dentro de run()
+func current(thread *starlark.Thread, fn *starlark.Builtin) (*invocation, error) {
+	inv, ok := thread.Local(invocationKey).(*invocation)
+	if !ok {
+		return nil, fmt.Errorf("%s: only allowed inside run()", fn.Name())
+	}
+	return inv, nil
+}
+
+// datos del target que recibe run()
+func targetValue(t probe.Target) starlark.Value {
+	s := t.Service
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"host":     starlark.String(t.Host),
+		"port":     starlark.MakeInt(t.Port),
+		"protocol": starlark.String(t.Protocol),
+		"hostname": starlark.String(t.Hostname()),
+		"service":  starlark.String(s.Type),
+		"product":  starlark.String(s.Product),
+		"version":  starlark.String(s.Version),
+		"tls":      starlark.Bool(s.TLSInfo != nil && s.StartTLS == nil),
+	})
+}
+
+// connect(timeout=None, tls=False): conexion al puerto del target con el protocolo del script
+// timeout en segundos cubre el dialogo de la conexion; tls=True hace el handshake al conectar
+func connect(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	inv, err := current(thread, fn)
+	if err != nil {
+		return nil, err
+	}
+	var timeout starlark.Value = starlark.None
+	useTLS := false
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "timeout?", &timeout, "tls?", &useTLS); err != nil {
+		return nil, err
+	}
+
+	//cuenta todas las conexiones de la invocacion, aunque el script ya las haya cerrado
+	if len(inv.conns) >= MaxConns {
+		return nil, fmt.Errorf("%s: connection limit reached (%d per run)", fn.Name(), MaxConns)
+	}
+
+	opts := inv.opts
+	if timeout != starlark.None {
+		secs, ok := starlark.AsFloat(timeout)
+		if !ok || secs <= 0 {
+			return nil, fmt.Errorf("%s: invalid timeout %s", fn.Name(), timeout)
+		}
+		opts.Timeout = time.Duration(secs * float64(time.Second))
+	}
+	if useTLS && inv.script.proto != model.ProtocolTCP {
+		return nil, fmt.Errorf("%s: tls is only supported over tcp", fn.Name())
+	}
+
+	address := inv.target.Address()
+	var c net.Conn
+	if useTLS {
+		c, err = opts.DialTLS(inv.ctx, address, opts.TLSConfig(tlsName(inv.target)))
+	} else {
+		c, err = opts.Dial(inv.ctx, string(inv.script.proto), address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+
+	sc := &conn{Conn: c, inv: inv}
+	inv.conns = append(inv.conns, sc)
+	return sc, nil
+}
+
+// conexion expuesta al script: send, recv, starttls y close
+type conn struct {
+	net.Conn
+	inv    *invocation
+	closed bool
+}
+
+func (c *conn) String() string        { return fmt.Sprintf("<conn %s>", c.RemoteAddr()) }
+func (c *conn) Type() string          { return "conn" }
+func (c *conn) Freeze()               {}
+func (c *conn) Truth() starlark.Bool  { return starlark.Bool(!c.closed) }
+func (c *conn) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable: conn") }
+
+func (c *conn) AttrNames() []string { return []string{"close", "recv", "send", "starttls"} }
+
+func (c *conn) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "send":
+		return starlark.NewBuiltin("conn.send", c.send), nil
+	case "recv":
+		return starlark.NewBuiltin("conn.recv", c.recv), nil
+	case "starttls":
+		return starlark.NewBuiltin("conn.starttls", c.starttls), nil
+	case "close":
+		return starlark.NewBuiltin("conn.close", c.close), nil
+	}
+	return nil, nil
+}
+
+func (c *conn) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.Conn.Close()
+}
+
+// send(data): string o bytes
+func (c *conn) send(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var data starlark.Value
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &data); err != nil {
+		return nil, err
+	}
+	var payload string
+	switch v := data.(type) {
+	case starlark.String:
+		payload = string(v)
+	case starlark.Bytes:
+		payload = string(v)
+	default:
+		return nil, fmt.Errorf("%s: got %s, want string or bytes", fn.Name(), data.Type())
+	}
+	if _, err := c.Write([]byte(payload)); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+// recv(n=4096): lo que llegue en una lectura (hasta n bytes), "" si se cerro o vencio el tiempo
+func (c *conn) recv(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	n := 4096
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "n?", &n); err != nil {
+		return nil, err
+	}
+	if n <= 0 || n > maxRecv {
+		return nil, fmt.Errorf("%s: n must be between 1 and %d", fn.Name(), maxRecv)
+	}
+
+	buf := make([]byte, n)
+	read, err := c.Read(buf)
+	if err != nil && read == 0 && !errors.Is(err, os.ErrDeadlineExceeded) && !errors.Is(err, io.EOF) {
+		if c.inv.ctx.Err() != nil {
+			return nil, c.inv.ctx.Err()
+		}
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.String(buf[:read]), nil
+}
+
+// starttls(server_name=None): upgrade a TLS sobre la misma conexion (tras el comando del protocolo)
+func (c *conn) starttls(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	name := tlsName(c.inv.target)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "server_name?", &name); err != nil {
+		return nil, err
+	}
+	if _, ok := c.Conn.(*tls.Conn); ok {
+		return nil, fmt.Errorf("%s: connection already uses tls", fn.Name())
+	}
+
+	tlsConn := tls.Client(c.Conn, c.inv.opts.TLSConfig(name))
+	if err := tlsConn.HandshakeContext(c.inv.ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	c.Conn = tlsConn
+
+	state := tlsConn.ConnectionState()
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"version": starlark.String(tls.VersionName(state.Version)),
+		"cipher":  starlark.String(tls.CipherSuiteName(state.CipherSuite)),
+		"alpn":    starlark.String(state.NegotiatedProtocol),
+	}), nil
+}
+
+func (c *conn) close(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	c.Close()
+	return starlark.None, nil
+}
+
+// finding(name, severity="info", id=<script>, description="", extracted=[])
+func finding(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	inv, err := current(thread, fn)
+	if err != nil {
+		return nil, err
+	}
+	var name, description string
+	id := inv.script.name
+	severity := string(service.SeverityInfo)
+	var extracted *starlark.List
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+		"name", &name, "severity?", &severity, "id?", &id, "description?", &description, "extracted?", &extracted); err != nil {
+		return nil, err
+	}
+	sev, ok := service.ParseSeverity(severity)
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown severity %q", fn.Name(), severity)
+	}
+	values, err := stringList(extracted)
+	if err != nil {
+		return nil, fmt.Errorf("%s: extracted: %w", fn.Name(), err)
+	}
+
+	info := inv.result()
+	info.Findings = append(info.Findings, service.Finding{
+		ID:          id,
+		Name:        name,
+		Severity:    sev,
+		Description: description,
+		Tags:        inv.script.tags,
+		Source:      "script",
+		Matched:     string(inv.script.proto) + "://" + inv.target.Address(),
+		Extracted:   values,
+	})
+	return starlark.None, nil
+}
+
+// service(type="", product="", version="", extra_info="", hostname="", os="", device_type="", cpe=[])
+// datos del servicio con confianza media: no pisan lo que identifico un prober incluido
+func serviceFact(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	inv, err := current(thread, fn)
+	if err != nil {
+		return nil, err
+	}
+	var typ, product, version, extra, hostname, osHint, device string
+	var cpe *starlark.List
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+		"type?", &typ, "product?", &product, "version?", &version, "extra_info?", &extra,
+		"hostname?", &hostname, "os?", &osHint, "device_type?", &device, "cpe?", &cpe); err != nil {
+		return nil, err
+	}
+	cpes, err := stringList(cpe)
+	if err != nil {
+		return nil, fmt.Errorf("%s: cpe: %w", fn.Name(), err)
+	}
+
+	info := inv.result()
+	fact := &service.ServiceInfo{
+		Product:    product,
+		Version:    version,
+		ExtraInfo:  extra,
+		Hostname:   hostname,
+		OSHint:     osHint,
+		DeviceType: device,
+		CPE:        cpes,
+		Confidence: model.ConfidenceMedium,
+	}
+	if typ != "" {
+		fact.Type = service.TypeFromName(typ)
+		fact.Method = service.MethodProbe
+	}
+	info.Merge(fact)
+	return starlark.None, nil
+}
+
+// hex(data) -> "0a0b..."
+func hexEncode(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var data starlark.Value
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &data); err != nil {
+		return nil, err
+	}
+	switch v := data.(type) {
+	case starlark.String:
+		return starlark.String(hex.EncodeToString([]byte(v))), nil
+	case starlark.Bytes:
+		return starlark.String(hex.EncodeToString([]byte(v))), nil
+	}
+	return nil, fmt.Errorf("%s: got %s, want string or bytes", fn.Name(), data.Type())
+}
+
+// unhex("0a 0b") -> string con esos bytes (espacios ignorados)
+func hexDecode(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var s string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &s); err != nil {
+		return nil, err
+	}
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid hex", fn.Name())
+	}
+	return starlark.String(b), nil
+}
+
+// regex.match(pattern, s) -> bool
+func regexMatch(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, s, err := regexArgs(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.Bool(re.MatchString(s)), nil
+}
+
+// regex.search(pattern, s) -> [match, grupo1, ...] o None
+func regexSearch(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, s, err := regexArgs(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return starlark.None, nil
+	}
+	return stringsValue(m), nil
+}
+
+// regex.findall(pattern, s) -> lista de matches (o del grupo 1 si la regex tiene grupos)
+func regexFindAll(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, s, err := regexArgs(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, m := range re.FindAllStringSubmatch(s, -1) {
+		if len(m) > 1 {
+			out = append(out, m[1])
+		} else {
+			out = append(out, m[0])
+		}
+	}
+	return stringsValue(out), nil
+}
+
+func regexArgs(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (*regexp.Regexp, string, error) {
+	var pattern, s string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "pattern", &pattern, "s", &s); err != nil {
+		return nil, "", err
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return re, s, nil
+}
+
+func stringsValue(list []string) *starlark.List {
+	values := make([]starlark.Value, len(list))
+	for i, s := range list {
+		values[i] = starlark.String(s)
+	}
+	return starlark.NewList(values)
+}
+
+// nombre para el SNI: el hostname del target si se conoce
+func tlsName(t probe.Target) string {
+	if name := t.Hostname(); name != "" {
+		return name
+	}
+	return t.Host
+}
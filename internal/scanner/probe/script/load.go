@@ -0,0 +1,65 @@
+package script
+
+//CARGA -> scripts (*.star) de un directorio del usuario y registro junto a los demas probers
+import (
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// carga los scripts (*.star) del directorio y sus subdirectorios
+// cualquier script invalido aborta la carga indicando el archivo
+func LoadDir(dir string) ([]*Script, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.ToLower(filepath.Ext(path)) == ".star" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scripts directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var scripts []*Script
+	names := make(map[string]string)
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script: %w", err)
+		}
+		s, err := Parse(path, src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		key := string(s.proto) + "/" + strings.ToLower(s.name)
+		if prev, dup := names[key]; dup {
+			return nil, fmt.Errorf("%s: duplicate probe name %q (also in %s)", path, s.name, prev)
+		}
+		names[key] = path
+		scripts = append(scripts, s)
+	}
+	return scripts, nil
+}
+
+// agrega los scripts al registro, despues de los probers ya registrados
+// un nombre no puede reemplazar a un prober existente del mismo protocolo
+func Register(r *probe.Registry, scripts []*Script) error {
+	for _, s := range scripts {
+		spec := s.Spec()
+		if _, exists := r.Get(spec.Protocol, spec.Name); exists {
+			return fmt.Errorf("%s: name %q is already a registered prober", s.File, s.name)
+		}
+		r.Register(s)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package script
+
+//SCRIPTS -> probers en Starlark con logica propia (handshakes de varios pasos, consultas condicionales)
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// grupo que habilita todos los scripts
+const Group = "scripts"
+
+// presupuesto de cada invocacion: pasos del interprete, tiempo total (incluye la red) y conexiones abiertas
+const (
+	MaxSteps    = 1_000_000
+	MaxDuration = 10 * time.Second
+	MaxConns    = 16
+)
+
+// el script no aporto nada sobre el servicio
+var errNoResult = errors.New("no result")
+
+// dialecto: while y set permitidos, sin load() ni recursion
+var fileOptions = &syntax.FileOptions{Set: true, While: true}
+
+// script cargado, se registra como un prober
+type Script struct {
+	File     string
+	name     string
+	proto    model.Protocol
+	services []service.ServiceType
+	ports    []int
+	requires []string
+	tags     []string
+	timeout  time.Duration      //tiempo maximo de la invocacion (<= MaxDuration)
+	run      *starlark.Function //run(target)
+}
+
+// ejecuta el archivo (declaracion con probe(...) y definicion de run) y congela sus globales
+// el modulo solo se evalua una vez, cada invocacion usa su propio thread
+func Parse(filename string, src []byte) (*Script, error) {
+	s := &Script{File: filename, timeout: MaxDuration}
+
+	thread := &starlark.Thread{Name: filename, Print: func(*starlark.Thread, string) {}}
+	thread.SetMaxExecutionSteps(MaxSteps)
+	thread.SetLocal(declKey, s)
+
+	globals, err := starlark.ExecFileOptions(fileOptions, thread, filename, src, predeclared())
+	if err != nil {
+		return nil, err
+	}
+	globals.Freeze()
+
+	if s.name == "" {
+		return nil, fmt.Errorf("missing probe(...) declaration")
+	}
+	run, ok := globals["run"].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("missing run(target) function")
+	}
+	if run.NumParams() != 1 {
+		return nil, fmt.Errorf("run must take exactly one argument (target)")
+	}
+	s.run = run
+	return s, nil
+}
+
+// se habilita por nombre, "scripts" o "tag:<tag>"; "all" no los incluye
+func (s *Script) Spec() probe.Spec {
+	spec := probe.Spec{
+		Name:     s.name,
+		Protocol: s.proto,
+		Services: s.services,
+		Ports:    s.ports,
+		Requires: s.requires,
+		Groups:   []string{Group},
+		Explicit: true,
+	}
+	for _, tag := range s.tags {
+		spec.Groups = append(spec.Groups, "tag:"+tag)
+	}
+	return spec
+}
+
+// llama a run(target) con el presupuesto de pasos y tiempo del script
+// cancelar ctx o agotar el tiempo detiene el interprete y cierra las conexiones abiertas
+func (s *Script) Probe(ctx context.Context, target probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	inv := &invocation{ctx: ctx, script: s, target: target, opts: opts}
+	defer inv.closeAll()
+
+	thread := &starlark.Thread{Name: s.name, Print: func(*starlark.Thread, string) {}}
+	thread.SetMaxExecutionSteps(MaxSteps)
+	thread.SetLocal(invocationKey, inv)
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	if _, err := starlark.Call(thread, s.run, starlark.Tuple{targetValue(target)}, nil); err != nil {
+		return nil, fmt.Errorf("script %s: %w", s.name, err)
+	}
+	if inv.info == nil {
+		return nil, fmt.Errorf("script %s: %w", s.name, errNoResult)
+	}
+	return inv.info, nil
+}
+
+// probe(name, protocol="tcp", services=[], ports=[], requires=[], tags=[], timeout=10)
+func declare(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	s, ok := thread.Local(declKey).(*Script)
+	if !ok {
+		return nil, fmt.Errorf("%s: only allowed at the top level of the script", fn.Name())
+	}
+	if s.name != "" {
+		return nil, fmt.Errorf("%s: declared twice", fn.Name())
+	}
+
+	var name string
+	proto := "tcp"
+	var services, requires, tags *starlark.List
+	var ports *starlark.List
+	var timeout starlark.Value = starlark.Float(MaxDuration.Seconds())
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+		"name", &name, "protocol?", &proto, "services?", &services, "ports?", &ports,
+		"requires?", &requires, "tags?", &tags, "timeout?", &timeout); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t,") {
+		return nil, fmt.Errorf("%s: invalid name %q", fn.Name(), name)
+	}
+	switch model.Protocol(strings.ToLower(proto)) {
+	case model.ProtocolTCP, model.ProtocolUDP:
+		s.proto = model.Protocol(strings.ToLower(proto))
+	default:
+		return nil, fmt.Errorf("%s: unknown protocol %q", fn.Name(), proto)
+	}
+	secs, ok := starlark.AsFloat(timeout)
+	if !ok || secs <= 0 || secs > MaxDuration.Seconds() {
+		return nil, fmt.Errorf("%s: timeout must be between 0 and %v seconds", fn.Name(), MaxDuration.Seconds())
+	}
+	s.timeout = time.Duration(secs * float64(time.Second))
+
+	names, err := stringList(services)
+	if err != nil {
+		return nil, fmt.Errorf("%s: services: %w", fn.Name(), err)
+	}
+	for _, n := range names {
+		s.services = append(s.services, service.TypeFromName(n))
+	}
+	if s.requires, err = stringList(requires); err != nil {
+		return nil, fmt.Errorf("%s: requires: %w", fn.Name(), err)
+	}
+	if s.tags, err = stringList(tags); err != nil {
+		return nil, fmt.Errorf("%s: tags: %w", fn.Name(), err)
+	}
+	for i := range s.tags {
+		s.tags[i] = strings.ToLower(s.tags[i])
+	}
+	if ports != nil {
+		for i := 0; i < ports.Len(); i++ {
+			p, err := starlark.AsInt32(ports.Index(i))
+			if err != nil || p < 1 || p > 65535 {
+				return nil, fmt.Errorf("%s: invalid port %s", fn.Name(), ports.Index(i))
+			}
+			s.ports = append(s.ports, p)
+		}
+	}
+	s.name = name
+	return starlark.None, nil
+}
+
+// lista de strings de starlark (nil = vacia)
+func stringList(l *starlark.List) ([]string, error) {
+	if l == nil {
+		return nil, nil
+	}
+	var out []string
+	for i := 0; i < l.Len(); i++ {
+		s, ok := starlark.AsString(l.Index(i))
+		if !ok {
+			return nil, fmt.Errorf("got %s, want string", l.Index(i).Type())
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package script
+
+import (
+	"context"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// servidor TCP que cuenta las conexiones y cuantas cerro el cliente
+func countingServer(t *testing.T) (probe.Target, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var accepted, closed atomic.Int32
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func() {
+				defer closed.Add(1)
+				defer c.Close()
+				c.SetDeadline(time.Now().Add(5 * time.Second))
+				io.Copy(io.Discard, c) //hasta que el script cierre
+			}()
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	return probe.Target{Host: "127.0.0.1", Port: port, Protocol: "tcp", Service: &service.ServiceInfo{}}, &accepted, &closed
+}
+
+func TestConnectionLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		conns int32
+		err   string
+	}{
+		{
+			name: "loop over the limit",
+			src: `
+probe(name = "flood")
+
+def run(target):
+    n = 0
+    while True:
+        connect(timeout = 1)
+        n += 1
+`,
+			conns: MaxConns,
+			err:   "connection limit reached",
+		},
+		{
+			name: "closed connections still count",
+			src: `
+probe(name = "flood-close")
+
+def run(target):
+    for i in range(100):
+        connect(timeout = 1).close()
+`,
+			conns: MaxConns,
+			err:   "connection limit reached",
+		},
+		{
+			name: "within the limit, left open",
+			src: `
+probe(name = "few")
+
+def run(target):
+    for i in range(3):
+        connect(timeout = 1).send("x")
+    finding(name = "three connections")
+`,
+			conns: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		target, accepted, closed := countingServer(t)
+		s, err := Parse(tt.name+".star", []byte(tt.src))
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tt.name, err)
+		}
+
+		_, err = s.Probe(context.Background(), target, probe.Options{})
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: Probe error = %v, want %q", tt.name, err, tt.err)
+		}
+
+		//todas las conexiones quedan cerradas al terminar run()
+		deadline := time.Now().Add(3 * time.Second)
+		for (accepted.Load() < tt.conns || closed.Load() < accepted.Load()) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if open := accepted.Load() - closed.Load(); open != 0 {
+			t.Errorf("%s: %d connections still open after run()", tt.name, open)
+		}
+		if got := accepted.Load(); got != tt.conns {
+			t.Errorf("%s: %d connections, want %d", tt.name, got, tt.conns)
+		}
+	}
+}
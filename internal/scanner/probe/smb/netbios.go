@@ -0,0 +1,167 @@
+package smb
+
+//NETBIOS-NS -> consulta NBSTAT (node status) por UDP 137
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"strings"
+)
+
+// tipo de registro NBSTAT, clase IN
+const (
+	nbstatType = 0x0021
+	classIN    = 0x0001
+)
+
+type NetBIOSProbe struct{}
+
+func NewNetBIOSProbe() *NetBIOSProbe {
+	return &NetBIOSProbe{}
+}
+
+func (p *NetBIOSProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     "netbios-ns",
+		Protocol: model.ProtocolUDP,
+		Services: []service.ServiceType{service.ServiceNetBIOS},
+		Ports:    []int{137},
+		Groups:   []string{"smb"},
+	}
+}
+
+func (p *NetBIOSProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	conn, err := opts.Dial(ctx, "udp", t.Address())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	id := make([]byte, 2)
+	rand.Read(id)
+	if _, err := conn.Write(nodeStatusQuery(id)); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 2048)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 || buf[0] != id[0] || buf[1] != id[1] {
+		return nil, errors.New("unexpected netbios response id")
+	}
+	details, err := parseNodeStatus(buf[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	info := &service.ServiceInfo{
+		Type:       service.ServiceNetBIOS,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		NetBIOS:    details,
+	}
+	for _, name := range details.Names {
+		//nombre unico del equipo (sufijo 0x00 workstation)
+		if !name.Group && name.Suffix == 0x00 {
+			info.Hostname = name.Name
+			break
+		}
+	}
+	if details.Workgroup != "" {
+		info.ExtraInfo = "workgroup: " + details.Workgroup
+	}
+	return info, nil
+}
+
+// pregunta NBSTAT por el nombre comodin "*"
+func nodeStatusQuery(id []byte) []byte {
+	packet := append([]byte{}, id...)
+	packet = binary.BigEndian.AppendUint16(packet, 0x0000) //flags: query
+	packet = binary.BigEndian.AppendUint16(packet, 1)      //QDCOUNT
+	packet = append(packet, make([]byte, 6)...)            //AN, NS y AR en 0
+	packet = append(packet, encodeName("*", 0x00)...)
+	packet = binary.BigEndian.AppendUint16(packet, nbstatType)
+	return binary.BigEndian.AppendUint16(packet, classIN)
+}
+
+// respuesta: cabecera, nombre, tipo/clase/TTL/largo y la tabla de nombres + MAC
+func parseNodeStatus(buf []byte) (*service.NetBIOSInfo, error) {
+	if len(buf) < 12 || binary.BigEndian.Uint16(buf[6:8]) == 0 {
+		return nil, errors.New("netbios response without answers")
+	}
+	pos := 12
+	pos, err := skipName(buf, pos)
+	if err != nil {
+		return nil, err
+	}
+	if pos+10 > len(buf) || binary.BigEndian.Uint16(buf[pos:pos+2]) != nbstatType {
+		return nil, errors.New("not a node status response")
+	}
+	pos += 10 //tipo, clase, TTL y RDLENGTH
+	if pos >= len(buf) {
+		return nil, errors.New("truncated node status response")
+	}
+
+	count := int(buf[pos])
+	pos++
+	details := &service.NetBIOSInfo{}
+	for i := 0; i < count; i++ {
+		if pos+18 > len(buf) {
+			return nil, errors.New("truncated netbios name table")
+		}
+		entry := buf[pos : pos+18]
+		pos += 18
+
+		name := service.NetBIOSName{
+			Name:   strings.TrimRight(string(entry[0:15]), " \x00"),
+			Suffix: entry[15],
+			Group:  binary.BigEndian.Uint16(entry[16:18])&0x8000 != 0,
+		}
+		details.Names = append(details.Names, name)
+		if name.Group && name.Suffix == 0x00 && details.Workgroup == "" {
+			details.Workgroup = name.Name
+		}
+	}
+	if pos+6 <= len(buf) {
+		details.MAC = net.HardwareAddr(buf[pos : pos+6]).String()
+	}
+	return details, nil
+}
+
+// salta un nombre codificado (etiquetas con largo o puntero de compresion)
+func skipName(buf []byte, pos int) (int, error) {
+	for pos < len(buf) {
+		length := int(buf[pos])
+		switch {
+		case length == 0:
+			return pos + 1, nil
+		case length&0xc0 == 0xc0:
+			return pos + 2, nil
+		}
+		pos += 1 + length
+	}
+	return 0, errors.New("truncated netbios name")
+}
+
+// codificacion de primer nivel: 16 bytes (nombre + sufijo) en 32 letras 'A'..'P'
+func encodeName(name string, suffix byte) []byte {
+	raw := []byte(fmt.Sprintf("%-15.15s", strings.ToUpper(name)))
+	if name == "*" {
+		raw = append([]byte{'*'}, make([]byte, 14)...) //el comodin se rellena con ceros
+	}
+	raw = append(raw, suffix)
+
+	out := []byte{32}
+	for _, b := range raw {
+		out = append(out, 'A'+b>>4, 'A'+b&0x0f)
+	}
+	return append(out, 0)
+}
@@ -0,0 +1,172 @@
+package smb
+
+//PROBER SMB -> NEGOTIATE SMB1/SMB2, firma, GUID y challenge NTLMSSP
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/ntlm"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+)
+
+// puerto de SMB sobre NetBIOS (requiere session request previo)
+const netbiosSessionPort = 139
+
+type SMBProbe struct{}
+
+func NewSMBProbe() *SMBProbe {
+	return &SMBProbe{}
+}
+
+func (p *SMBProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     "smb",
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{service.ServiceSMB},
+		Ports:    []int{139, 445},
+		Groups:   []string{"smb"},
+	}
+}
+
+// un NEGOTIATE por dialecto (asi se listan todos), SMB1 aparte y SESSION_SETUP anonimo para NTLM
+func (p *SMBProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	details := &service.SMBInfo{}
+
+	//negociacion principal: dialectos hasta 3.0.2, firma, GUID y challenge NTLM
+	err := withConn(ctx, t, opts, func(c *conn) error {
+		resp, err := c.negotiate(baseDialects)
+		if err != nil {
+			return err
+		}
+		details.SigningEnabled = resp.securityMode&0x01 != 0
+		details.SigningRequired = resp.securityMode&0x02 != 0
+		details.ServerGUID = formatGUID(resp.guid)
+
+		if challenge, err := c.sessionSetup(ntlm.Negotiate()); err == nil {
+			details.NTLM, _ = ntlm.ParseChallenge(challenge)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range allDialects {
+		accepted := false
+		withConn(ctx, t, opts, func(c *conn) error {
+			resp, err := c.negotiate([]uint16{d})
+			accepted = err == nil && resp.dialect == d
+			return nil
+		})
+		if accepted {
+			details.Dialects = append(details.Dialects, dialectName(d))
+		}
+	}
+
+	withConn(ctx, t, opts, func(c *conn) error {
+		details.SMB1 = c.negotiateSMB1()
+		return nil
+	})
+
+	info := &service.ServiceInfo{
+		Type:       service.ServiceSMB,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		SMB:        details,
+	}
+	if n := details.NTLM; n != nil {
+		info.Hostname = n.DNSComputer
+		if info.Hostname == "" {
+			info.Hostname = n.NetBIOSComputer
+		}
+		if n.OSVersion != "" {
+			info.OSHint = "Windows"
+			info.ExtraInfo = n.OSVersion
+			if release := ntlm.WindowsRelease(n.OSVersion); release != "" {
+				info.ExtraInfo = release + " " + n.OSVersion
+			}
+		}
+	}
+	return info, nil
+}
+
+// conexion SMB: cada mensaje va con la cabecera de sesion NetBIOS (4 bytes)
+type conn struct {
+	net.Conn
+	messageID uint64
+}
+
+func withConn(ctx context.Context, t probe.Target, opts probe.Options, fn func(c *conn) error) error {
+	raw, err := opts.Dial(ctx, "tcp", t.Address())
+	if err != nil {
+		return err
+	}
+	defer raw.Close()
+
+	c := &conn{Conn: raw}
+	if t.Port == netbiosSessionPort {
+		if err := c.sessionRequest(); err != nil {
+			return err
+		}
+	}
+	return fn(c)
+}
+
+func (c *conn) send(msg []byte) error {
+	frame := binary.BigEndian.AppendUint32(nil, uint32(len(msg))) //tipo 0x00 + largo de 24 bits
+	_, err := c.Write(append(frame, msg...))
+	return err
+}
+
+func (c *conn) receive() ([]byte, error) {
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(c, head); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(head) & 0x00ffffff
+	if head[0] != 0 || length > 1024*1024 {
+		return nil, fmt.Errorf("unexpected netbios frame type 0x%02x", head[0])
+	}
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(c, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// NetBIOS session request al nombre comodin *SMBSERVER (puerto 139)
+func (c *conn) sessionRequest() error {
+	body := append(encodeName("*SMBSERVER", 0x20), encodeName("GO-SCANNER", 0x00)...)
+	packet := []byte{0x81, 0x00}
+	packet = binary.BigEndian.AppendUint16(packet, uint16(len(body)))
+	if _, err := c.Write(append(packet, body...)); err != nil {
+		return err
+	}
+
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(c, head); err != nil {
+		return err
+	}
+	if length := binary.BigEndian.Uint16(head[2:4]); length > 0 {
+		io.CopyN(io.Discard, c, int64(length))
+	}
+	if head[0] != 0x82 {
+		return errors.New("netbios session request rejected")
+	}
+	return nil
+}
+
+// GUID en formato texto (los 3 primeros campos son little endian)
+func formatGUID(b []byte) string {
+	if len(b) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(b[0:4]), binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]), b[8:10], b[10:16])
+}
@@ -0,0 +1,39 @@
+package smb
+
+//SMB1 -> NEGOTIATE ofreciendo solo "NT LM 0.12"
+import (
+	"encoding/binary"
+)
+
+// comando SMB_COM_NEGOTIATE
+const smb1Negotiate = 0x72
+
+// true si el servidor acepta el dialecto SMB1
+func (c *conn) negotiateSMB1() bool {
+	h := []byte{0xff, 'S', 'M', 'B', smb1Negotiate}
+	h = binary.LittleEndian.AppendUint32(h, 0) //Status
+	h = append(h, 0x18)                        //Flags: nombres sin distinguir mayusculas
+	h = binary.LittleEndian.AppendUint16(h, 0xc853)
+	h = append(h, make([]byte, 12)...)         //PIDHigh, SecurityFeatures, Reserved
+	h = binary.LittleEndian.AppendUint16(h, 0) //TID
+	h = binary.LittleEndian.AppendUint16(h, 0xfeff)
+	h = binary.LittleEndian.AppendUint16(h, 0) //UID
+	h = binary.LittleEndian.AppendUint16(h, 0) //MID
+
+	dialects := append([]byte{0x02}, "NT LM 0.12\x00"...)
+	body := []byte{0} //WordCount
+	body = binary.LittleEndian.AppendUint16(body, uint16(len(dialects)))
+	body = append(body, dialects...)
+
+	if err := c.send(append(h, body...)); err != nil {
+		return false
+	}
+	msg, err := c.receive()
+	if err != nil || len(msg) < 35 || string(msg[0:4]) != "\xffSMB" {
+		return false
+	}
+	if binary.LittleEndian.Uint32(msg[5:9]) != 0 || msg[32] == 0 {
+		return false
+	}
+	return binary.LittleEndian.Uint16(msg[33:35]) != 0xffff
+}
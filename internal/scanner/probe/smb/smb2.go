@@ -0,0 +1,203 @@
+package smb
+
+//SMB2/3 -> NEGOTIATE y SESSION_SETUP
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// comandos SMB2
+const (
+	cmdNegotiate    = 0x0000
+	cmdSessionSetup = 0x0001
+)
+
+// estados NT relevantes
+const (
+	statusSuccess                = 0x00000000
+	statusMoreProcessingRequired = 0xc0000016
+)
+
+// dialectos SMB2/3
+const (
+	dialect202 = 0x0202
+	dialect210 = 0x0210
+	dialect300 = 0x0300
+	dialect302 = 0x0302
+	dialect311 = 0x0311
+)
+
+// 3.1.1 exige contextos de negociacion, la negociacion principal usa los anteriores
+var (
+	baseDialects = []uint16{dialect202, dialect210, dialect300, dialect302}
+	allDialects  = []uint16{dialect202, dialect210, dialect300, dialect302, dialect311}
+)
+
+func dialectName(d uint16) string {
+	switch d {
+	case dialect202:
+		return "2.0.2"
+	case dialect210:
+		return "2.1"
+	case dialect300:
+		return "3.0"
+	case dialect302:
+		return "3.0.2"
+	case dialect311:
+		return "3.1.1"
+	}
+	return fmt.Sprintf("0x%04x", d)
+}
+
+// cabecera SMB2 de 64 bytes
+func (c *conn) header(command uint16) []byte {
+	h := []byte{0xfe, 'S', 'M', 'B'}
+	h = binary.LittleEndian.AppendUint16(h, 64) //StructureSize
+	h = binary.LittleEndian.AppendUint16(h, 0)  //CreditCharge
+	h = binary.LittleEndian.AppendUint32(h, 0)  //Status
+	h = binary.LittleEndian.AppendUint16(h, command)
+	h = binary.LittleEndian.AppendUint16(h, 1) //CreditRequest
+	h = binary.LittleEndian.AppendUint32(h, 0) //Flags
+	h = binary.LittleEndian.AppendUint32(h, 0) //NextCommand
+	h = binary.LittleEndian.AppendUint64(h, c.messageID)
+	c.messageID++
+	return append(h, make([]byte, 4+4+8+16)...) //ProcessId, TreeId, SessionId, Signature
+}
+
+// envia un comando y retorna el estado y el cuerpo de la respuesta
+func (c *conn) call(command uint16, body []byte) (uint32, []byte, error) {
+	if err := c.send(append(c.header(command), body...)); err != nil {
+		return 0, nil, err
+	}
+
+	for {
+		msg, err := c.receive()
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(msg) < 64 || string(msg[0:4]) != "\xfeSMB" {
+			return 0, nil, errors.New("not an smb2 response")
+		}
+		status := binary.LittleEndian.Uint32(msg[8:12])
+		if status == 0x00000103 { //STATUS_PENDING, llega otra respuesta
+			continue
+		}
+		return status, msg, nil
+	}
+}
+
+// respuesta de NEGOTIATE
+type negotiateResponse struct {
+	securityMode uint16
+	dialect      uint16
+	guid         []byte
+}
+
+func (c *conn) negotiate(dialects []uint16) (*negotiateResponse, error) {
+	clientGUID := make([]byte, 16)
+	rand.Read(clientGUID)
+
+	body := binary.LittleEndian.AppendUint16(nil, 36) //StructureSize
+	body = binary.LittleEndian.AppendUint16(body, uint16(len(dialects)))
+	body = binary.LittleEndian.AppendUint16(body, 0x01) //SecurityMode: firma habilitada
+	body = binary.LittleEndian.AppendUint16(body, 0)
+	body = binary.LittleEndian.AppendUint32(body, 0x7f) //Capabilities
+	body = append(body, clientGUID...)
+
+	with311 := false
+	for _, d := range dialects {
+		with311 = with311 || d == dialect311
+	}
+
+	//con 3.1.1 los 8 bytes siguientes son offset/cantidad de contextos, si no ClientStartTime
+	contextsAt := len(body) + 8
+	body = append(body, make([]byte, 8)...)
+	for _, d := range dialects {
+		body = binary.LittleEndian.AppendUint16(body, d)
+	}
+	if with311 {
+		for (64+len(body))%8 != 0 {
+			body = append(body, 0)
+		}
+		binary.LittleEndian.PutUint32(body[contextsAt-8:], uint32(64+len(body)))
+		binary.LittleEndian.PutUint16(body[contextsAt-4:], 2)
+		body = append(body, negotiateContexts()...)
+	}
+
+	status, msg, err := c.call(cmdNegotiate, body)
+	if err != nil {
+		return nil, err
+	}
+	if status != statusSuccess {
+		return nil, fmt.Errorf("smb2 negotiate failed: 0x%08x", status)
+	}
+	r := msg[64:]
+	if len(r) < 64 {
+		return nil, errors.New("short smb2 negotiate response")
+	}
+
+	return &negotiateResponse{
+		securityMode: binary.LittleEndian.Uint16(r[2:4]),
+		dialect:      binary.LittleEndian.Uint16(r[4:6]),
+		guid:         r[8:24],
+	}, nil
+}
+
+// PREAUTH_INTEGRITY (SHA-512) y ENCRYPTION (AES-128-GCM/CCM), alineados a 8 bytes
+func negotiateContexts() []byte {
+	salt := make([]byte, 32)
+	rand.Read(salt)
+
+	preauth := []byte{1, 0, 32, 0, 0x01, 0x00} //1 hash, salt de 32, SHA-512
+	preauth = append(preauth, salt...)
+	encryption := []byte{2, 0, 0x02, 0x00, 0x01, 0x00} //AES-128-GCM, AES-128-CCM
+
+	var out []byte
+	for i, ctx := range []struct {
+		kind uint16
+		data []byte
+	}{{1, preauth}, {2, encryption}} {
+		if i > 0 {
+			for len(out)%8 != 0 {
+				out = append(out, 0)
+			}
+		}
+		out = binary.LittleEndian.AppendUint16(out, ctx.kind)
+		out = binary.LittleEndian.AppendUint16(out, uint16(len(ctx.data)))
+		out = append(out, 0, 0, 0, 0)
+		out = append(out, ctx.data...)
+	}
+	return out
+}
+
+// SESSION_SETUP con un token de seguridad, retorna el token del servidor (challenge)
+func (c *conn) sessionSetup(token []byte) ([]byte, error) {
+	body := binary.LittleEndian.AppendUint16(nil, 25) //StructureSize
+	body = append(body, 0, 0x01)                      //Flags, SecurityMode
+	body = binary.LittleEndian.AppendUint32(body, 0)  //Capabilities
+	body = binary.LittleEndian.AppendUint32(body, 0)  //Channel
+	body = binary.LittleEndian.AppendUint16(body, 64+24)
+	body = binary.LittleEndian.AppendUint16(body, uint16(len(token)))
+	body = binary.LittleEndian.AppendUint64(body, 0) //PreviousSessionId
+	body = append(body, token...)
+
+	status, msg, err := c.call(cmdSessionSetup, body)
+	if err != nil {
+		return nil, err
+	}
+	if status != statusMoreProcessingRequired {
+		return nil, fmt.Errorf("unexpected session setup status 0x%08x", status)
+	}
+	r := msg[64:]
+	if len(r) < 8 {
+		return nil, errors.New("short session setup response")
+	}
+	offset := int(binary.LittleEndian.Uint16(r[4:6]))
+	length := int(binary.LittleEndian.Uint16(r[6:8]))
+	if offset+length > len(msg) {
+		return nil, errors.New("truncated session setup token")
+	}
+	return msg[offset : offset+length], nil
+}
@@ -0,0 +1,298 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// servidor en memoria: por cada mensaje recibido responde los frames que retorne reply
+func fakeServer(t *testing.T, reply func(req []byte) [][]byte) *conn {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	go func() {
+		defer server.Close()
+		s := &conn{Conn: server}
+		for {
+			req, err := s.receive()
+			if err != nil {
+				return
+			}
+			for _, frame := range reply(req) {
+				if _, err := server.Write(frame); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return &conn{Conn: client}
+}
+
+// frame de sesion NetBIOS con el mensaje
+func frame(msg []byte) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(msg))), msg...)
+}
+
+// cabecera SMB2 de respuesta con el estado dado
+func responseHeader(command uint16, status uint32) []byte {
+	h := []byte{0xfe, 'S', 'M', 'B'}
+	h = binary.LittleEndian.AppendUint16(h, 64)
+	h = binary.LittleEndian.AppendUint16(h, 0)
+	h = binary.LittleEndian.AppendUint32(h, status)
+	h = binary.LittleEndian.AppendUint16(h, command)
+	return append(h, make([]byte, 64-len(h))...)
+}
+
+// cuerpo de NEGOTIATE: modo de seguridad, dialecto y GUID del servidor
+func negotiateBody(securityMode, dialect uint16, guid []byte) []byte {
+	body := binary.LittleEndian.AppendUint16(nil, 65)
+	body = binary.LittleEndian.AppendUint16(body, securityMode)
+	body = binary.LittleEndian.AppendUint16(body, dialect)
+	body = append(body, 0, 0)
+	body = append(body, guid...)
+	return append(body, make([]byte, 64-len(body))...)
+}
+
+// cuerpo de SESSION_SETUP con el token a partir del offset dado (relativo a la cabecera)
+func sessionSetupBody(offset int, token []byte) []byte {
+	body := binary.LittleEndian.AppendUint16(nil, 9)
+	body = binary.LittleEndian.AppendUint16(body, 0)
+	body = binary.LittleEndian.AppendUint16(body, uint16(offset))
+	body = binary.LittleEndian.AppendUint16(body, uint16(len(token)))
+	return append(body, token...)
+}
+
+var serverGUID = []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+
+func TestNegotiate(t *testing.T) {
+	var request []byte
+	c := fakeServer(t, func(req []byte) [][]byte {
+		request = req
+		return [][]byte{
+			frame(responseHeader(cmdNegotiate, 0x00000103)), //STATUS_PENDING antes de la respuesta
+			frame(append(responseHeader(cmdNegotiate, statusSuccess), negotiateBody(0x03, dialect311, serverGUID)...)),
+		}
+	})
+
+	resp, err := c.negotiate(allDialects)
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if resp.dialect != dialect311 || resp.securityMode != 0x03 || !bytes.Equal(resp.guid, serverGUID) {
+		t.Errorf("negotiate = %+v", resp)
+	}
+	if got := formatGUID(resp.guid); got != "00112233-4455-6677-8899-aabbccddeeff" {
+		t.Errorf("formatGUID = %q", got)
+	}
+
+	//pedido: cabecera, cantidad de dialectos y contextos de 3.1.1 alineados a 8
+	body := request[64:]
+	if string(request[:4]) != "\xfeSMB" || binary.LittleEndian.Uint16(request[12:14]) != cmdNegotiate {
+		t.Fatalf("request header = % x", request[:16])
+	}
+	if n := binary.LittleEndian.Uint16(body[2:4]); n != uint16(len(allDialects)) {
+		t.Errorf("dialect count = %d", n)
+	}
+	offset := binary.LittleEndian.Uint32(body[28:32])
+	if offset%8 != 0 || int(offset) >= len(request) || binary.LittleEndian.Uint16(body[32:34]) != 2 {
+		t.Errorf("negotiate contexts at %d (count %d), request %d bytes", offset, binary.LittleEndian.Uint16(body[32:34]), len(request))
+	} else if kind := binary.LittleEndian.Uint16(request[offset:]); kind != 1 {
+		t.Errorf("first negotiate context = %d, want preauth integrity", kind)
+	}
+}
+
+func TestNegotiateErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []byte
+	}{
+		{"failure status", frame(append(responseHeader(cmdNegotiate, 0xc0000022), negotiateBody(0, dialect210, serverGUID)...))},
+		{"short body", frame(append(responseHeader(cmdNegotiate, statusSuccess), 65, 0, 1, 0))},
+		{"not smb2", frame(append([]byte{0xff, 'S', 'M', 'B'}, make([]byte, 80)...))},
+		{"short header", frame([]byte{0xfe, 'S', 'M', 'B'})},
+		{"not a session frame", append([]byte{0x85, 0, 0, 4}, 0xfe, 'S', 'M', 'B')},
+		{"oversized frame", []byte{0x00, 0xff, 0xff, 0xff}},
+	}
+
+	for _, tt := range tests {
+		c := fakeServer(t, func([]byte) [][]byte { return [][]byte{tt.reply} })
+		if resp, err := c.negotiate(baseDialects); err == nil {
+			t.Errorf("%s: negotiate = %+v, want error", tt.name, resp)
+		}
+	}
+}
+
+func TestSessionSetup(t *testing.T) {
+	token := []byte("NTLMSSP\x00\x02\x00\x00\x00challenge")
+
+	tests := []struct {
+		name   string
+		status uint32
+		offset int
+		want   []byte
+		ok     bool
+	}{
+		{"challenge", statusMoreProcessingRequired, 64 + 8, token, true},
+		{"success without challenge", statusSuccess, 64 + 8, nil, false},
+		{"token beyond message", statusMoreProcessingRequired, 64 + 40, nil, false},
+	}
+
+	for _, tt := range tests {
+		c := fakeServer(t, func(req []byte) [][]byte {
+			return [][]byte{frame(append(responseHeader(cmdSessionSetup, tt.status), sessionSetupBody(tt.offset, token)...))}
+		})
+		got, err := c.sessionSetup([]byte("NTLMSSP\x00\x01"))
+		if (err == nil) != tt.ok || !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: sessionSetup = %q, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestNegotiateSMB1(t *testing.T) {
+	smb1Response := func(status uint32, wordCount byte, dialectIndex uint16) []byte {
+		msg := []byte{0xff, 'S', 'M', 'B', smb1Negotiate}
+		msg = binary.LittleEndian.AppendUint32(msg, status)
+		msg = append(msg, make([]byte, 32-len(msg))...)
+		msg = append(msg, wordCount)
+		return frame(binary.LittleEndian.AppendUint16(msg, dialectIndex))
+	}
+
+	tests := []struct {
+		name  string
+		reply []byte
+		want  bool
+	}{
+		{"accepted", smb1Response(0, 17, 0), true},
+		{"no dialect", smb1Response(0, 1, 0xffff), false},
+		{"error status", smb1Response(0xc0000002, 17, 0), false},
+		{"no words", smb1Response(0, 0, 0), false},
+		{"smb2 only", frame(append(responseHeader(cmdNegotiate, statusSuccess), negotiateBody(0, dialect202, serverGUID)...)), false},
+	}
+
+	for _, tt := range tests {
+		var request []byte
+		c := fakeServer(t, func(req []byte) [][]byte {
+			request = req
+			return [][]byte{tt.reply}
+		})
+		if got := c.negotiateSMB1(); got != tt.want {
+			t.Errorf("%s: negotiateSMB1 = %v, want %v", tt.name, got, tt.want)
+		}
+		if !bytes.Contains(request, []byte("\x02NT LM 0.12\x00")) {
+			t.Errorf("%s: request without NT LM 0.12: % x", tt.name, request)
+		}
+	}
+}
+
+func TestEncodeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix byte
+		want   string
+	}{
+		{"*SMBSERVER", 0x20, "CKFDENECFDEFFCFGEFFCCACACACACACA"},
+		{"*", 0x00, "CKAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"},
+		{"go-scanner", 0x00, "EHEPCNFDEDEBEOEOEFFCCACACACACAAA"},
+	}
+
+	for _, tt := range tests {
+		got := encodeName(tt.name, tt.suffix)
+		want := append(append([]byte{32}, tt.want...), 0)
+		if !bytes.Equal(got, want) {
+			t.Errorf("encodeName(%q, %#x) = %q, want %q", tt.name, tt.suffix, got[1:33], tt.want)
+		}
+	}
+}
+
+// respuesta NBSTAT con la tabla de nombres y la MAC
+func nodeStatus(names []string, suffixes []byte, flags []uint16, mac []byte) []byte {
+	buf := []byte{0x12, 0x34, 0x84, 0x00, 0, 0, 0, 1, 0, 0, 0, 0}
+	buf = append(buf, encodeName("*", 0)...)
+	buf = binary.BigEndian.AppendUint16(buf, nbstatType)
+	buf = binary.BigEndian.AppendUint16(buf, classIN)
+	buf = append(buf, 0, 0, 0, 0)
+
+	var data []byte
+	data = append(data, byte(len(names)))
+	for i, n := range names {
+		data = append(data, []byte(n+strings.Repeat(" ", 15-len(n)))...)
+		data = append(data, suffixes[i])
+		data = binary.BigEndian.AppendUint16(data, flags[i])
+	}
+	data = append(data, mac...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(data)))
+	return append(buf, data...)
+}
+
+func TestParseNodeStatus(t *testing.T) {
+	mac := []byte{0x00, 0x0c, 0x29, 0xab, 0xcd, 0xef}
+	valid := nodeStatus(
+		[]string{"FILESRV", "CORP", "FILESRV"},
+		[]byte{0x00, 0x00, 0x20},
+		[]uint16{0x0400, 0x8400, 0x0400},
+		mac,
+	)
+
+	info, err := parseNodeStatus(valid)
+	if err != nil {
+		t.Fatalf("parseNodeStatus: %v", err)
+	}
+	if len(info.Names) != 3 || info.Names[0].Name != "FILESRV" || info.Names[2].Suffix != 0x20 || !info.Names[1].Group {
+		t.Errorf("names = %+v", info.Names)
+	}
+	if info.Workgroup != "CORP" || info.MAC != "00:0c:29:ab:cd:ef" {
+		t.Errorf("workgroup %q, mac %q", info.Workgroup, info.MAC)
+	}
+
+	//sin MAC al final
+	if info, err := parseNodeStatus(valid[:len(valid)-6]); err != nil || info.MAC != "" || len(info.Names) != 3 {
+		t.Errorf("without mac = %+v, %v", info, err)
+	}
+
+	noAnswers := append([]byte{}, valid...)
+	noAnswers[7] = 0
+	wrongType := append([]byte{}, valid...)
+	binary.BigEndian.PutUint16(wrongType[12+34:], 0x0001)
+
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{"empty", nil},
+		{"short header", valid[:8]},
+		{"no answers", noAnswers},
+		{"truncated name", valid[:20]},
+		{"not nbstat", wrongType},
+		{"no name count", valid[:12+34+10]},
+		{"truncated name table", valid[:12+34+10+1+18+5]},
+	}
+
+	for _, tt := range tests {
+		if info, err := parseNodeStatus(tt.buf); err == nil {
+			t.Errorf("%s: parseNodeStatus = %+v, want error", tt.name, info)
+		}
+	}
+}
+
+func TestReceiveShortRead(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	//frame que declara 16 bytes y se corta al primero
+	go func() {
+		server.Write([]byte{0x00, 0x00, 0x00, 0x10, 0xfe})
+		server.Close()
+	}()
+
+	c := &conn{Conn: client}
+	if msg, err := c.receive(); err == nil {
+		t.Errorf("receive = % x, want error", msg)
+	}
+}
@@ -0,0 +1,261 @@
+package snmp
+
+//PROBER SNMP -> GET del grupo system con v1/v2c y descubrimiento del engine SNMPv3
+import (
+	"context"
+	"errors"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/ber"
+	"go-scanner/internal/scanner/service"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// comunidad probada si la policy no define otras
+var DefaultCommunities = []string{"public"}
+
+// versiones del campo version del mensaje
+const (
+	versionV1  = 0
+	versionV2c = 1
+	versionV3  = 3
+)
+
+// tags de PDU
+const (
+	pduGetRequest = 0xa0
+	pduResponse   = 0xa2
+	pduReport     = 0xa8
+)
+
+// grupo system de MIB-II
+const (
+	oidSysDescr    = "1.3.6.1.2.1.1.1.0"
+	oidSysObjectID = "1.3.6.1.2.1.1.2.0"
+	oidSysUpTime   = "1.3.6.1.2.1.1.3.0"
+	oidSysContact  = "1.3.6.1.2.1.1.4.0"
+	oidSysName     = "1.3.6.1.2.1.1.5.0"
+)
+
+var systemOIDs = []string{oidSysDescr, oidSysObjectID, oidSysUpTime, oidSysContact, oidSysName}
+
+type SNMPProbe struct{}
+
+func NewSNMPProbe() *SNMPProbe {
+	return &SNMPProbe{}
+}
+
+func (p *SNMPProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     "snmp",
+		Protocol: model.ProtocolUDP,
+		Services: []service.ServiceType{service.ServiceSNMP},
+		Ports:    []int{161},
+	}
+}
+
+// solo se envian GET (nunca SET); una comunidad equivocada no recibe respuesta
+// comunidades de la policy, o DefaultCommunities
+func (p *SNMPProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	communities := opts.SNMPCommunities
+	if len(communities) == 0 {
+		communities = DefaultCommunities
+	}
+	address := t.Address()
+	details := &service.SNMPInfo{}
+
+	//ambas consultas esperan el timeout si no hay respuesta, se hacen en paralelo
+	v3 := &service.SNMPInfo{}
+	var engineErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		engineErr = discoverEngine(ctx, opts, address, v3)
+	}()
+	systemErr := readSystem(ctx, opts, address, communities, details)
+	wg.Wait()
+
+	details.EngineID, details.EngineVendor = v3.EngineID, v3.EngineVendor
+	details.EngineBoots, details.EngineTime = v3.EngineBoots, v3.EngineTime
+	if engineErr != nil && systemErr != nil {
+		return nil, errors.New("no snmp response")
+	}
+
+	info := &service.ServiceInfo{
+		Type:       service.ServiceSNMP,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		SNMP:       details,
+		Hostname:   details.SysName,
+	}
+	describe(info)
+	return info, nil
+}
+
+// un GET por comunidad y version en el mismo socket; gana la primera respuesta valida
+func readSystem(ctx context.Context, opts probe.Options, address string, communities []string, details *service.SNMPInfo) error {
+	conn, err := opts.Dial(ctx, "udp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	type attempt struct {
+		community string
+		version   string
+	}
+	sent := make(map[int64]attempt)
+	for _, c := range communities {
+		for _, v := range []int64{versionV2c, versionV1} {
+			id := rand.Int63n(1 << 30)
+			msg, err := getRequest(v, c, id)
+			if err != nil {
+				return err
+			}
+			if _, err := conn.Write(msg); err != nil {
+				return err
+			}
+			name := "v2c"
+			if v == versionV1 {
+				name = "v1"
+			}
+			sent[id] = attempt{c, name}
+		}
+	}
+
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return err
+		}
+		id, values, err := parseResponse(buf[:n])
+		if err != nil {
+			continue
+		}
+		a, ok := sent[id]
+		if !ok {
+			continue
+		}
+		details.Community, details.Version = a.community, a.version
+		details.SysDescr = values[oidSysDescr].text()
+		details.SysObjectID = values[oidSysObjectID].oid()
+		details.SysUpTime = values[oidSysUpTime].number()
+		details.SysContact = values[oidSysContact].text()
+		details.SysName = values[oidSysName].text()
+		return nil
+	}
+}
+
+// GetRequest con los OIDs del grupo system
+func getRequest(version int64, community string, id int64) ([]byte, error) {
+	var binds [][]byte
+	for _, o := range systemOIDs {
+		oid, err := ber.OID(o)
+		if err != nil {
+			return nil, err
+		}
+		binds = append(binds, ber.Sequence(oid, ber.Null()))
+	}
+	pdu := ber.Constructed(pduGetRequest,
+		ber.Integer(id),
+		ber.Integer(0), //error-status
+		ber.Integer(0), //error-index
+		ber.Sequence(binds...),
+	)
+	return ber.Sequence(ber.Integer(version), ber.OctetString([]byte(community)), pdu), nil
+}
+
+// valor de un varbind
+type value ber.Element
+
+func (v value) text() string {
+	if v.Tag != ber.TagOctetString {
+		return ""
+	}
+	return strings.TrimSpace(strings.ToValidUTF8(string(v.Value), "?"))
+}
+
+func (v value) oid() string {
+	s, _ := ber.Element(v).OID()
+	return s
+}
+
+func (v value) number() int64 {
+	n, _ := ber.Element(v).Uint()
+	return int64(n)
+}
+
+// GetResponse v1/v2c: request-id y varbinds (los errores noSuchObject quedan sin valor)
+func parseResponse(buf []byte) (int64, map[string]value, error) {
+	msg, _, err := ber.Parse(buf)
+	if err != nil {
+		return 0, nil, err
+	}
+	fields, err := msg.Children()
+	if err != nil || len(fields) != 3 || fields[2].Tag != pduResponse {
+		return 0, nil, errors.New("not an snmp response")
+	}
+	pdu, err := fields[2].Children()
+	if err != nil || len(pdu) != 4 {
+		return 0, nil, errors.New("malformed snmp pdu")
+	}
+	id, err := pdu[0].Int()
+	if err != nil {
+		return 0, nil, err
+	}
+	if status, _ := pdu[1].Int(); status != 0 {
+		return 0, nil, errors.New("snmp error status")
+	}
+
+	binds, err := pdu[3].Children()
+	if err != nil {
+		return 0, nil, err
+	}
+	values := make(map[string]value)
+	for _, b := range binds {
+		pair, err := b.Children()
+		if err != nil || len(pair) != 2 {
+			continue
+		}
+		if oid, err := pair[0].OID(); err == nil {
+			values[oid] = value(pair[1])
+		}
+	}
+	return id, values, nil
+}
+
+// sistema operativo y producto a partir de sysDescr y del engine
+func describe(info *service.ServiceInfo) {
+	s := info.SNMP
+	descr := strings.ToLower(s.SysDescr)
+	switch {
+	case strings.Contains(descr, "cisco ios"):
+		info.OSHint = "Cisco IOS"
+	case strings.HasPrefix(descr, "linux"):
+		info.OSHint = "Linux"
+	case strings.Contains(descr, "windows"):
+		info.OSHint = "Windows"
+	case strings.Contains(descr, "freebsd"):
+		info.OSHint = "FreeBSD"
+	case strings.Contains(descr, "junos"):
+		info.OSHint = "Junos"
+	case strings.Contains(descr, "routeros"):
+		info.OSHint = "RouterOS"
+	}
+
+	if strings.HasPrefix(s.SysObjectID, "1.3.6.1.4.1.8072.") || s.EngineVendor == "net-snmp" {
+		info.Product = "net-snmp"
+		addCPE(info, "net-snmp:net-snmp")
+	}
+	if line, _, _ := strings.Cut(s.SysDescr, "\n"); line != "" {
+		info.ExtraInfo = strings.TrimSpace(line)
+	}
+}
+
+func addCPE(info *service.ServiceInfo, vendorProduct string) {
+	info.CPE = append(info.CPE, "cpe:/a:"+vendorProduct)
+}
@@ -0,0 +1,132 @@
+package snmp
+
+//SNMPv3 -> GET sin usuario (noAuthNoPriv), el agente responde un REPORT con su engine
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/ber"
+	"go-scanner/internal/scanner/service"
+	"math/rand"
+)
+
+// modelo de seguridad USM
+const securityModelUSM = 3
+
+// msgFlags: reportable, sin autenticacion ni cifrado
+const flagReportable = 0x04
+
+// empresas IANA frecuentes en engine IDs
+var enterprises = map[uint32]string{
+	2:     "IBM",
+	9:     "Cisco",
+	11:    "HP",
+	311:   "Microsoft",
+	2011:  "Huawei",
+	2636:  "Juniper",
+	3375:  "F5",
+	4526:  "Netgear",
+	6876:  "VMware",
+	8072:  "net-snmp",
+	12356: "Fortinet",
+	14988: "MikroTik",
+	25461: "Palo Alto Networks",
+	41112: "Ubiquiti",
+}
+
+func discoverEngine(ctx context.Context, opts probe.Options, address string, details *service.SNMPInfo) error {
+	conn, err := opts.Dial(ctx, "udp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	msgID := rand.Int63n(1 << 30)
+	if _, err := conn.Write(discoveryRequest(msgID)); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return err
+		}
+		if parseReport(buf[:n], msgID, details) == nil {
+			return nil
+		}
+	}
+}
+
+// mensaje v3 con engine ID y usuario vacios y un GetRequest sin varbinds
+func discoveryRequest(msgID int64) []byte {
+	header := ber.Sequence(
+		ber.Integer(msgID),
+		ber.Integer(65507), //msgMaxSize
+		ber.OctetString([]byte{flagReportable}),
+		ber.Integer(securityModelUSM),
+	)
+	usm := ber.Sequence(
+		ber.OctetString(nil), //engine ID
+		ber.Integer(0),       //boots
+		ber.Integer(0),       //time
+		ber.OctetString(nil), //usuario
+		ber.OctetString(nil), //parametros de autenticacion
+		ber.OctetString(nil), //parametros de privacidad
+	)
+	scoped := ber.Sequence(
+		ber.OctetString(nil), //contextEngineID
+		ber.OctetString(nil), //contextName
+		ber.Constructed(pduGetRequest, ber.Integer(msgID), ber.Integer(0), ber.Integer(0), ber.Sequence()),
+	)
+	return ber.Sequence(ber.Integer(versionV3), header, ber.OctetString(usm), scoped)
+}
+
+// del REPORT solo interesan los parametros USM (engine ID, boots y time)
+func parseReport(buf []byte, msgID int64, details *service.SNMPInfo) error {
+	msg, _, err := ber.Parse(buf)
+	if err != nil {
+		return err
+	}
+	fields, err := msg.Children()
+	if err != nil || len(fields) < 4 {
+		return errors.New("not an snmpv3 message")
+	}
+	if v, err := fields[0].Int(); err != nil || v != versionV3 {
+		return errors.New("not an snmpv3 message")
+	}
+	header, err := fields[1].Children()
+	if err != nil || len(header) < 1 {
+		return errors.New("malformed snmpv3 header")
+	}
+	if id, err := header[0].Int(); err != nil || id != msgID {
+		return errors.New("unexpected snmpv3 message id")
+	}
+
+	//el agente contesta con un REPORT (usmStatsUnknownEngineIDs) dentro del scopedPDU
+	scoped, err := fields[3].Children()
+	if err != nil || len(scoped) < 3 || scoped[2].Tag != pduReport {
+		return errors.New("not an snmpv3 report")
+	}
+
+	usmSeq, _, err := ber.Parse(fields[2].Value)
+	if err != nil {
+		return err
+	}
+	usm, err := usmSeq.Children()
+	if err != nil || len(usm) < 3 || len(usm[0].Value) == 0 {
+		return errors.New("malformed usm parameters")
+	}
+
+	engineID := usm[0].Value
+	details.EngineID = hex.EncodeToString(engineID)
+	details.EngineBoots, _ = usm[1].Int()
+	details.EngineTime, _ = usm[2].Int()
+	if len(engineID) >= 4 {
+		//los 4 primeros bytes son el numero de empresa (bit alto = formato RFC 3411)
+		enterprise := (uint32(engineID[0])<<24 | uint32(engineID[1])<<16 | uint32(engineID[2])<<8 | uint32(engineID[3])) & 0x7fffffff
+		details.EngineVendor = enterprises[enterprise]
+	}
+	return nil
+}
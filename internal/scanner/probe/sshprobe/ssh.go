@@ -0,0 +1,229 @@
+package sshprobe
+
+//PROBER SSH -> intercambio de versiones, KEXINIT y claves de host
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// identificacion enviada al servidor
+const clientVersion = "SSH-2.0-go-scanner"
+
+// SSH_MSG_KEXINIT
+const msgKexInit = 20
+
+// se detiene el handshake una vez obtenida la clave de host
+var errHostKeyCaptured = errors.New("host key captured")
+
+// prober SSH
+type SSHProbe struct{}
+
+func NewSSHProbe() *SSHProbe {
+	return &SSHProbe{}
+}
+
+func (p *SSHProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     "ssh",
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{service.ServiceSSH},
+		Ports:    []int{22},
+	}
+}
+
+// completa el intercambio de versiones y KEXINIT, luego obtiene una clave de host por tipo
+func (p *SSHProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	address := t.Address()
+
+	details, err := kexInit(ctx, opts, address)
+	if err != nil {
+		return nil, err
+	}
+	details.Weak = service.WeakSSHAlgorithms(details)
+
+	for _, keyType := range keyTypes(details.HostKeyAlgorithms) {
+		key, err := hostKey(ctx, opts, address, keyType)
+		if err != nil {
+			continue
+		}
+		details.HostKeys = append(details.HostKeys, service.HostKey{
+			Type:        key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(key),
+		})
+	}
+
+	info := &service.ServiceInfo{
+		Type:       service.ServiceSSH,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		SSH:        details,
+	}
+	if parsed, ok := service.ParseBanner(details.Banner); ok {
+		info.Merge(&parsed)
+	}
+	return info, nil
+}
+
+// lee la identificacion del servidor y su KEXINIT (en claro)
+func kexInit(ctx context.Context, opts probe.Options, address string) (*service.SSHInfo, error) {
+	conn, err := opts.Dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	//el servidor puede enviar lineas antes de la identificacion (RFC 4253 4.2)
+	var banner string
+	for i := 0; i < 32; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("no ssh identification: %w", err)
+		}
+		if strings.HasPrefix(line, "SSH-") {
+			banner = strings.TrimRight(line, "\r\n")
+			break
+		}
+	}
+	if banner == "" {
+		return nil, errors.New("no ssh identification")
+	}
+
+	if _, err := conn.Write([]byte(clientVersion + "\r\n")); err != nil {
+		return nil, err
+	}
+
+	payload, err := readPacket(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < 17 || payload[0] != msgKexInit {
+		return nil, errors.New("unexpected ssh packet, expected KEXINIT")
+	}
+
+	//cookie (16 bytes) y luego las name-lists
+	lists, err := nameLists(payload[17:], 8)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.SSHInfo{
+		Banner:            banner,
+		KexAlgorithms:     lists[0],
+		HostKeyAlgorithms: lists[1],
+		Ciphers:           lists[3], //servidor -> cliente
+		MACs:              lists[5],
+		Compression:       lists[7],
+	}, nil
+}
+
+// paquete binario sin cifrar: largo, padding, payload
+func readPacket(r io.Reader) ([]byte, error) {
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(head[0:4])
+	padding := uint32(head[4])
+	if length < padding+1 || length > 256*1024 {
+		return nil, errors.New("invalid ssh packet length")
+	}
+
+	rest := make([]byte, length-1)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, err
+	}
+	return rest[:length-1-padding], nil
+}
+
+// lee n name-lists (uint32 largo + nombres separados por coma)
+func nameLists(data []byte, n int) ([][]string, error) {
+	lists := make([][]string, 0, n)
+	for i := 0; i < n; i++ {
+		if len(data) < 4 {
+			return nil, errors.New("truncated KEXINIT")
+		}
+		size := binary.BigEndian.Uint32(data[:4])
+		if uint32(len(data)-4) < size {
+			return nil, errors.New("truncated KEXINIT")
+		}
+
+		var names []string
+		if size > 0 {
+			names = strings.Split(string(data[4:4+size]), ",")
+		}
+		lists = append(lists, names)
+		data = data[4+size:]
+	}
+	return lists, nil
+}
+
+// un algoritmo representativo por tipo de clave (rsa-sha2-* y ssh-rsa usan la misma clave)
+func keyTypes(algorithms []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, a := range algorithms {
+		kind := a
+		switch {
+		case a == "ssh-rsa" || strings.HasPrefix(a, "rsa-sha2-"):
+			kind = "rsa"
+		case strings.Contains(a, "-cert-v01@"):
+			continue //certificados, la clave subyacente ya se cubre
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// handshake limitado a un algoritmo de clave de host, se corta al recibir la clave
+func hostKey(ctx context.Context, opts probe.Options, address, algorithm string) (ssh.PublicKey, error) {
+	supported, insecure := ssh.SupportedAlgorithms(), ssh.InsecureAlgorithms()
+
+	var captured ssh.PublicKey
+	config := &ssh.ClientConfig{
+		User:              "go-scanner",
+		Timeout:           opts.ConnTimeout(),
+		ClientVersion:     clientVersion,
+		HostKeyAlgorithms: []string{algorithm},
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			captured = key
+			return errHostKeyCaptured
+		},
+		Config: ssh.Config{
+			KeyExchanges: append(supported.KeyExchanges, insecure.KeyExchanges...),
+			Ciphers:      append(supported.Ciphers, insecure.Ciphers...),
+			MACs:         append(supported.MACs, insecure.MACs...),
+		},
+	}
+
+	conn, err := opts.Dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	_, _, _, err = ssh.NewClientConn(conn, address, config)
+	if captured != nil {
+		return captured, nil
+	}
+	if err == nil {
+		err = errors.New("no host key received")
+	}
+	return nil, err
+}
@@ -0,0 +1,69 @@
+package starttls
+
+//AUTH TLS de FTP (RFC 4217)
+import (
+	"fmt"
+	"go-scanner/internal/scanner/service"
+	"strings"
+)
+
+func NewFTPProbe() *StartTLSProbe {
+	return &StartTLSProbe{proto: protocol{
+		name:    "ftp",
+		service: service.ServiceFTP,
+		ports:   []int{21},
+		command: "AUTH TLS",
+		check:   ftpCheck,
+		upgrade: ftpUpgrade,
+	}}
+}
+
+// saludo 220 (puede ser multilinea "220-")
+func ftpGreeting(s *session) error {
+	code, _, err := s.readReply()
+	if err != nil {
+		return err
+	}
+	if code != 220 {
+		return fmt.Errorf("unexpected ftp greeting %d", code)
+	}
+	return nil
+}
+
+func ftpCheck(s *session) (bool, bool, error) {
+	if err := ftpGreeting(s); err != nil {
+		return false, false, err
+	}
+
+	//FEAT lista "AUTH TLS" (o "AUTH SSL;TLS")
+	offered := false
+	if code, lines, err := s.command("FEAT"); err == nil && code == 211 {
+		for _, l := range lines {
+			u := strings.ToUpper(strings.TrimSpace(l))
+			if strings.HasPrefix(u, "AUTH") && strings.Contains(u, "TLS") {
+				offered = true
+			}
+		}
+	}
+
+	//USER rechazado mencionando TLS -> obligatorio
+	code, lines, err := s.command("USER anonymous")
+	required := err == nil && code >= 500 && mentionsTLS(lines)
+	s.send("QUIT")
+
+	return offered, required, nil
+}
+
+func ftpUpgrade(s *session) error {
+	if err := ftpGreeting(s); err != nil {
+		return err
+	}
+	code, _, err := s.command("AUTH TLS")
+	if err != nil {
+		return err
+	}
+	if code != 234 {
+		return errNotOffered
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package starttls
+
+//StartTLS de LDAP (extended operation, RFC 4511)
+import (
+	"errors"
+	"go-scanner/internal/scanner/service"
+	"io"
+)
+
+// OID de la extended operation StartTLS
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// resultCodes relevantes
+const (
+	ldapSuccess                 = 0
+	ldapConfidentialityRequired = 13
+)
+
+func NewLDAPProbe() *StartTLSProbe {
+	return &StartTLSProbe{proto: protocol{
+		name:    "ldap",
+		service: service.ServiceLDAP,
+		ports:   []int{389},
+		command: "StartTLS",
+		check:   ldapCheck,
+		upgrade: ldapUpgrade,
+	}}
+}
+
+// ExtendedRequest StartTLS (messageID 1)
+func startTLSRequest() []byte {
+	name := append([]byte{0x80, byte(len(startTLSOID))}, startTLSOID...) //requestName [0]
+	op := append([]byte{0x77, byte(len(name))}, name...)                 //[APPLICATION 23]
+	msg := append([]byte{0x02, 0x01, 0x01}, op...)                       //messageID
+	return append([]byte{0x30, byte(len(msg))}, msg...)
+}
+
+// BindRequest simple anonimo (messageID 2)
+var anonymousBind = []byte{
+	0x30, 0x0c,
+	0x02, 0x01, 0x02, //messageID
+	0x60, 0x07, //[APPLICATION 0] BindRequest
+	0x02, 0x01, 0x03, //version 3
+	0x04, 0x00, //name ""
+	0x80, 0x00, //simple ""
+}
+
+// envia un mensaje LDAP y retorna el resultCode de la respuesta
+func ldapExchange(s *session, msg []byte) (int, error) {
+	if _, err := s.conn.Write(msg); err != nil {
+		return 0, err
+	}
+
+	tag, content, err := readTLV(s.r)
+	if err != nil {
+		return 0, err
+	}
+	if tag != 0x30 {
+		return 0, errors.New("unexpected ldap message")
+	}
+
+	//messageID, luego la operacion de respuesta cuyo primer campo es el resultCode
+	_, op, err := splitTLV(content)
+	if err != nil {
+		return 0, err
+	}
+	result, _, err := splitTLV(op)
+	if err != nil {
+		return 0, err
+	}
+	code, _, err := splitTLV(result)
+	if err != nil || len(code) == 0 {
+		return 0, errors.New("malformed ldap result")
+	}
+	return int(code[0]), nil
+}
+
+func ldapCheck(s *session) (bool, bool, error) {
+	code, err := ldapExchange(s, startTLSRequest())
+	if err != nil {
+		return false, false, err
+	}
+	offered := code == ldapSuccess
+	if offered {
+		//la sesion ya espera el handshake, el bind va en otra conexion
+		return offered, false, nil
+	}
+
+	bind, err := ldapExchange(s, anonymousBind)
+	return offered, err == nil && bind == ldapConfidentialityRequired, nil
+}
+
+func ldapUpgrade(s *session) error {
+	code, err := ldapExchange(s, startTLSRequest())
+	if err != nil {
+		return err
+	}
+	if code != ldapSuccess {
+		return errNotOffered
+	}
+	return nil
+}
+
+// lee un TLV BER completo (longitud corta o larga)
+func readTLV(r io.Reader) (byte, []byte, error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return 0, nil, err
+	}
+
+	length := int(head[1])
+	if head[1]&0x80 != 0 {
+		n := int(head[1] & 0x7f)
+		if n == 0 || n > 4 {
+			return 0, nil, errors.New("unsupported ber length")
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return 0, nil, err
+		}
+		length = 0
+		for _, b := range buf {
+			length = length<<8 | int(b)
+		}
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return 0, nil, err
+	}
+	return head[0], content, nil
+}
+
+// separa el primer TLV de data, retorna su contenido y el resto
+func splitTLV(data []byte) ([]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errors.New("short ber element")
+	}
+
+	length, offset := int(data[1]), 2
+	if data[1]&0x80 != 0 {
+		n := int(data[1] & 0x7f)
+		if n == 0 || n > 4 || len(data) < 2+n {
+			return nil, nil, errors.New("unsupported ber length")
+		}
+		length = 0
+		for _, b := range data[2 : 2+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+	if len(data) < offset+length {
+		return nil, nil, errors.New("truncated ber element")
+	}
+	return data[offset : offset+length], data[offset+length:], nil
+}
@@ -0,0 +1,280 @@
+package starttls
+
+//SMTP, IMAP y POP3
+import (
+	"fmt"
+	"go-scanner/internal/scanner/service"
+	"strconv"
+	"strings"
+)
+
+// nombre anunciado en EHLO
+const ehloName = "go-scanner.local"
+
+// STARTTLS de SMTP (RFC 3207)
+func NewSMTPProbe() *StartTLSProbe {
+	return &StartTLSProbe{proto: protocol{
+		name:    "smtp",
+		service: service.ServiceSMTP,
+		ports:   []int{25, 587},
+		command: "STARTTLS",
+		check:   smtpCheck,
+		upgrade: smtpUpgrade,
+	}}
+}
+
+// STARTTLS de IMAP (RFC 3501)
+func NewIMAPProbe() *StartTLSProbe {
+	return &StartTLSProbe{proto: protocol{
+		name:    "imap",
+		service: service.ServiceIMAP,
+		ports:   []int{143},
+		command: "STARTTLS",
+		check:   imapCheck,
+		upgrade: imapUpgrade,
+	}}
+}
+
+// STLS de POP3 (RFC 2595)
+func NewPOP3Probe() *StartTLSProbe {
+	return &StartTLSProbe{proto: protocol{
+		name:    "pop3",
+		service: service.ServicePOP3,
+		ports:   []int{110},
+		command: "STLS",
+		check:   pop3Check,
+		upgrade: pop3Upgrade,
+	}}
+}
+
+// lee una respuesta con codigo numerico (SMTP, FTP), multilinea "250-..." hasta "250 ..."
+func (s *session) readReply() (int, []string, error) {
+	var lines []string
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			return 0, lines, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		if len(line) >= 3 && (len(line) == 3 || line[3] == ' ') {
+			if code, err := strconv.Atoi(line[:3]); err == nil {
+				return code, lines, nil
+			}
+		}
+	}
+}
+
+// envia un comando y lee su respuesta
+func (s *session) command(line string) (int, []string, error) {
+	if err := s.send(line); err != nil {
+		return 0, nil, err
+	}
+	return s.readReply()
+}
+
+// lee lineas hasta la que cumpla done (respuestas IMAP y POP3)
+func (s *session) readUntil(done func(line string) bool) ([]string, error) {
+	var lines []string
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			return lines, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+		if done(line) {
+			return lines, nil
+		}
+	}
+}
+
+// alguna linea contiene la capacidad (sin distinguir mayusculas)
+func hasCapability(lines []string, capability string) bool {
+	for _, l := range lines {
+		for _, f := range strings.Fields(strings.ToUpper(l)) {
+			if strings.Trim(f, "[]-") == capability {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// el mensaje de rechazo habla de cifrado
+func mentionsTLS(lines []string) bool {
+	text := strings.ToLower(strings.Join(lines, " "))
+	for _, k := range []string{"tls", "ssl", "secure", "encrypt", "plaintext", "starttls"} {
+		if strings.Contains(text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// saludo 220 y EHLO, retorna las extensiones
+func smtpHello(s *session) ([]string, error) {
+	code, _, err := s.readReply()
+	if err != nil {
+		return nil, err
+	}
+	if code != 220 {
+		return nil, fmt.Errorf("unexpected smtp greeting %d", code)
+	}
+
+	code, lines, err := s.command("EHLO " + ehloName)
+	if err != nil {
+		return nil, err
+	}
+	if code != 250 {
+		return nil, fmt.Errorf("ehlo rejected (%d)", code)
+	}
+	return lines, nil
+}
+
+func smtpCheck(s *session) (bool, bool, error) {
+	ext, err := smtpHello(s)
+	if err != nil {
+		return false, false, err
+	}
+	offered := hasCapability(ext, "STARTTLS")
+
+	//530 al iniciar una transaccion sin TLS -> obligatorio (RFC 3207)
+	//submission tambien responde 530 por falta de autenticacion (RFC 4954), el texto debe hablar de TLS
+	code, lines, err := s.command("MAIL FROM:<>")
+	required := err == nil && code == 530 && mentionsTLS(lines)
+	s.command("RSET")
+	s.send("QUIT")
+
+	return offered, required, nil
+}
+
+func smtpUpgrade(s *session) error {
+	if _, err := smtpHello(s); err != nil {
+		return err
+	}
+	code, _, err := s.command("STARTTLS")
+	if err != nil {
+		return err
+	}
+	if code != 220 {
+		return errNotOffered
+	}
+	return nil
+}
+
+// saludo "* OK"
+func imapGreeting(s *session) error {
+	lines, err := s.readUntil(func(string) bool { return true })
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.ToUpper(lines[0]), "* OK") {
+		return fmt.Errorf("unexpected imap greeting")
+	}
+	return nil
+}
+
+// envia un comando IMAP con tag y lee hasta la respuesta con ese tag
+func imapCommand(s *session, tag, cmd string) ([]string, error) {
+	if err := s.send(tag + " " + cmd); err != nil {
+		return nil, err
+	}
+	return s.readUntil(func(line string) bool {
+		return strings.HasPrefix(line, tag+" ")
+	})
+}
+
+// la ultima linea (con tag) es OK
+func imapOK(lines []string, tag string) bool {
+	return len(lines) > 0 && strings.HasPrefix(strings.ToUpper(lines[len(lines)-1]), tag+" OK")
+}
+
+func imapCheck(s *session) (bool, bool, error) {
+	if err := imapGreeting(s); err != nil {
+		return false, false, err
+	}
+
+	lines, err := imapCommand(s, "a1", "CAPABILITY")
+	if err != nil {
+		return false, false, err
+	}
+	s.send("a2 LOGOUT")
+
+	//LOGINDISABLED: no se permite LOGIN hasta negociar TLS
+	return hasCapability(lines, "STARTTLS"), hasCapability(lines, "LOGINDISABLED"), nil
+}
+
+func imapUpgrade(s *session) error {
+	if err := imapGreeting(s); err != nil {
+		return err
+	}
+	lines, err := imapCommand(s, "a1", "STARTTLS")
+	if err != nil {
+		return err
+	}
+	if !imapOK(lines, "a1") {
+		return errNotOffered
+	}
+	return nil
+}
+
+// lee una linea de estado POP3 (+OK / -ERR)
+func pop3Status(s *session) (bool, []string, error) {
+	lines, err := s.readUntil(func(string) bool { return true })
+	if err != nil {
+		return false, lines, err
+	}
+	return strings.HasPrefix(lines[0], "+OK"), lines, nil
+}
+
+func pop3Check(s *session) (bool, bool, error) {
+	ok, _, err := pop3Status(s)
+	if err != nil {
+		return false, false, err
+	}
+	if !ok {
+		return false, false, fmt.Errorf("unexpected pop3 greeting")
+	}
+
+	//CAPA es multilinea y termina con "."
+	offered := false
+	if err := s.send("CAPA"); err != nil {
+		return false, false, err
+	}
+	if ok, _, err := pop3Status(s); err == nil && ok {
+		lines, err := s.readUntil(func(line string) bool { return line == "." })
+		if err != nil {
+			return false, false, err
+		}
+		offered = hasCapability(lines, "STLS")
+	}
+
+	//USER rechazado mencionando TLS -> obligatorio
+	required := false
+	if err := s.send("USER anonymous"); err == nil {
+		ok, lines, err := pop3Status(s)
+		required = err == nil && !ok && mentionsTLS(lines)
+	}
+	s.send("QUIT")
+
+	return offered, required, nil
+}
+
+func pop3Upgrade(s *session) error {
+	if ok, _, err := pop3Status(s); err != nil || !ok {
+		return fmt.Errorf("unexpected pop3 greeting")
+	}
+	if err := s.send("STLS"); err != nil {
+		return err
+	}
+	ok, _, err := pop3Status(s)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errNotOffered
+	}
+	return nil
+}
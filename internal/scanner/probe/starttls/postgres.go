@@ -0,0 +1,122 @@
+package starttls
+
+//SSLRequest de PostgreSQL (protocolo v3)
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"strings"
+)
+
+// codigos magicos del protocolo
+const (
+	pgSSLRequestCode = 80877103
+	pgProtocolV3     = 196608
+)
+
+func NewPostgresProbe() *StartTLSProbe {
+	return &StartTLSProbe{proto: protocol{
+		name:    "postgresql",
+		service: service.ServicePostgreSQL,
+		ports:   []int{5432},
+		command: "SSLRequest",
+		check:   postgresCheck,
+		upgrade: postgresUpgrade,
+	}}
+}
+
+// SSLRequest: largo (8) + codigo
+func sslRequest() []byte {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint32(msg[0:4], 8)
+	binary.BigEndian.PutUint32(msg[4:8], pgSSLRequestCode)
+	return msg
+}
+
+// StartupMessage sin cifrado para ver si el servidor lo rechaza
+func startupMessage(user string) []byte {
+	var body bytes.Buffer
+	binary.Write(&body, binary.BigEndian, uint32(pgProtocolV3))
+	for _, kv := range []string{"user", user, "database", user} {
+		body.WriteString(kv)
+		body.WriteByte(0)
+	}
+	body.WriteByte(0)
+
+	msg := make([]byte, 4, 4+body.Len())
+	binary.BigEndian.PutUint32(msg, uint32(4+body.Len()))
+	return append(msg, body.Bytes()...)
+}
+
+// respuesta de un byte al SSLRequest: 'S' acepta, 'N' rechaza
+func postgresSSL(s *session) (bool, error) {
+	if _, err := s.conn.Write(sslRequest()); err != nil {
+		return false, err
+	}
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 'S':
+		return true, nil
+	case 'N':
+		return false, nil
+	default:
+		return false, errors.New("unexpected postgres ssl response")
+	}
+}
+
+func postgresCheck(s *session) (bool, bool, error) {
+	offered, err := postgresSSL(s)
+	if err != nil {
+		return false, false, err
+	}
+	if !offered {
+		//con 'N' la misma sesion sigue en claro
+		return false, postgresRejectsPlain(s), nil
+	}
+
+	//con 'S' la sesion ya espera el handshake: el inicio sin cifrado va en otra conexion
+	plain, err := s.redial()
+	if err != nil {
+		return offered, false, nil
+	}
+	defer plain.conn.Close()
+	return offered, postgresRejectsPlain(plain), nil
+}
+
+// StartupMessage sin SSLRequest: un pg_hba con solo hostssl lo rechaza ("no encryption" / "SSL off")
+// un pedido de autenticacion ('R') significa que se acepta en claro
+func postgresRejectsPlain(s *session) bool {
+	if _, err := s.conn.Write(startupMessage("postgres")); err != nil {
+		return false
+	}
+	tag, err := s.r.ReadByte()
+	if err != nil || tag != 'E' {
+		return false
+	}
+	var length uint32
+	if err := binary.Read(s.r, binary.BigEndian, &length); err != nil || length < 4 || length > 8192 {
+		return false
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(s.r, body); err != nil {
+		return false
+	}
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "no encryption") || strings.Contains(msg, "ssl off")
+}
+
+func postgresUpgrade(s *session) error {
+	offered, err := postgresSSL(s)
+	if err != nil {
+		return err
+	}
+	if !offered {
+		return errNotOffered
+	}
+	return nil
+}
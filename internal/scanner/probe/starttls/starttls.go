@@ -0,0 +1,140 @@
+package starttls
+
+//PROBERS STARTTLS -> upgrade del protocolo en claro y analisis TLS
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/probe/tlsprobe"
+	"go-scanner/internal/scanner/service"
+	"net"
+)
+
+// el servidor no ofrece el upgrade
+var errNotOffered = errors.New("starttls not offered")
+
+// conexion en claro con lector con buffer (los protocolos de texto leen por lineas)
+type session struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	//otra conexion en claro al mismo servicio (el llamador la cierra)
+	redial func() (*session, error)
+}
+
+// envia una linea terminada en CRLF
+func (s *session) send(line string) error {
+	_, err := s.conn.Write([]byte(line + "\r\n"))
+	return err
+}
+
+// dialogo en claro de un protocolo
+type protocol struct {
+	name    string //nombre del prober en la policy
+	service service.ServiceType
+	ports   []int  //puertos habituales del protocolo en claro
+	command string //comando de upgrade (STARTTLS, STLS, AUTH TLS, ...)
+
+	//revisa en claro si el upgrade se ofrece y si es obligatorio
+	check func(s *session) (offered, required bool, err error)
+
+	//deja la sesion lista para el handshake (errNotOffered si el servidor lo rechaza)
+	upgrade func(s *session) error
+}
+
+// prober generico: el protocolo define el dialogo, el analisis TLS es comun
+type StartTLSProbe struct {
+	proto protocol
+}
+
+func (p *StartTLSProbe) Spec() probe.Spec {
+	return probe.Spec{
+		Name:     p.proto.name,
+		Protocol: model.ProtocolTCP,
+		Services: []service.ServiceType{p.proto.service},
+		Ports:    p.proto.ports,
+		Groups:   []string{"starttls"},
+	}
+}
+
+// realiza el check en claro y, si el upgrade se ofrece, el analisis TLS completo
+func (p *StartTLSProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	address := t.Address()
+
+	var offered, required bool
+	err := withSession(ctx, opts, address, func(s *session) error {
+		var err error
+		offered, required, err = p.proto.check(s)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s check failed: %w", p.proto.command, err)
+	}
+
+	info := &service.ServiceInfo{
+		Type:       p.proto.service,
+		Method:     service.MethodProbe,
+		Confidence: model.ConfidenceHigh,
+		StartTLS: &service.StartTLSInfo{
+			Command:  p.proto.command,
+			Offered:  offered,
+			Required: required,
+		},
+	}
+	if !offered {
+		return info, nil
+	}
+
+	//cada handshake repite el dialogo en una conexion nueva
+	sni := t.Hostname()
+	if sni == "" {
+		sni = t.Host
+	}
+	details, err := tlsprobe.Inspect(opts, sni, func(config *tls.Config) (*tls.Conn, error) {
+		conn, err := opts.Dial(ctx, "tcp", address)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.proto.upgrade(&session{conn: conn, r: bufio.NewReader(conn)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	})
+	if err == nil {
+		info.TLS = true
+		info.TLSInfo = details
+	}
+	return info, nil
+}
+
+// abre una conexion en claro con deadline y ejecuta fn
+func withSession(ctx context.Context, opts probe.Options, address string, fn func(s *session) error) error {
+	dial := func() (*session, error) {
+		conn, err := opts.Dial(ctx, "tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		return &session{conn: conn, r: bufio.NewReader(conn)}, nil
+	}
+
+	s, err := dial()
+	if err != nil {
+		return err
+	}
+	defer s.conn.Close()
+
+	s.redial = dial
+	return fn(s)
+}
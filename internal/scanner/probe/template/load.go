@@ -0,0 +1,67 @@
+package template
+
+//CARGA -> templates de un directorio del usuario y registro junto a los probers incluidos
+import (
+	"fmt"
+	"go-scanner/internal/scanner/probe"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// carga los templates (*.yaml, *.yml) del directorio y sus subdirectorios
+// cualquier template invalido aborta la carga indicando el archivo
+func LoadDir(dir string) ([]*Template, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+	sort.Strings(files)
+
+	var templates []*Template
+	ids := make(map[string]string)
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		t, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		id := strings.ToLower(t.ID)
+		if prev, dup := ids[id]; dup {
+			return nil, fmt.Errorf("%s: duplicate id %q (also in %s)", path, t.ID, prev)
+		}
+		ids[id] = path
+		t.File = path
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// agrega los templates al registro, despues de los probers ya registrados
+// un id no puede reemplazar a un prober existente del mismo protocolo
+func Register(r *probe.Registry, templates []*Template) error {
+	for _, t := range templates {
+		spec := t.Spec()
+		if _, exists := r.Get(spec.Protocol, spec.Name); exists {
+			return fmt.Errorf("%s: id %q is already a registered prober", t.File, t.ID)
+		}
+		r.Register(t)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package template
+
+//EVALUACION -> matchers y extractores sobre la respuesta obtenida
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// respuesta de un request (en TCP/UDP solo body)
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// parte de la respuesta sobre la que se evalua
+func (r *response) part(name string) []byte {
+	switch strings.ToLower(name) {
+	case "header":
+		return r.rawHeader()
+	case "all":
+		return append(r.rawHeader(), r.body...)
+	default:
+		return r.body
+	}
+}
+
+// headers como texto "Clave: valor\r\n"
+func (r *response) rawHeader() []byte {
+	var buf bytes.Buffer
+	if r.header != nil {
+		r.header.Write(&buf)
+	}
+	return buf.Bytes()
+}
+
+// evalua los matchers del request con su condicion
+func (r *Rules) match(resp *response) bool {
+	and := strings.EqualFold(r.MatchersCondition, "and")
+	for _, m := range r.Matchers {
+		ok := m.match(resp)
+		if and && !ok {
+			return false
+		}
+		if !and && ok {
+			return true
+		}
+	}
+	return and
+}
+
+// valores extraidos, sin repetir
+func (r *Rules) extract(resp *response) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, e := range r.Extractors {
+		for _, v := range e.extract(resp) {
+			if v != "" && !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+func (m *Matcher) match(resp *response) bool {
+	return m.eval(resp) != m.Negative
+}
+
+// condicion entre los valores del matcher
+func (m *Matcher) eval(resp *response) bool {
+	and := strings.EqualFold(m.Condition, "and")
+	data := resp.part(m.Part)
+
+	var checks []func() bool
+	switch m.Type {
+	case "word":
+		text := string(data)
+		if m.CaseInsensitive {
+			text = strings.ToLower(text)
+		}
+		for _, w := range m.Words {
+			if m.CaseInsensitive {
+				w = strings.ToLower(w)
+			}
+			checks = append(checks, func() bool { return strings.Contains(text, w) })
+		}
+	case "regex":
+		for _, re := range m.regexps {
+			checks = append(checks, func() bool { return re.Match(data) })
+		}
+	case "binary":
+		for _, b := range m.binary {
+			checks = append(checks, func() bool { return bytes.Contains(data, b) })
+		}
+	case "status":
+		for _, s := range m.Status {
+			checks = append(checks, func() bool { return resp.status == s })
+		}
+	}
+
+	for _, check := range checks {
+		ok := check()
+		if and && !ok {
+			return false
+		}
+		if !and && ok {
+			return true
+		}
+	}
+	return and
+}
+
+func (e *Extractor) extract(resp *response) []string {
+	var out []string
+	switch e.Type {
+	case "regex":
+		data := resp.part(e.Part)
+		for _, re := range e.re {
+			for _, m := range re.FindAllSubmatch(data, -1) {
+				out = append(out, string(m[e.Group]))
+			}
+		}
+	case "kval":
+		for _, k := range e.KVal {
+			//nuclei usa guiones bajos en las claves (content_type)
+			if v := resp.header.Get(strings.ReplaceAll(k, "_", "-")); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package template
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// reglas de un template HTTP con el bloque de matchers/extractores dado
+func httpRules(t *testing.T, rules string) *Rules {
+	t.Helper()
+	tpl, err := Parse([]byte("id: test\nhttp:\n  - path: /\n" + indent(rules, "    ")))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return &tpl.HTTP[0].Rules
+}
+
+// reglas de un template TCP
+func tcpRules(t *testing.T, rules string) *Rules {
+	t.Helper()
+	tpl, err := Parse([]byte("id: test\ntcp:\n  - inputs: [{data: x}]\n" + indent(rules, "    ")))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return &tpl.TCP[0].Rules
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+var page = &response{
+	status: 200,
+	header: http.Header{"Server": {"Apache/2.4.57 (Debian)"}, "Content-Type": {"text/html"}, "X-Powered-By": {"PHP/8.2.7"}},
+	body:   []byte("<title>Jenkins</title>\n<meta name=\"version\" content=\"2.426.1\">\nbuild 2.426.1 ok"),
+}
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		want  bool
+	}{
+		{"word in body", "matchers:\n  - type: word\n    words: [Jenkins]", true},
+		{"word missing", "matchers:\n  - type: word\n    words: [Grafana]", false},
+		{"word is case sensitive", "matchers:\n  - type: word\n    words: [jenkins]", false},
+		{"word case insensitive", "matchers:\n  - type: word\n    words: [JENKINS]\n    case-insensitive: true", true},
+		{"words or", "matchers:\n  - type: word\n    words: [Grafana, Jenkins]", true},
+		{"words and", "matchers:\n  - type: word\n    words: [Grafana, Jenkins]\n    condition: and", false},
+		{"words and all present", "matchers:\n  - type: word\n    words: [Jenkins, '2.426.1']\n    condition: and", true},
+		{"word list as string", "matchers:\n  - type: word\n    words: Grafana, Jenkins", true},
+		{"word not in body", "matchers:\n  - type: word\n    words: [Apache]", false},
+		{"word in header", "matchers:\n  - type: word\n    part: header\n    words: [Apache]", true},
+		{"header part has no body", "matchers:\n  - type: word\n    part: header\n    words: [Jenkins]", false},
+		{"word in all", "matchers:\n  - type: word\n    part: all\n    words: [Apache, Jenkins]\n    condition: and", true},
+		{"negative", "matchers:\n  - type: word\n    words: [Grafana]\n    negative: true", true},
+		{"negative of match", "matchers:\n  - type: word\n    words: [Jenkins]\n    negative: true", false},
+		{"regex", "matchers:\n  - type: regex\n    regex: ['content=\"2\\.4[0-9]+']", true},
+		{"regex case insensitive", "matchers:\n  - type: regex\n    regex: ['<TITLE>jenkins']\n    case-insensitive: true", true},
+		{"regex in header", "matchers:\n  - type: regex\n    part: header\n    regex: ['(?m)^Server: Apache/2\\.4']", true},
+		{"regex and", "matchers:\n  - type: regex\n    regex: ['Jenkins', 'Grafana']\n    condition: and", false},
+		{"binary", "matchers:\n  - type: binary\n    binary: ['3c 74 69 74 6c 65 3e']", true},
+		{"binary missing", "matchers:\n  - type: binary\n    binary: ['00ff']", false},
+		{"status", "matchers:\n  - type: status\n    status: [301, 200]", true},
+		{"status missing", "matchers:\n  - type: status\n    status: [404]", false},
+		{"status and never matches two codes", "matchers:\n  - type: status\n    status: [200, 404]\n    condition: and", false},
+		{
+			name:  "matchers or",
+			rules: "matchers:\n  - type: status\n    status: [404]\n  - type: word\n    words: [Jenkins]",
+			want:  true,
+		},
+		{
+			name:  "matchers and",
+			rules: "matchers-condition: and\nmatchers:\n  - type: status\n    status: [404]\n  - type: word\n    words: [Jenkins]",
+			want:  false,
+		},
+		{
+			name:  "matchers and all match",
+			rules: "matchers-condition: AND\nmatchers:\n  - type: status\n    status: [200]\n  - type: word\n    words: [Jenkins]\n  - type: word\n    words: [Grafana]\n    negative: true",
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := httpRules(t, tt.rules).match(page); got != tt.want {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRawMatchers(t *testing.T) {
+	banner := &response{body: []byte("\x00\x00\x00\x0a5.7.42-log\x00SSH-2.0-OpenSSH_8.9p1")}
+
+	tests := []struct {
+		name  string
+		rules string
+		want  bool
+	}{
+		{"word", "matchers:\n  - type: word\n    words: [OpenSSH_8.9]", true},
+		{"binary", "matchers:\n  - type: binary\n    binary: ['0000000a35']", true},
+		{"binary and", "matchers:\n  - type: binary\n    binary: ['0a', 'ff']\n    condition: and", false},
+		{"all part is the response", "matchers:\n  - type: word\n    part: all\n    words: [5.7.42]", true},
+		{"regex over bytes", "matchers:\n  - type: regex\n    regex: ['^\\x00\\x00\\x00\\n5\\.7']", true},
+	}
+
+	for _, tt := range tests {
+		if got := tcpRules(t, tt.rules).match(banner); got != tt.want {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractors(t *testing.T) {
+	base := "matchers:\n  - type: status\n    status: [200]\nextractors:\n"
+
+	tests := []struct {
+		name  string
+		rules string
+		want  []string
+	}{
+		{"regex full match", "  - type: regex\n    regex: ['build [0-9.]+']", []string{"build 2.426.1"}},
+		{"regex group", "  - type: regex\n    regex: ['content=\"([0-9.]+)\"']\n    group: 1", []string{"2.426.1"}},
+		{"regex all matches without repeats", "  - type: regex\n    regex: ['2\\.[0-9.]+']", []string{"2.426.1"}},
+		{"regex in header", "  - type: regex\n    part: header\n    regex: ['Apache/([0-9.]+)']\n    group: 1", []string{"2.4.57"}},
+		{"regex no match", "  - type: regex\n    regex: ['Grafana v([0-9.]+)']\n    group: 1", nil},
+		{"kval", "  - type: kval\n    kval: [server, x_powered_by, missing]", []string{"Apache/2.4.57 (Debian)", "PHP/8.2.7"}},
+		{
+			name:  "several extractors",
+			rules: "  - type: kval\n    kval: [content_type]\n  - type: regex\n    regex: ['<title>(.*)</title>']\n    group: 1\n  - type: regex\n    regex: ['text/html']",
+			want:  []string{"text/html", "Jenkins"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := httpRules(t, base+tt.rules).extract(page); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: extract = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	//grupos vacios no se reportan
+	rules := tcpRules(t, "matchers:\n  - type: word\n    words: [x]\nextractors:\n  - type: regex\n    regex: ['v(\\d*)x']\n    group: 1")
+	if got := rules.extract(&response{body: []byte("vx v2x")}); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Errorf("extract empty group = %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+package template
+
+//TEMPLATE COMO PROBER -> declaracion para el registro y ejecucion de sus requests
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// grupo que habilita todos los templates
+const Group = "templates"
+
+// sin match el template no aporta nada al servicio
+var errNoMatch = errors.New("no match")
+
+// servicios web por defecto de los templates HTTP
+var webServices = []service.ServiceType{service.ServiceHTTP, service.ServiceHTTPS}
+
+// se habilita por id, "templates", "tag:<tag>" o "severity:<minima>"; "all" no los incluye
+// los HTTP corren despues de http (esquema y servicio ya identificados)
+func (t *Template) Spec() probe.Spec {
+	spec := probe.Spec{
+		Name:     t.ID,
+		Protocol: model.ProtocolTCP,
+		Ports:    t.Ports,
+		Groups:   []string{Group},
+		Explicit: true,
+	}
+	for _, name := range t.Services {
+		spec.Services = append(spec.Services, service.TypeFromName(name))
+	}
+
+	switch {
+	case len(t.UDP) > 0:
+		spec.Protocol = model.ProtocolUDP
+	case len(t.HTTP) > 0:
+		if len(spec.Services) == 0 {
+			spec.Services = webServices
+		}
+		spec.Requires = []string{"tls", "http"}
+	}
+
+	for _, tag := range t.tags {
+		spec.Groups = append(spec.Groups, "tag:"+tag)
+	}
+	//severity:x habilita los de severidad x o mayor
+	for _, sev := range service.Severities {
+		if sev.Rank() <= t.severity.Rank() {
+			spec.Groups = append(spec.Groups, "severity:"+string(sev))
+		}
+	}
+	return spec
+}
+
+// ejecuta los requests en orden, el primero con match genera el hallazgo
+func (t *Template) Probe(ctx context.Context, target probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	var matched string
+	var extracted []string
+	var err error
+	switch {
+	case len(t.TCP) > 0:
+		matched, extracted, err = t.runRaw(ctx, target, opts, "tcp", t.TCP)
+	case len(t.UDP) > 0:
+		matched, extracted, err = t.runRaw(ctx, target, opts, "udp", t.UDP)
+	default:
+		matched, extracted, err = t.runHTTP(ctx, target, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", t.ID, err)
+	}
+
+	return &service.ServiceInfo{
+		Findings: []service.Finding{{
+			ID:          t.ID,
+			Name:        t.Info.Name,
+			Severity:    t.severity,
+			Description: strings.TrimSpace(t.Info.Description),
+			Tags:        t.Info.Tags,
+			Source:      "template",
+			Matched:     matched,
+			Extracted:   extracted,
+		}},
+	}, nil
+}
+
+// requests de bytes crudos, una conexion por request
+func (t *Template) runRaw(ctx context.Context, target probe.Target, opts probe.Options, network string, requests []*Raw) (string, []string, error) {
+	lastErr := errNoMatch
+	for _, r := range requests {
+		data, err := r.exchange(ctx, target, opts, network)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp := &response{body: data}
+		if r.match(resp) {
+			return network + "://" + target.Address(), r.extract(resp), nil
+		}
+	}
+	return "", nil, lastErr
+}
+
+// envia cada input y acumula lo que responde el servicio (sin inputs solo lee el banner)
+func (r *Raw) exchange(ctx context.Context, target probe.Target, opts probe.Options, network string) ([]byte, error) {
+	var conn net.Conn
+	var err error
+	if r.TLS {
+		conn, err = opts.DialTLS(ctx, target.Address(), opts.TLSConfig(tlsName(target)))
+	} else {
+		conn, err = opts.Dial(ctx, network, target.Address())
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	vars := variables(target, "")
+	buf := make([]byte, r.ReadSize)
+	var data []byte
+	read := func() error {
+		n, err := conn.Read(buf)
+		data = append(data, buf[:n]...)
+		return err
+	}
+
+	if len(r.Inputs) == 0 {
+		read()
+	}
+	for _, in := range r.Inputs {
+		payload := in.raw
+		if in.Type == "" {
+			payload = []byte(vars.Replace(in.Data))
+		}
+		if _, err := conn.Write(payload); err != nil {
+			break
+		}
+		if err := read(); err != nil {
+			break
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty response")
+	}
+	return data, nil
+}
+
+// requests HTTP, cada path es un request
+func (t *Template) runHTTP(ctx context.Context, target probe.Target, opts probe.Options) (string, []string, error) {
+	host := target.Hostname()
+	vars := variables(target, scheme(target))
+	lastErr := errNoMatch
+
+	for _, r := range t.HTTP {
+		client := newClient(ctx, opts, tlsName(target), r.Redirects)
+		for _, path := range r.Path {
+			url := vars.Replace(path)
+			if strings.HasPrefix(url, "/") {
+				url = vars.Replace("{{BaseURL}}") + url
+			}
+
+			resp, err := r.do(ctx, client, url, host, vars)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if r.match(resp) {
+				return url, r.extract(resp), nil
+			}
+		}
+	}
+	return "", nil, lastErr
+}
+
+// un request HTTP, el body se lee hasta maxBodySize
+func (r *HTTP) do(ctx context.Context, client *http.Client, url, host string, vars *strings.Replacer) (*response, error) {
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(vars.Replace(r.Body))
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "go-scanner")
+	for k, v := range r.Headers {
+		req.Header.Set(k, vars.Replace(v))
+	}
+	//Host del template, si no el hostname del target
+	if h := req.Header.Get("Host"); h != "" {
+		req.Host = h
+	} else if host != "" {
+		req.Host = host
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil && len(data) == 0 {
+		return nil, err
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: data}, nil
+}
+
+// cliente HTTP con la conexion de la campaña, sin keep-alive
+func newClient(ctx context.Context, opts probe.Options, sni string, redirects bool) *http.Client {
+	client := &http.Client{
+		Timeout: opts.ConnTimeout(),
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, network, address string) (net.Conn, error) {
+				return opts.Dial(ctx, network, address)
+			},
+			TLSClientConfig:   opts.TLSConfig(sni),
+			DisableKeepAlives: true,
+		},
+	}
+	if !redirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// https si hubo TLS directo en el puerto o el servicio se identifico como HTTPS
+func scheme(t probe.Target) string {
+	if (t.Service.TLSInfo != nil && t.Service.StartTLS == nil) || t.Service.Type == service.ServiceHTTPS {
+		return "https"
+	}
+	return "http"
+}
+
+// nombre para el SNI: el hostname del target si se conoce
+func tlsName(t probe.Target) string {
+	if name := t.Hostname(); name != "" {
+		return name
+	}
+	return t.Host
+}
+
+// variables de los templates: {{BaseURL}}, {{RootURL}}, {{Hostname}} (host:puerto), {{Host}} y {{Port}}
+func variables(t probe.Target, scheme string) *strings.Replacer {
+	address := t.Address()
+	base := scheme + "://" + address
+	return strings.NewReplacer(
+		"{{BaseURL}}", base,
+		"{{RootURL}}", base,
+		"{{Hostname}}", address,
+		"{{Host}}", t.Host,
+		"{{Port}}", strconv.Itoa(t.Port),
+	)
+}
@@ -0,0 +1,362 @@
+package template
+
+//TEMPLATES -> checks declarativos en YAML (estilo nuclei): request TCP/UDP/HTTP, matchers y extractores
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"go-scanner/internal/scanner/service"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lectura maxima por defecto de una respuesta TCP/UDP
+const defaultReadSize = 4096
+
+// lectura maxima del body HTTP
+const maxBodySize = 64 * 1024
+
+// check declarativo, se registra como un prober mas
+type Template struct {
+	ID       string   `yaml:"id"`
+	Info     Info     `yaml:"info"`
+	Services strList  `yaml:"services"` //servicios sobre los que aplica (nombres de portdb/nmap)
+	Ports    []int    `yaml:"ports"`    //puertos si el servicio no se identifico
+	TCP      []*Raw   `yaml:"tcp"`
+	UDP      []*Raw   `yaml:"udp"`
+	HTTP     []*HTTP  `yaml:"http"`
+	File     string   `yaml:"-"` //archivo de origen
+	tags     []string //tags en minusculas
+	severity service.Severity
+}
+
+// metadatos del check
+type Info struct {
+	Name        string  `yaml:"name"`
+	Severity    string  `yaml:"severity"`
+	Description string  `yaml:"description"`
+	Tags        strList `yaml:"tags"`
+}
+
+// request de bytes crudos por TCP o UDP
+type Raw struct {
+	Inputs   []Input `yaml:"inputs"`    //se envian en orden, leyendo la respuesta tras cada uno
+	ReadSize int     `yaml:"read-size"` //lectura maxima por respuesta (0 = defaultReadSize)
+	TLS      bool    `yaml:"tls"`       //solo TCP: handshake TLS antes de enviar
+	Rules    `yaml:",inline"`
+}
+
+// datos a enviar, texto (con variables) o hex
+type Input struct {
+	Data string `yaml:"data"`
+	Type string `yaml:"type"` //"" o "hex"
+	raw  []byte
+}
+
+// request HTTP, uno por cada path
+type HTTP struct {
+	Method    string            `yaml:"method"`
+	Path      strList           `yaml:"path"` //"{{BaseURL}}/ruta" o "/ruta"
+	Headers   map[string]string `yaml:"headers"`
+	Body      string            `yaml:"body"`
+	Redirects bool              `yaml:"redirects"` //seguir redirects
+	Rules     `yaml:",inline"`
+}
+
+// matchers y extractores de un request
+type Rules struct {
+	MatchersCondition string       `yaml:"matchers-condition"` //"or" (defecto) o "and"
+	Matchers          []*Matcher   `yaml:"matchers"`
+	Extractors        []*Extractor `yaml:"extractors"`
+}
+
+// condicion sobre la respuesta
+type Matcher struct {
+	Type            string  `yaml:"type"` //word, regex, binary, status
+	Part            string  `yaml:"part"` //body (defecto), header o all; en TCP/UDP siempre la respuesta
+	Words           strList `yaml:"words"`
+	Regex           strList `yaml:"regex"`
+	Binary          strList `yaml:"binary"` //hex
+	Status          []int   `yaml:"status"`
+	Condition       string  `yaml:"condition"` //entre los valores: "or" (defecto) o "and"
+	Negative        bool    `yaml:"negative"`
+	CaseInsensitive bool    `yaml:"case-insensitive"`
+	regexps         []*regexp.Regexp
+	binary          [][]byte
+}
+
+// valores a reportar de la respuesta
+type Extractor struct {
+	Type  string  `yaml:"type"` //regex o kval
+	Part  string  `yaml:"part"`
+	Regex strList `yaml:"regex"`
+	Group int     `yaml:"group"` //grupo de la regex (0 = match completo)
+	KVal  strList `yaml:"kval"`  //headers HTTP
+	re    []*regexp.Regexp
+}
+
+// lista que acepta un string separado por comas o una secuencia YAML
+type strList []string
+
+func (l *strList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, s := range strings.Split(node.Value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				*l = append(*l, s)
+			}
+		}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// decodifica y valida un template
+func Parse(data []byte) (*Template, error) {
+	var t Template
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	if err := t.compile(); err != nil {
+		if t.ID != "" {
+			return nil, fmt.Errorf("%s: %w", t.ID, err)
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// valida los campos y precompila regex, binarios e inputs
+func (t *Template) compile() error {
+	t.ID = strings.TrimSpace(t.ID)
+	if t.ID == "" {
+		return fmt.Errorf("missing id")
+	}
+	if strings.ContainsAny(t.ID, " \t,") {
+		return fmt.Errorf("invalid id %q", t.ID)
+	}
+	if t.Info.Name == "" {
+		t.Info.Name = t.ID
+	}
+
+	sev, ok := service.ParseSeverity(t.Info.Severity)
+	if t.Info.Severity == "" {
+		sev, ok = service.SeverityInfo, true
+	}
+	if !ok {
+		return fmt.Errorf("unknown severity %q", t.Info.Severity)
+	}
+	t.severity = sev
+	for _, tag := range t.Info.Tags {
+		t.tags = append(t.tags, strings.ToLower(tag))
+	}
+
+	kinds := 0
+	for _, n := range []int{len(t.TCP), len(t.UDP), len(t.HTTP)} {
+		if n > 0 {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("exactly one of tcp, udp or http is required")
+	}
+
+	for i, r := range t.TCP {
+		if err := r.compile(); err != nil {
+			return fmt.Errorf("tcp[%d]: %w", i, err)
+		}
+	}
+	for i, r := range t.UDP {
+		if r.TLS {
+			return fmt.Errorf("udp[%d]: tls is only supported over tcp", i)
+		}
+		if len(r.Inputs) == 0 {
+			return fmt.Errorf("udp[%d]: missing inputs", i)
+		}
+		if err := r.compile(); err != nil {
+			return fmt.Errorf("udp[%d]: %w", i, err)
+		}
+	}
+	for i, r := range t.HTTP {
+		if err := r.compile(); err != nil {
+			return fmt.Errorf("http[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r *Raw) compile() error {
+	if r.ReadSize <= 0 {
+		r.ReadSize = defaultReadSize
+	}
+	for i := range r.Inputs {
+		in := &r.Inputs[i]
+		switch strings.ToLower(in.Type) {
+		case "":
+			in.raw = []byte(in.Data)
+		case "hex":
+			b, err := decodeHex(in.Data)
+			if err != nil {
+				return fmt.Errorf("inputs[%d]: %w", i, err)
+			}
+			in.raw = b
+		default:
+			return fmt.Errorf("inputs[%d]: unknown type %q", i, in.Type)
+		}
+	}
+	return r.Rules.compile(false)
+}
+
+func (r *HTTP) compile() error {
+	r.Method = strings.ToUpper(r.Method)
+	if r.Method == "" {
+		r.Method = "GET"
+	}
+	if len(r.Path) == 0 {
+		return fmt.Errorf("missing path")
+	}
+	return r.Rules.compile(true)
+}
+
+func (r *Rules) compile(http bool) error {
+	switch strings.ToLower(r.MatchersCondition) {
+	case "", "or", "and":
+	default:
+		return fmt.Errorf("unknown matchers-condition %q", r.MatchersCondition)
+	}
+	if len(r.Matchers) == 0 {
+		return fmt.Errorf("missing matchers")
+	}
+
+	for i, m := range r.Matchers {
+		if err := m.compile(http); err != nil {
+			return fmt.Errorf("matchers[%d]: %w", i, err)
+		}
+	}
+	for i, e := range r.Extractors {
+		if err := e.compile(http); err != nil {
+			return fmt.Errorf("extractors[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m *Matcher) compile(http bool) error {
+	switch strings.ToLower(m.Condition) {
+	case "", "or", "and":
+	default:
+		return fmt.Errorf("unknown condition %q", m.Condition)
+	}
+	if err := checkPart(m.Part, http); err != nil {
+		return err
+	}
+
+	switch m.Type {
+	case "word":
+		if len(m.Words) == 0 {
+			return fmt.Errorf("word matcher without words")
+		}
+	case "regex":
+		if len(m.Regex) == 0 {
+			return fmt.Errorf("regex matcher without regex")
+		}
+		for _, expr := range m.Regex {
+			if m.CaseInsensitive {
+				expr = "(?i)" + expr
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return err
+			}
+			m.regexps = append(m.regexps, re)
+		}
+	case "binary":
+		if len(m.Binary) == 0 {
+			return fmt.Errorf("binary matcher without values")
+		}
+		for _, h := range m.Binary {
+			b, err := decodeHex(h)
+			if err != nil {
+				return err
+			}
+			m.binary = append(m.binary, b)
+		}
+	case "status":
+		if !http {
+			return fmt.Errorf("status matcher is only supported over http")
+		}
+		if len(m.Status) == 0 {
+			return fmt.Errorf("status matcher without status")
+		}
+	default:
+		return fmt.Errorf("unknown matcher type %q", m.Type)
+	}
+	return nil
+}
+
+func (e *Extractor) compile(http bool) error {
+	if err := checkPart(e.Part, http); err != nil {
+		return err
+	}
+
+	switch e.Type {
+	case "regex":
+		if len(e.Regex) == 0 {
+			return fmt.Errorf("regex extractor without regex")
+		}
+		if e.Group < 0 {
+			return fmt.Errorf("invalid group %d", e.Group)
+		}
+		for _, expr := range e.Regex {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return err
+			}
+			if e.Group > re.NumSubexp() {
+				return fmt.Errorf("regex %q has no group %d", expr, e.Group)
+			}
+			e.re = append(e.re, re)
+		}
+	case "kval":
+		if !http {
+			return fmt.Errorf("kval extractor is only supported over http")
+		}
+		if len(e.KVal) == 0 {
+			return fmt.Errorf("kval extractor without keys")
+		}
+	default:
+		return fmt.Errorf("unknown extractor type %q", e.Type)
+	}
+	return nil
+}
+
+// partes validas de la respuesta
+func checkPart(part string, http bool) error {
+	switch strings.ToLower(part) {
+	case "", "body", "all":
+		return nil
+	case "header":
+		if http {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown part %q", part)
+}
+
+// hex con espacios opcionales ("0a 0b" o "0a0b")
+func decodeHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %q", s)
+	}
+	return b, nil
+}
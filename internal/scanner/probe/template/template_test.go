@@ -0,0 +1,110 @@
+package template
+
+import (
+	"bytes"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/service"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const validTemplate = `
+id: mysql-banner
+info:
+  name: MySQL banner
+  severity: Medium
+  tags: db, MySQL
+services: mysql
+ports: [3306]
+tcp:
+  - inputs:
+      - data: "{{Host}}\r\n"
+      - data: "0a 0b 0c"
+        type: hex
+    matchers:
+      - type: word
+        words: [mysql]
+`
+
+func TestParse(t *testing.T) {
+	tpl, err := Parse([]byte(validTemplate))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if tpl.ID != "mysql-banner" || tpl.severity != service.SeverityMedium {
+		t.Errorf("id %q, severity %q", tpl.ID, tpl.severity)
+	}
+	if !slices.Equal(tpl.tags, []string{"db", "mysql"}) {
+		t.Errorf("tags = %q", tpl.tags)
+	}
+	raw := tpl.TCP[0]
+	if raw.ReadSize != defaultReadSize || !bytes.Equal(raw.Inputs[1].raw, []byte{0x0a, 0x0b, 0x0c}) {
+		t.Errorf("read size %d, hex input % x", raw.ReadSize, raw.Inputs[1].raw)
+	}
+
+	spec := tpl.Spec()
+	if spec.Protocol != model.ProtocolTCP || !spec.Explicit || !slices.Equal(spec.Ports, []int{3306}) {
+		t.Errorf("spec = %+v", spec)
+	}
+	for _, group := range []string{Group, "tag:db", "tag:mysql", "severity:low", "severity:medium"} {
+		if !slices.Contains(spec.Groups, group) {
+			t.Errorf("spec groups %q without %q", spec.Groups, group)
+		}
+	}
+	if slices.Contains(spec.Groups, "severity:high") {
+		t.Errorf("spec groups %q include severity:high", spec.Groups)
+	}
+
+	//HTTP: metodo por defecto, nombre del id y servicios web
+	tpl, err = Parse([]byte("id: admin\nhttp:\n  - path: /admin\n    matchers:\n      - type: status\n        status: [200]\n"))
+	if err != nil {
+		t.Fatalf("Parse http: %v", err)
+	}
+	if tpl.HTTP[0].Method != "GET" || tpl.Info.Name != "admin" || tpl.severity != service.SeverityInfo {
+		t.Errorf("http template = %+v", tpl)
+	}
+	if spec := tpl.Spec(); !slices.Equal(spec.Services, webServices) || !slices.Equal(spec.Requires, []string{"tls", "http"}) {
+		t.Errorf("http spec = %+v", spec)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	matcher := "    matchers:\n      - type: word\n        words: [x]\n"
+
+	tests := []struct {
+		name, yaml, err string
+	}{
+		{"missing id", "tcp:\n  - inputs: [{data: x}]\n" + matcher, "missing id"},
+		{"invalid id", "id: a b\ntcp:\n  - inputs: [{data: x}]\n" + matcher, "invalid id"},
+		{"unknown field", "id: t\nfoo: 1\n", "field foo not found"},
+		{"unknown severity", "id: t\ninfo:\n  severity: urgent\ntcp:\n  - inputs: [{data: x}]\n" + matcher, "unknown severity"},
+		{"no request", "id: t\n", "exactly one of tcp, udp or http"},
+		{"two request kinds", "id: t\ntcp:\n  - inputs: [{data: x}]\n" + matcher + "udp:\n  - inputs: [{data: x}]\n" + matcher, "exactly one of tcp, udp or http"},
+		{"udp tls", "id: t\nudp:\n  - tls: true\n    inputs: [{data: x}]\n" + matcher, "udp[0]: tls is only supported over tcp"},
+		{"udp without inputs", "id: t\nudp:\n  - read-size: 10\n" + matcher, "udp[0]: missing inputs"},
+		{"bad hex input", "id: t\ntcp:\n  - inputs: [{data: zz, type: hex}]\n" + matcher, "tcp[0]: inputs[0]: invalid hex"},
+		{"unknown input type", "id: t\ntcp:\n  - inputs: [{data: x, type: base64}]\n" + matcher, "unknown type"},
+		{"http without path", "id: t\nhttp:\n  - method: get\n" + matcher, "http[0]: missing path"},
+		{"no matchers", "id: t\ntcp:\n  - inputs: [{data: x}]\n", "missing matchers"},
+		{"matchers condition", "id: t\ntcp:\n  - matchers-condition: xor\n" + matcher, "unknown matchers-condition"},
+		{"unknown matcher", "id: t\ntcp:\n  - matchers:\n      - type: dsl\n", "matchers[0]: unknown matcher type"},
+		{"matcher condition", "id: t\ntcp:\n  - matchers:\n      - type: word\n        words: [x]\n        condition: xor\n", "unknown condition"},
+		{"word without words", "id: t\ntcp:\n  - matchers:\n      - type: word\n", "word matcher without words"},
+		{"bad regex", "id: t\ntcp:\n  - matchers:\n      - type: regex\n        regex: ['(']\n", "missing closing )"},
+		{"bad binary", "id: t\ntcp:\n  - matchers:\n      - type: binary\n        binary: ['0g']\n", "invalid hex"},
+		{"status over tcp", "id: t\ntcp:\n  - matchers:\n      - type: status\n        status: [200]\n", "status matcher is only supported over http"},
+		{"header part over tcp", "id: t\ntcp:\n  - matchers:\n      - type: word\n        part: header\n        words: [x]\n", "unknown part"},
+		{"negative regex group", "id: t\nhttp:\n  - path: /\n" + matcher + "    extractors:\n      - type: regex\n        regex: ['a(b)']\n        group: -1\n", "extractors[0]: invalid group -1"},
+		{"regex group out of range", "id: t\nhttp:\n  - path: /\n" + matcher + "    extractors:\n      - type: regex\n        regex: ['a(b)']\n        group: 2\n", "has no group 2"},
+		{"kval over tcp", "id: t\ntcp:\n  - inputs: [{data: x}]\n" + matcher + "    extractors:\n      - type: kval\n        kval: [server]\n", "kval extractor is only supported over http"},
+		{"unknown extractor", "id: t\nhttp:\n  - path: /\n" + matcher + "    extractors:\n      - type: json\n", "extractors[0]: unknown extractor type"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.yaml))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: Parse error = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package tlsprobe
+
+//PROBER TLS -> handshake, cipher, ALPN, versiones aceptadas y certificado
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/probe"
+	"go-scanner/internal/scanner/service"
+	"net"
+	"time"
+)
+
+// versiones sondeadas de forma individual (la mas nueva primero)
+var versions = []uint16{
+	tls.VersionTLS13,
+	tls.VersionTLS12,
+	tls.VersionTLS11,
+	tls.VersionTLS10,
+}
+
+// protocolos ofrecidos por ALPN
+var alpnProtocols = []string{"h2", "http/1.1"}
+
+// prober TLS para cualquier puerto
+type TLSProbe struct{}
+
+// nueva instancia del prober
+func NewTLSProbe() *TLSProbe {
+	return &TLSProbe{}
+}
+
+// cualquier puerto TCP abierto, antes que los probers que dependen de TLS (http, jarm)
+func (p *TLSProbe) Spec() probe.Spec {
+	return probe.Spec{Name: "tls", Protocol: model.ProtocolTCP}
+}
+
+// no se intenta si el servicio ya hablo en claro (banner)
+func (p *TLSProbe) Match(t probe.Target) bool {
+	return t.Service.Method != service.MethodBanner
+}
+
+// establece una conexion TLS con la config dada (handshake completo)
+// permite reutilizar el analisis tras un upgrade STARTTLS
+type Connector func(config *tls.Config) (*tls.Conn, error)
+
+// realiza el handshake, si el servicio no habla TLS retorna error
+func (p *TLSProbe) Probe(ctx context.Context, t probe.Target, opts probe.Options) (*service.ServiceInfo, error) {
+	details, err := Inspect(opts, sniName(t), func(config *tls.Config) (*tls.Conn, error) {
+		return opts.DialTLS(ctx, t.Address(), config)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := &service.ServiceInfo{
+		Method:     service.MethodProbe,
+		TLS:        true,
+		TLSInfo:    details,
+		Confidence: model.ConfidenceHigh,
+	}
+
+	//ALPN HTTP negociado: es HTTPS aunque el puerto diga otra cosa
+	if details.ALPN == "h2" || details.ALPN == "http/1.1" {
+		info.Type = service.ServiceHTTPS
+	}
+	return info, nil
+}
+
+// analiza el TLS de un servicio: handshake negociado, versiones aceptadas y certificado
+// los handshakes parten de la configuracion TLS de la campaña
+func Inspect(opts probe.Options, target string, connect Connector) (*service.TLSInfo, error) {
+	state, err := handshake(opts, connect, serverName(target), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &service.TLSInfo{
+		Version: tls.VersionName(state.Version),
+		Cipher:  tls.CipherSuiteName(state.CipherSuite),
+		ALPN:    state.NegotiatedProtocol,
+	}
+	if len(state.PeerCertificates) > 0 {
+		details.Certificate = Certificate(state.PeerCertificates[0], time.Now())
+	}
+
+	//cada version por separado, asi se detectan TLS 1.0/1.1 aunque se negocie 1.3
+	for _, v := range versions {
+		accepted := v == state.Version
+		if !accepted {
+			_, err := handshake(opts, connect, serverName(target), v)
+			accepted = err == nil
+		}
+		details.Versions = append(details.Versions, service.TLSVersion{
+			Name:     tls.VersionName(v),
+			Accepted: accepted,
+		})
+	}
+
+	return details, nil
+}
+
+// handshake mediante el connector, version 0 deja que se negocie la mejor
+func handshake(opts probe.Options, connect Connector, sni string, version uint16) (tls.ConnectionState, error) {
+	config := opts.TLSConfig(sni)
+	config.NextProtos = alpnProtocols
+	config.MinVersion = tls.VersionTLS10
+	config.CipherSuites = allCipherSuites()
+	if version != 0 {
+		config.MinVersion = version
+		config.MaxVersion = version
+	}
+
+	conn, err := connect(config)
+	if err != nil {
+		return tls.ConnectionState{}, fmt.Errorf("tls handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.ConnectionState(), nil
+}
+
+// todas las suites soportadas por Go, incluidas las inseguras (servidores viejos)
+func allCipherSuites() []uint16 {
+	var ids []uint16
+	for _, s := range tls.CipherSuites() {
+		ids = append(ids, s.ID)
+	}
+	for _, s := range tls.InsecureCipherSuites() {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+// SNI solo para nombres, no se envia para IPs
+func serverName(target string) string {
+	if net.ParseIP(target) != nil {
+		return ""
+	}
+	return target
+}
+
+// nombre para el SNI: el hostname del target si se conoce, si no lo que se conecta
+func sniName(t probe.Target) string {
+	if name := t.Hostname(); name != "" {
+		return name
+	}
+	return t.Host
+}
+
+// extrae los datos relevantes de un certificado, now define si esta expirado
+func Certificate(cert *x509.Certificate, now time.Time) *service.CertificateInfo {
+	info := &service.CertificateInfo{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+		Expired:   now.After(cert.NotAfter) || now.Before(cert.NotBefore),
+	}
+
+	info.SANs = append(info.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		info.KeyType, info.KeyBits = "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		info.KeyType, info.KeyBits = "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		info.KeyType, info.KeyBits = "Ed25519", 256
+	default:
+		info.KeyType = cert.PublicKeyAlgorithm.String()
+	}
+
+	//autofirmado: mismo sujeto y emisor, y la firma valida con su propia clave
+	if cert.Subject.String() == cert.Issuer.String() {
+		info.SelfSigned = cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+	info.SHA256 = hex.EncodeToString(sum[:])
+
+	return info
+}
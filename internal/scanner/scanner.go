@@ -3,6 +3,7 @@ package scanner
 import (
 	"fmt"
 	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/service"
 )
 
 // ESTADO DEL PORT
@@ -18,11 +19,14 @@ const (
 type ScanResult struct {
 	Host     string //IP o hostname
 	Port     int
-	State    PortState // Estado explicito del puerto
-	Service  string    //nombre del servicio
-	Banner   string    //banner capturado
+	Protocol model.Protocol //protocolo de transporte (tcp, udp)
+	State    PortState      // Estado explicito del puerto
+	Service  string         //nombre del servicio
+	Banner   string         //banner capturado (crudo)
 	Error    error
 	Metadata *model.HostMetadata //contexto del host discovery
+
+	ServiceInfo *service.ServiceInfo //fingerprint estructurado (producto, version, CPE...)
 }
 
 // IsOpen helper
@@ -30,9 +34,40 @@ func (r ScanResult) IsOpen() bool {
 	return r.State == PortStateOpen
 }
 
+// identifica un resultado de manera univoca: TCP 53 y UDP 53 son puertos distintos
+type ResultKey struct {
+	Host     string
+	Protocol model.Protocol
+	Port     int
+}
+
+// clave (host, proto, port) del resultado
+func (r ScanResult) Key() ResultKey {
+	return ResultKey{Host: r.Host, Protocol: r.Protocol, Port: r.Port}
+}
+
+// puerto con su protocolo, ej. "53/udp"
+func (r ScanResult) PortLabel() string {
+	if r.Protocol == "" {
+		return fmt.Sprintf("%d", r.Port)
+	}
+	return fmt.Sprintf("%d/%s", r.Port, r.Protocol)
+}
+
 // representacion bonita del resultado
 func (r ScanResult) String() string {
-	return fmt.Sprintf("[%s] Port %d: %s", r.Host, r.Port, r.State)
+	return fmt.Sprintf("[%s] Port %s: %s", r.Host, r.PortLabel(), r.State)
+}
+
+// ordena por (host, port, proto) de forma estable para los reportes
+func Less(a, b ScanResult) bool {
+	if a.Host != b.Host {
+		return a.Host < b.Host
+	}
+	if a.Port != b.Port {
+		return a.Port < b.Port
+	}
+	return a.Protocol < b.Protocol
 }
 
 // define el contrato para cualquier tipo de escaner
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"go-scanner/internal/model"
+	"regexp"
+	"strings"
+)
+
+//PARSEO DE BANNERS -> producto, version y CPE
+
+var (
+	//SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.6
+	sshBannerRe = regexp.MustCompile(`^SSH-([\d.]+)-([^\s_-]+)(?:[_-]([^\s]+))?(?:\s+(.*))?`)
+
+	//220 (vsFTPd 3.0.3) / 220 ProFTPD 1.3.5e Server
+	vsftpdRe  = regexp.MustCompile(`(?i)\(vsFTPd ([\w.]+)\)`)
+	proftpdRe = regexp.MustCompile(`(?i)ProFTPD ([\w.]+)`)
+	filezRe   = regexp.MustCompile(`(?i)FileZilla Server(?: version)? ([\w.]+)`)
+
+	//220 mail.example.com ESMTP Postfix (Ubuntu) / Exim 4.94.2
+	postfixRe = regexp.MustCompile(`(?i)ESMTP Postfix(?: \(([^)]+)\))?`)
+	eximRe    = regexp.MustCompile(`(?i)ESMTP Exim ([\w.]+)`)
+	msSMTPRe  = regexp.MustCompile(`(?i)Microsoft ESMTP MAIL Service(?:, Version: ([\d.]+))?`)
+
+	//+OK Dovecot ready. / * OK [CAPABILITY ...] Dovecot ready.
+	dovecotRe = regexp.MustCompile(`(?i)Dovecot(?: \(([^)]+)\))? ready`)
+)
+
+// intenta identificar el servicio a partir de lo que dice el propio servicio
+func ParseBanner(banner string) (ServiceInfo, bool) {
+	banner = strings.TrimSpace(banner)
+	lower := strings.ToLower(banner)
+
+	switch {
+	case strings.HasPrefix(lower, "ssh-"):
+		return parseSSH(banner), true
+
+	case strings.HasPrefix(lower, "220"):
+		//FTP y SMTP empiezan en 220 normalmente, asi que...
+		if strings.Contains(lower, "ftp") {
+			return parseFTP(banner), true
+		}
+		if strings.Contains(lower, "smtp") || strings.Contains(lower, "mail") {
+			return parseSMTP(banner), true
+		}
+
+	case strings.HasPrefix(lower, "+ok"):
+		return parseMail(ServicePOP3, banner), true
+
+	case strings.HasPrefix(lower, "* ok"):
+		return parseMail(ServiceIMAP, banner), true
+
+	case strings.HasPrefix(banner, "RFB "):
+		//VNC anuncia la version del protocolo al conectar
+		return bannerInfo(ServiceVNC), true
+	}
+
+	return ServiceInfo{}, false
+}
+
+func parseSSH(banner string) ServiceInfo {
+	info := bannerInfo(ServiceSSH)
+
+	m := sshBannerRe.FindStringSubmatch(banner)
+	if m == nil {
+		return info
+	}
+
+	info.Product = m[2]
+	info.Version = m[3]
+	extra := []string{"protocol " + m[1]}
+	if m[4] != "" {
+		extra = append([]string{m[4]}, extra...)
+		info.OSHint = osFromText(m[4])
+	}
+	info.ExtraInfo = strings.Join(extra, "; ")
+
+	if strings.EqualFold(info.Product, "OpenSSH") {
+		info.Product = "OpenSSH"
+		info.CPE = append(info.CPE, cpe("openbsd", "openssh", info.Version))
+	} else if strings.EqualFold(info.Product, "dropbear") {
+		info.Product = "Dropbear sshd"
+		info.CPE = append(info.CPE, cpe("matt_johnston", "dropbear_ssh_server", info.Version))
+	}
+	return info
+}
+
+func parseFTP(banner string) ServiceInfo {
+	info := bannerInfo(ServiceFTP)
+
+	if m := vsftpdRe.FindStringSubmatch(banner); m != nil {
+		info.Product, info.Version = "vsftpd", m[1]
+		info.CPE = append(info.CPE, cpe("beasts", "vsftpd", m[1]))
+	} else if m := proftpdRe.FindStringSubmatch(banner); m != nil {
+		info.Product, info.Version = "ProFTPD", m[1]
+		info.CPE = append(info.CPE, cpe("proftpd", "proftpd", m[1]))
+	} else if m := filezRe.FindStringSubmatch(banner); m != nil {
+		info.Product, info.Version = "FileZilla ftpd", m[1]
+		info.OSHint = "Windows"
+		info.CPE = append(info.CPE, cpe("filezilla-project", "filezilla_server", m[1]))
+	}
+	return info
+}
+
+func parseSMTP(banner string) ServiceInfo {
+	info := bannerInfo(ServiceSMTP)
+
+	if m := postfixRe.FindStringSubmatch(banner); m != nil {
+		info.Product = "Postfix smtpd"
+		info.CPE = append(info.CPE, cpe("postfix", "postfix", ""))
+		if m[1] != "" {
+			info.OSHint = osFromText(m[1])
+		}
+	} else if m := eximRe.FindStringSubmatch(banner); m != nil {
+		info.Product, info.Version = "Exim smtpd", m[1]
+		info.CPE = append(info.CPE, cpe("exim", "exim", m[1]))
+	} else if m := msSMTPRe.FindStringSubmatch(banner); m != nil {
+		info.Product, info.Version = "Microsoft ESMTP", m[1]
+		info.OSHint = "Windows"
+		info.CPE = append(info.CPE, "cpe:/o:microsoft:windows")
+	}
+	return info
+}
+
+func parseMail(svc ServiceType, banner string) ServiceInfo {
+	info := bannerInfo(svc)
+
+	if m := dovecotRe.FindStringSubmatch(banner); m != nil {
+		if svc == ServicePOP3 {
+			info.Product = "Dovecot pop3d"
+		} else {
+			info.Product = "Dovecot imapd"
+		}
+		info.CPE = append(info.CPE, cpe("dovecot", "dovecot", ""))
+		if m[1] != "" {
+			info.OSHint = osFromText(m[1])
+		}
+	}
+	return info
+}
+
+// informacion base de una identificacion por banner
+func bannerInfo(svc ServiceType) ServiceInfo {
+	return ServiceInfo{
+		Type:       svc,
+		Method:     MethodBanner,
+		Confidence: model.ConfidenceHigh,
+	}
+}
+
+// sugerencia de sistema operativo a partir de un texto libre del banner
+func osFromText(text string) string {
+	lower := strings.ToLower(text)
+	for _, hint := range []struct{ key, os string }{
+		{"ubuntu", "Linux"},
+		{"debian", "Linux"},
+		{"centos", "Linux"},
+		{"red hat", "Linux"},
+		{"fedora", "Linux"},
+		{"freebsd", "FreeBSD"},
+		{"openbsd", "OpenBSD"},
+		{"windows", "Windows"},
+		{"win32", "Windows"},
+	} {
+		if strings.Contains(lower, hint.key) {
+			return hint.os
+		}
+	}
+	return ""
+}
+
+// arma un CPE 2.2 de aplicacion, sin version si no se conoce
+func cpe(vendor, product, version string) string {
+	c := "cpe:/a:" + vendor + ":" + product
+	if version != "" {
+		c += ":" + strings.ToLower(version)
+	}
+	return c
+}
@@ -0,0 +1,11 @@
+package service
+
+//DETALLES DE BASES DE DATOS -> handshake inicial, sin credenciales
+
+// lo que el servidor revela antes de autenticar
+type DatabaseInfo struct {
+	Protocol     string //version del protocolo (MySQL 10, PostgreSQL 3.0, TDS, wire 17, RESP)
+	AuthRequired bool   //el servidor exige credenciales antes de operar
+	AuthMethod   string //mecanismo anunciado (mysql_native_password, SCRAM-SHA-256, ...)
+	Encryption   string //postura de cifrado anunciada (MSSQL: off, on, required, unsupported)
+}
@@ -0,0 +1,19 @@
+package service
+
+//DETALLES DE DNS -> identidad (CHAOS), recursion y transferencias de zona
+
+// respuestas del servidor DNS a consultas de solo lectura
+type DNSInfo struct {
+	Transport     string         //"udp" o "tcp"
+	VersionBind   string         //TXT de version.bind (CHAOS)
+	IDServer      string         //TXT de id.server (CHAOS), identifica la instancia
+	Recursion     bool           //resolvio un nombre externo (resolver abierto)
+	ZoneTransfers []ZoneTransfer //AXFR de las zonas pedidas por el usuario
+}
+
+// resultado de un AXFR
+type ZoneTransfer struct {
+	Zone    string
+	Allowed bool //el servidor entrego la zona
+	Records int  //registros recibidos (incluye los dos SOA)
+}
@@ -0,0 +1,51 @@
+package service
+
+//HALLAZGOS -> resultados de checks declarativos (templates) sobre un servicio
+import "strings"
+
+// severidad de un hallazgo
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severidades en orden creciente
+var Severities = []Severity{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// convierte un nombre en Severity, false si no es una severidad conocida
+func ParseSeverity(name string) (Severity, bool) {
+	s := Severity(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range Severities {
+		if s == known {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// orden de la severidad (info = 1, critical = 5, desconocida = 0)
+func (s Severity) Rank() int {
+	for i, known := range Severities {
+		if s == known {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// hallazgo reportado por un check sobre el puerto
+type Finding struct {
+	ID          string   //id del check (template)
+	Name        string   //nombre legible
+	Severity    Severity //severidad declarada por el check
+	Description string   //descripcion del check
+	Tags        []string //tags del check
+	Source      string   //origen: "template", ...
+	Matched     string   //lo que se consulto (URL o puerto) cuando hubo match
+	Extracted   []string //valores extraidos de la respuesta
+}
@@ -0,0 +1,50 @@
+package service
+
+//DETALLES DE HTTP -> pagina, redirects, headers de seguridad y tecnologias
+
+// resultado del analisis HTTP profundo
+type HTTPInfo struct {
+	Status          int          //codigo de la respuesta final
+	Title           string       //<title> de la pagina
+	FinalURL        string       //URL despues de seguir los redirects
+	Redirects       []string     //cadena de Location seguida
+	SecurityHeaders []string     //headers de seguridad presentes
+	MissingHeaders  []string     //headers de seguridad ausentes
+	Cookies         []Cookie     //cookies y sus flags
+	FaviconHash     *int32       //mmh3 del favicon (compatible con http.favicon.hash de Shodan)
+	Technologies    []Technology //tecnologias detectadas por firmas
+}
+
+// flags de una cookie
+type Cookie struct {
+	Name     string
+	Secure   bool
+	HttpOnly bool
+	SameSite string //Strict, Lax, None o vacio
+}
+
+// tecnologia identificada por una firma
+type Technology struct {
+	Name     string
+	Category string //framework, cms, panel, server, ...
+	Version  string
+}
+
+// respuesta de un virtual host, comparada contra la del host por defecto (sin nombre)
+type VHostResponse struct {
+	Host     string //Host/SNI enviado, vacio para el host por defecto
+	Status   int
+	Title    string
+	Location string //redirect, si lo hubo
+	Length   int    //bytes del body leidos
+	Distinct bool   //difiere materialmente de la respuesta por defecto
+}
+
+// protocolos y upgrades soportados por un servicio web
+type WebCapabilities struct {
+	H2        bool     //HTTP/2 negociado por ALPN (TLS)
+	H2C       bool     //acepta Upgrade: h2c (texto plano)
+	H3        bool     //anuncia HTTP/3 en Alt-Svc (sin confirmar por QUIC)
+	AltSvc    []string //entradas de Alt-Svc ("h3=\":443\"; ma=86400")
+	WebSocket []string //paths que aceptaron el upgrade a WebSocket
+}
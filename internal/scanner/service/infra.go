@@ -0,0 +1,54 @@
+package service
+
+//DETALLES DE PROTOCOLOS DE INFRAESTRUCTURA -> NTP, SIP, LDAP, Kerberos, MQTT y AMQP
+
+// respuesta de modo 3 (cliente), variables de modo 6 y exposicion de monlist (modo 7)
+type NTPInfo struct {
+	Version int    //version NTP de la respuesta
+	Stratum int    //0 sin sincronizar, 1 referencia primaria
+	RefID   string //reloj de referencia ("GPS") o IP del servidor superior
+	System  string //variable "system" de READVAR (modo 6)
+	Daemon  string //variable "version" de READVAR
+	Monlist bool   //responde MON_GETLIST (amplificacion, CVE-2013-5211)
+}
+
+// respuesta a OPTIONS
+type SIPInfo struct {
+	Status string   //linea de estado ("200 OK")
+	Server string   //cabecera Server o User-Agent
+	Allow  []string //metodos anunciados
+}
+
+// atributos publicos del rootDSE
+type LDAPInfo struct {
+	NamingContexts       []string
+	DefaultNamingContext string
+	DNSHostName          string //Active Directory
+	VendorName           string
+	VendorVersion        string
+	LDAPVersions         []string //supportedLDAPVersion
+	SASLMechanisms       []string //supportedSASLMechanisms
+}
+
+// respuesta del KDC a un AS-REQ de un usuario inexistente
+type KerberosInfo struct {
+	Realm      string //realm confirmado por el KDC (vacio si no se confirmo ninguno)
+	ErrorCode  int    //codigo del KRB-ERROR
+	ErrorName  string //nombre del codigo (KDC_ERR_C_PRINCIPAL_UNKNOWN, ...)
+	ServerTime string //stime del KRB-ERROR (UTC)
+}
+
+// resultado de un CONNECT sin usuario ni clave
+type MQTTInfo struct {
+	ProtocolLevel int  //4 = MQTT 3.1.1
+	ReturnCode    int  //codigo del CONNACK
+	Anonymous     bool //el broker acepto la conexion sin credenciales
+}
+
+// Connection.Start de AMQP 0-9-1 o cabecera de protocolo anunciada por el servidor
+type AMQPInfo struct {
+	Protocol   string   //"0-9-1", "1.0.0"
+	Platform   string   //server-properties.platform
+	Cluster    string   //server-properties.cluster_name
+	Mechanisms []string //mecanismos SASL ofrecidos
+}
@@ -0,0 +1,17 @@
+package service
+
+//DETALLES DE PROTOCOLOS INDUSTRIALES -> Modbus, S7, BACnet, DNP3 y EtherNet/IP (solo identificacion)
+
+// identidad que el dispositivo reporta sin que se lea ni escriba el proceso
+type OTInfo struct {
+	Protocol   string //"Modbus", "S7comm", "BACnet", "DNP3", "EtherNet/IP"
+	Vendor     string //fabricante reportado por el dispositivo
+	VendorID   int    //codigo de fabricante (BACnet, CIP); 0 si no aplica
+	Product    string //nombre o tipo de producto
+	Model      string //modelo u order number
+	Revision   string //firmware o revision
+	Serial     string //numero de serie
+	Name       string //nombre de la estacion, modulo u objeto
+	Address    string //unit id (Modbus), direccion de enlace (DNP3), instancia del device (BACnet)
+	DeviceType string //tipo de dispositivo CIP
+}
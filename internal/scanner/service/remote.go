@@ -0,0 +1,17 @@
+package service
+
+//DETALLES DE ESCRITORIO REMOTO -> protocolos de seguridad RDP y handshake RFB (VNC)
+
+// protocolos de seguridad aceptados en la negociacion X.224 de RDP
+type RDPInfo struct {
+	Protocols   []string  //protocolos aceptados ("RDP", "TLS", "CredSSP")
+	NLARequired bool      //el servidor rechaza conexiones sin CredSSP (NLA)
+	NTLM        *NTLMInfo //target info del challenge NTLM de CredSSP (si hubo)
+}
+
+// handshake inicial de VNC
+type VNCInfo struct {
+	Version       string   //version RFB anunciada ("3.8")
+	SecurityTypes []string //tipos de seguridad ofrecidos
+	NoAuth        bool     //ofrece el tipo "None" (acceso sin clave)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/portdb"
 	"strings"
 )
 
@@ -17,6 +19,37 @@ const (
 	ServicePOP3    ServiceType = "POP3"
 	ServiceIMAP    ServiceType = "IMAP"
 	ServiceDNS     ServiceType = "DNS"
+	ServiceSNMP    ServiceType = "SNMP"
+
+	//bases de datos
+	ServiceMySQL      ServiceType = "MySQL"
+	ServicePostgreSQL ServiceType = "PostgreSQL"
+	ServiceMSSQL      ServiceType = "MSSQL"
+	ServiceMongoDB    ServiceType = "MongoDB"
+	ServiceRedis      ServiceType = "Redis"
+
+	//redes Windows
+	ServiceSMB     ServiceType = "SMB"
+	ServiceNetBIOS ServiceType = "NetBIOS-NS"
+
+	//infraestructura
+	ServiceNTP      ServiceType = "NTP"
+	ServiceSIP      ServiceType = "SIP"
+	ServiceLDAP     ServiceType = "LDAP"
+	ServiceKerberos ServiceType = "Kerberos"
+	ServiceMQTT     ServiceType = "MQTT"
+	ServiceAMQP     ServiceType = "AMQP"
+
+	//escritorio remoto
+	ServiceRDP ServiceType = "RDP"
+	ServiceVNC ServiceType = "VNC"
+
+	//industriales (OT)
+	ServiceModbus     ServiceType = "Modbus"
+	ServiceS7         ServiceType = "S7"
+	ServiceBACnet     ServiceType = "BACnet"
+	ServiceDNP3       ServiceType = "DNP3"
+	ServiceEtherNetIP ServiceType = "EtherNet-IP"
 )
 
 // como fue detectado el servicio
@@ -28,51 +61,325 @@ const (
 	MethodNone   Method = "none"
 )
 
-// contiene la informacion del servicio detectado
+// MethodProbe: identificado por la respuesta a un probe activo
+const MethodProbe Method = "probe"
+
+// contiene la informacion del servicio detectado (fingerprint estructurado)
 type ServiceInfo struct {
-	Type   ServiceType
-	Method Method
+	Type       ServiceType           //nombre del servicio (SSH, HTTP, ...)
+	Method     Method                //como fue detectado
+	Product    string                //producto (OpenSSH, nginx, ...)
+	Version    string                //version del producto
+	ExtraInfo  string                //informacion adicional (distro, protocolo, ...)
+	Hostname   string                //hostname anunciado por el servicio
+	OSHint     string                //sistema operativo sugerido por el servicio
+	DeviceType string                //tipo de dispositivo (router, printer, ...)
+	CPE        []string              //identificadores CPE (cpe:/a:vendor:product:version)
+	TLS        bool                  //el servicio habla TLS
+	Confidence model.ConfidenceLevel //confianza de la identificacion
+
+	//detalle por protocolo (solo si el prober correspondiente se ejecuto)
+	TLSInfo        *TLSInfo         //handshake y certificado
+	StartTLS       *StartTLSInfo    //upgrade desde texto plano (protocolos en claro)
+	TLSFingerprint *TLSFingerprint  //JARM / JA3S del stack TLS
+	SSH            *SSHInfo         //algoritmos y claves de host SSH
+	HTTP           *HTTPInfo        //analisis de la pagina web
+	VHosts         []VHostResponse  //respuestas por virtual host en el mismo IP:puerto
+	Web            *WebCapabilities //h2, h2c, HTTP/3 (Alt-Svc) y WebSocket
+	Database       *DatabaseInfo    //handshake inicial de bases de datos
+	SMB            *SMBInfo         //NEGOTIATE SMB y challenge NTLMSSP
+	NetBIOS        *NetBIOSInfo     //tabla de nombres NetBIOS (NBSTAT)
+	RDP            *RDPInfo         //negociacion de seguridad RDP
+	VNC            *VNCInfo         //version RFB y tipos de seguridad
+	DNS            *DNSInfo         //CHAOS, recursion y AXFR
+	SNMP           *SNMPInfo        //grupo system y engine SNMPv3
+	NTP            *NTPInfo         //estado del servidor y monlist
+	SIP            *SIPInfo         //respuesta a OPTIONS
+	LDAP           *LDAPInfo        //rootDSE
+	Kerberos       *KerberosInfo    //realm y error del KDC
+	MQTT           *MQTTInfo        //CONNECT anonimo
+	AMQP           *AMQPInfo        //propiedades del broker
+	OT             *OTInfo          //identidad de dispositivos industriales
+
+	Findings []Finding       //hallazgos de checks declarativos (templates, scripts)
+	Vulns    []Vulnerability //CVEs del feed local para el producto/version
 }
 
-// mapa de puertos comunes por defecto
-var commonPorts = map[int]ServiceType{
-	21:  ServiceFTP,
-	22:  ServiceSSH,
-	25:  ServiceSMTP,
-	53:  ServiceDNS,
-	80:  ServiceHTTP,
-	110: ServicePOP3,
-	143: ServiceIMAP,
-	443: ServiceHTTPS,
-	465: ServiceSMTP, //SMTPS
-	993: ServiceIMAP, //IMAPS
-	995: ServicePOP3, //POP3S
+// resumen legible: "OpenSSH 8.9p1 (Ubuntu Linux; protocol 2.0)"
+func (i *ServiceInfo) Summary() string {
+	if i == nil {
+		return ""
+	}
+
+	var parts []string
+	if i.Product != "" {
+		parts = append(parts, i.Product)
+	}
+	if i.Version != "" {
+		parts = append(parts, i.Version)
+	}
+	if i.ExtraInfo != "" {
+		parts = append(parts, "("+i.ExtraInfo+")")
+	}
+	return strings.Join(parts, " ")
 }
 
-// inferir el servicio a raiz del puerto y banner
-func Detect(port int, banner string) ServiceInfo {
-	//mediante banner
-	if banner != "" {
-		lowerBanner := strings.ToLower(banner)
-		if strings.HasPrefix(lowerBanner, "ssh-") {
-			return ServiceInfo{Type: ServiceSSH, Method: MethodBanner}
+// completa la informacion con lo aportado por otra deteccion (ej. un prober)
+// los campos ya conocidos solo se reemplazan si la otra deteccion tiene mas confianza
+func (i *ServiceInfo) Merge(other *ServiceInfo) {
+	if other == nil {
+		return
+	}
+
+	override := confidenceRank(other.Confidence) > confidenceRank(i.Confidence)
+	conflict := differs(i.Product, other.Product) || differs(i.Version, other.Version)
+
+	pick := func(dst *string, src string) {
+		if src != "" && (*dst == "" || override) {
+			*dst = src
 		}
-		if strings.HasPrefix(lowerBanner, "220 ") {
-			//FTP y SMTP empiezan en 220 normalmente, asi que...
-			if strings.Contains(lowerBanner, "ftp") {
-				return ServiceInfo{Type: ServiceFTP, Method: MethodBanner}
-			}
-			if strings.Contains(lowerBanner, "smtp") || strings.Contains(lowerBanner, "mail") {
-				return ServiceInfo{Type: ServiceSMTP, Method: MethodBanner}
+	}
+
+	if other.Type != "" && other.Type != ServiceUnknown && (i.Type == "" || i.Type == ServiceUnknown || override) {
+		i.Type = other.Type
+		i.Method = other.Method
+	}
+	pick(&i.Product, other.Product)
+	pick(&i.Version, other.Version)
+	pick(&i.ExtraInfo, other.ExtraInfo)
+	pick(&i.Hostname, other.Hostname)
+	pick(&i.OSHint, other.OSHint)
+	pick(&i.DeviceType, other.DeviceType)
+
+	//si producto/version no coinciden solo quedan los CPEs de la deteccion que los define
+	//(un CPE con otra version haria reportar CVEs de una version que no corre)
+	if conflict && override {
+		i.CPE = nil
+	}
+	if !conflict || override {
+		for _, c := range other.CPE {
+			if !containsString(i.CPE, c) {
+				i.CPE = append(i.CPE, c)
 			}
 		}
 	}
 
+	i.TLS = i.TLS || other.TLS
+	if other.TLSInfo != nil && (i.TLSInfo == nil || override) {
+		i.TLSInfo = other.TLSInfo
+	}
+	if other.StartTLS != nil && (i.StartTLS == nil || override) {
+		i.StartTLS = other.StartTLS
+	}
+	if other.TLSFingerprint != nil && (i.TLSFingerprint == nil || override) {
+		i.TLSFingerprint = other.TLSFingerprint
+	}
+	if other.SSH != nil && (i.SSH == nil || override) {
+		i.SSH = other.SSH
+	}
+	if other.HTTP != nil && (i.HTTP == nil || override) {
+		i.HTTP = other.HTTP
+	}
+	if other.VHosts != nil && (i.VHosts == nil || override) {
+		i.VHosts = other.VHosts
+	}
+	if other.Web != nil && (i.Web == nil || override) {
+		i.Web = other.Web
+	}
+	if other.Database != nil && (i.Database == nil || override) {
+		i.Database = other.Database
+	}
+	if other.SMB != nil && (i.SMB == nil || override) {
+		i.SMB = other.SMB
+	}
+	if other.NetBIOS != nil && (i.NetBIOS == nil || override) {
+		i.NetBIOS = other.NetBIOS
+	}
+	if other.RDP != nil && (i.RDP == nil || override) {
+		i.RDP = other.RDP
+	}
+	if other.VNC != nil && (i.VNC == nil || override) {
+		i.VNC = other.VNC
+	}
+	if other.DNS != nil && (i.DNS == nil || override) {
+		i.DNS = other.DNS
+	}
+	if other.SNMP != nil && (i.SNMP == nil || override) {
+		i.SNMP = other.SNMP
+	}
+	if other.NTP != nil && (i.NTP == nil || override) {
+		i.NTP = other.NTP
+	}
+	if other.SIP != nil && (i.SIP == nil || override) {
+		i.SIP = other.SIP
+	}
+	if other.LDAP != nil && (i.LDAP == nil || override) {
+		i.LDAP = other.LDAP
+	}
+	if other.Kerberos != nil && (i.Kerberos == nil || override) {
+		i.Kerberos = other.Kerberos
+	}
+	if other.MQTT != nil && (i.MQTT == nil || override) {
+		i.MQTT = other.MQTT
+	}
+	if other.AMQP != nil && (i.AMQP == nil || override) {
+		i.AMQP = other.AMQP
+	}
+	if other.OT != nil && (i.OT == nil || override) {
+		i.OT = other.OT
+	}
+	for _, f := range other.Findings {
+		if !containsFinding(i.Findings, f) {
+			i.Findings = append(i.Findings, f)
+		}
+	}
+	for _, v := range other.Vulns {
+		if !containsVuln(i.Vulns, v.ID) {
+			i.Vulns = append(i.Vulns, v)
+		}
+	}
+	if override {
+		i.Confidence = other.Confidence
+	}
+}
+
+func confidenceRank(c model.ConfidenceLevel) int {
+	switch c {
+	case model.ConfidenceHigh:
+		return 3
+	case model.ConfidenceMedium:
+		return 2
+	case model.ConfidenceLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ambos conocidos y distintos
+func differs(a, b string) bool {
+	return a != "" && b != "" && !strings.EqualFold(a, b)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// mismo check sobre lo mismo (id y lo consultado)
+func containsFinding(list []Finding, f Finding) bool {
+	for _, v := range list {
+		if v.ID == f.ID && v.Matched == f.Matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVuln(list []Vulnerability, id string) bool {
+	for _, v := range list {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// nombres de la tabla de puertos que corresponden a un ServiceType conocido
+var aliases = map[string]ServiceType{
+	"http":          ServiceHTTP,
+	"http-alt":      ServiceHTTP,
+	"http-proxy":    ServiceHTTP,
+	"https":         ServiceHTTPS,
+	"https-alt":     ServiceHTTPS,
+	"ssh":           ServiceSSH,
+	"ftp":           ServiceFTP,
+	"smtp":          ServiceSMTP,
+	"smtps":         ServiceSMTP, //SMTPS
+	"submission":    ServiceSMTP,
+	"pop3":          ServicePOP3,
+	"pop3s":         ServicePOP3, //POP3S
+	"imap":          ServiceIMAP,
+	"imaps":         ServiceIMAP, //IMAPS
+	"dns":           ServiceDNS,
+	"domain":        ServiceDNS, //nombre usado por nmap
+	"snmp":          ServiceSNMP,
+	"ntp":           ServiceNTP,
+	"sip":           ServiceSIP,
+	"ldap":          ServiceLDAP,
+	"globalcatldap": ServiceLDAP, //catalogo global de Active Directory
+	"kerberos-sec":  ServiceKerberos,
+	"kerberos":      ServiceKerberos,
+	"mqtt":          ServiceMQTT,
+	"amqp":          ServiceAMQP,
+	"mysql":         ServiceMySQL,
+	"postgresql":    ServicePostgreSQL,
+	"postgres":      ServicePostgreSQL,
+	"ms-sql-s":      ServiceMSSQL,
+	"mssql":         ServiceMSSQL,
+	"mongodb":       ServiceMongoDB,
+	"mongod":        ServiceMongoDB,
+	"redis":         ServiceRedis,
+	"smb":           ServiceSMB,
+	"microsoft-ds":  ServiceSMB,
+	"netbios-ssn":   ServiceSMB, //SMB sobre NetBIOS (139)
+	"netbios-ns":    ServiceNetBIOS,
+	"rdp":           ServiceRDP,
+	"ms-wbt-server": ServiceRDP, //nombre usado por nmap
+	"vnc":           ServiceVNC,
+	"vnc-1":         ServiceVNC,
+	"mbap":          ServiceModbus, //Modbus/TCP
+	"modbus":        ServiceModbus,
+	"iso-tsap":      ServiceS7, //S7comm sobre ISO-on-TCP (102)
+	"s7":            ServiceS7,
+	"bacnet":        ServiceBACnet,
+	"dnp":           ServiceDNP3,
+	"dnp3":          ServiceDNP3,
+	"ethernet-ip":   ServiceEtherNetIP,
+	"enip":          ServiceEtherNetIP,
+	"unknown":       ServiceUnknown,
+}
+
+// convierte un nombre de servicio (portdb, nmap) en ServiceType
+func TypeFromName(name string) ServiceType {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ServiceUnknown
+	}
+	if svc, known := aliases[name]; known {
+		return svc
+	}
+	return ServiceType(name)
+}
+
+// servicio asociado a un puerto/protocolo segun la tabla embebida (portdb)
+// UDP 161 es SNMP aunque TCP 161 normalmente no lo sea
+func lookupPort(proto model.Protocol, port int) (ServiceType, bool) {
+	name, ok := portdb.Name(port, string(proto))
+	if !ok {
+		return ServiceUnknown, false
+	}
+	return TypeFromName(name), true
+}
+
+// inferir el servicio a raiz del protocolo, puerto y banner
+func Detect(proto model.Protocol, port int, banner string) ServiceInfo {
+	//mediante banner
+	if banner != "" {
+		if info, ok := ParseBanner(banner); ok {
+			return info
+		}
+	}
+
 	//mediante puerto
-	if svc, ok := commonPorts[port]; ok {
-		return ServiceInfo{Type: svc, Method: MethodPort}
+	if svc, ok := lookupPort(proto, port); ok {
+		return ServiceInfo{Type: svc, Method: MethodPort, Confidence: model.ConfidenceLow}
 	}
 
 	//desconocido
-	return ServiceInfo{Type: ServiceUnknown, Method: MethodNone}
+	return ServiceInfo{Type: ServiceUnknown, Method: MethodNone, Confidence: model.ConfidenceUnknown}
 }
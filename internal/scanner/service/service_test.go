@@ -0,0 +1,71 @@
+package service
+
+import (
+	"go-scanner/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestMergeCPE(t *testing.T) {
+	banner := func() *ServiceInfo {
+		return &ServiceInfo{
+			Product:    "OpenSSH",
+			Version:    "7.4",
+			CPE:        []string{"cpe:/a:openbsd:openssh:7.4"},
+			Confidence: model.ConfidenceMedium,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		other   *ServiceInfo
+		version string
+		cpe     []string
+	}{
+		{
+			name:    "higher confidence replaces version and its cpe",
+			other:   &ServiceInfo{Product: "OpenSSH", Version: "8.9p1", CPE: []string{"cpe:/a:openbsd:openssh:8.9p1", "cpe:/o:canonical:ubuntu_linux"}, Confidence: model.ConfidenceHigh},
+			version: "8.9p1",
+			cpe:     []string{"cpe:/a:openbsd:openssh:8.9p1", "cpe:/o:canonical:ubuntu_linux"},
+		},
+		{
+			name:    "higher confidence product without cpe drops the stale one",
+			other:   &ServiceInfo{Product: "Dropbear sshd", Version: "2022.83", Confidence: model.ConfidenceHigh},
+			version: "2022.83",
+			cpe:     nil,
+		},
+		{
+			name:    "lower confidence conflicting cpe is ignored",
+			other:   &ServiceInfo{Product: "OpenSSH", Version: "9.6", CPE: []string{"cpe:/a:openbsd:openssh:9.6"}, Confidence: model.ConfidenceLow},
+			version: "7.4",
+			cpe:     []string{"cpe:/a:openbsd:openssh:7.4"},
+		},
+		{
+			name:    "agreeing detections are combined",
+			other:   &ServiceInfo{Product: "openssh", Version: "7.4", CPE: []string{"cpe:/o:redhat:enterprise_linux:7"}, Confidence: model.ConfidenceHigh},
+			version: "7.4",
+			cpe:     []string{"cpe:/a:openbsd:openssh:7.4", "cpe:/o:redhat:enterprise_linux:7"},
+		},
+		{
+			name:    "no product or version keeps both",
+			other:   &ServiceInfo{CPE: []string{"cpe:/a:openbsd:openssh:7.4", "cpe:/o:linux:linux_kernel"}, Confidence: model.ConfidenceLow},
+			version: "7.4",
+			cpe:     []string{"cpe:/a:openbsd:openssh:7.4", "cpe:/o:linux:linux_kernel"},
+		},
+	}
+
+	for _, tt := range tests {
+		info := banner()
+		info.Merge(tt.other)
+		if info.Version != tt.version || !reflect.DeepEqual(info.CPE, tt.cpe) {
+			t.Errorf("%s: Merge = version %q, cpe %q, want %q, %q", tt.name, info.Version, info.CPE, tt.version, tt.cpe)
+		}
+	}
+
+	//sin version previa el CPE del banner se completa con el de la deteccion
+	info := &ServiceInfo{Product: "nginx", CPE: []string{"cpe:/a:f5:nginx"}, Confidence: model.ConfidenceLow}
+	info.Merge(&ServiceInfo{Version: "1.24.0", CPE: []string{"cpe:/a:f5:nginx:1.24.0"}, Confidence: model.ConfidenceHigh})
+	if !reflect.DeepEqual(info.CPE, []string{"cpe:/a:f5:nginx", "cpe:/a:f5:nginx:1.24.0"}) {
+		t.Errorf("Merge version only = %q", info.CPE)
+	}
+}
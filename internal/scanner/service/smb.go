@@ -0,0 +1,36 @@
+package service
+
+//DETALLES DE SMB Y NTLM -> dialectos, firma y datos del challenge NTLMSSP
+
+// resultado del NEGOTIATE de SMB
+type SMBInfo struct {
+	Dialects        []string  //dialectos SMB2/3 aceptados ("2.0.2", "3.1.1")
+	SMB1            bool      //acepta el dialecto "NT LM 0.12"
+	SigningEnabled  bool      //el servidor soporta firma
+	SigningRequired bool      //el servidor exige firma (sin ella, relay NTLM posible)
+	ServerGUID      string    //GUID del servidor (identifica la maquina entre IPs)
+	NTLM            *NTLMInfo //datos del challenge NTLMSSP (si hubo)
+}
+
+// campos del CHALLENGE de NTLMSSP (se obtienen sin credenciales)
+type NTLMInfo struct {
+	NetBIOSComputer string //nombre NetBIOS del equipo
+	NetBIOSDomain   string //dominio o workgroup NetBIOS
+	DNSComputer     string //FQDN del equipo
+	DNSDomain       string //dominio DNS
+	DNSTree         string //bosque
+	OSVersion       string //version de Windows anunciada ("10.0.17763")
+}
+
+// nombres NetBIOS registrados por el host (NBSTAT)
+type NetBIOSInfo struct {
+	Names     []NetBIOSName
+	Workgroup string //nombre de grupo con sufijo 0x00
+	MAC       string //direccion MAC reportada (00:00:00:00:00:00 en Samba)
+}
+
+type NetBIOSName struct {
+	Name   string
+	Suffix byte //0x00 workstation, 0x20 file server, 0x1c DC, ...
+	Group  bool
+}
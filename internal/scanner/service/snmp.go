@@ -0,0 +1,19 @@
+package service
+
+//DETALLES DE SNMP -> comunidad aceptada, MIB system y engine SNMPv3
+
+// lectura del grupo system y descubrimiento del engine SNMPv3
+type SNMPInfo struct {
+	Community   string //comunidad que respondio (vacio si ninguna)
+	Version     string //"v1" o "v2c" de la respuesta
+	SysDescr    string
+	SysObjectID string
+	SysUpTime   int64 //centesimas de segundo (TimeTicks)
+	SysContact  string
+	SysName     string
+
+	EngineID     string //hex, del REPORT de SNMPv3 (sin credenciales)
+	EngineVendor string //empresa registrada en el engine ID (IANA)
+	EngineBoots  int64
+	EngineTime   int64 //segundos desde el ultimo reinicio del engine
+}
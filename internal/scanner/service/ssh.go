@@ -0,0 +1,49 @@
+package service
+
+import "strings"
+
+//DETALLES DE SSH -> KEXINIT y claves de host
+
+// algoritmos ofrecidos por el servidor y sus claves de host
+type SSHInfo struct {
+	Banner            string    //linea de identificacion completa (SSH-2.0-...)
+	KexAlgorithms     []string  //intercambio de claves
+	HostKeyAlgorithms []string  //algoritmos de firma de la clave de host
+	Ciphers           []string  //cifrados (servidor -> cliente)
+	MACs              []string  //MACs (servidor -> cliente)
+	Compression       []string  //compresion
+	HostKeys          []HostKey //una clave por tipo
+	Weak              []string  //algoritmos debiles ofrecidos ("kex:diffie-hellman-group1-sha1")
+}
+
+// clave publica de host
+type HostKey struct {
+	Type        string //ssh-ed25519, ecdsa-sha2-nistp256, ssh-rsa, ...
+	Fingerprint string //SHA256:base64
+}
+
+// algoritmos debiles por categoria
+func WeakSSHAlgorithms(info *SSHInfo) []string {
+	var weak []string
+	flag := func(kind string, list []string, isWeak func(string) bool) {
+		for _, a := range list {
+			if isWeak(a) {
+				weak = append(weak, kind+":"+a)
+			}
+		}
+	}
+
+	flag("kex", info.KexAlgorithms, func(a string) bool {
+		return strings.HasSuffix(a, "-sha1") || strings.Contains(a, "group1-")
+	})
+	flag("cipher", info.Ciphers, func(a string) bool {
+		return strings.HasSuffix(a, "-cbc") || strings.HasPrefix(a, "arcfour") || strings.HasPrefix(a, "3des") || a == "none"
+	})
+	flag("mac", info.MACs, func(a string) bool {
+		return strings.Contains(a, "md5") || strings.HasPrefix(a, "hmac-sha1") || a == "none"
+	})
+	flag("hostkey", info.HostKeyAlgorithms, func(a string) bool {
+		return a == "ssh-rsa" || a == "ssh-dss" //ssh-rsa firma con SHA-1
+	})
+	return weak
+}
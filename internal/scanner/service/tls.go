@@ -0,0 +1,79 @@
+package service
+
+import (
+	"strings"
+	"time"
+)
+
+//DETALLES DE TLS -> handshake negociado y certificado
+
+// resultado del handshake TLS contra un servicio
+type TLSInfo struct {
+	Version     string           //version negociada (TLS 1.3, ...)
+	Cipher      string           //cipher suite negociada
+	ALPN        string           //protocolo de aplicacion negociado (h2, http/1.1)
+	Versions    []TLSVersion     //aceptacion de cada version del protocolo
+	Certificate *CertificateInfo //certificado hoja presentado por el servidor
+}
+
+// aceptacion de una version concreta del protocolo
+type TLSVersion struct {
+	Name     string //TLS 1.0, TLS 1.1, ...
+	Accepted bool   //el servidor completo el handshake con esa version
+}
+
+// datos relevantes del certificado hoja
+type CertificateInfo struct {
+	Subject    string    //DN del sujeto
+	Issuer     string    //DN del emisor
+	SANs       []string  //nombres DNS e IPs alternativos
+	NotBefore  time.Time //inicio de validez
+	NotAfter   time.Time //fin de validez
+	KeyType    string    //RSA, ECDSA, Ed25519
+	KeyBits    int       //tamaño de la clave
+	SelfSigned bool      //firmado por si mismo
+	Expired    bool      //fuera del periodo de validez al momento del escaneo
+	SHA256     string    //huella SHA-256 del certificado (hex)
+}
+
+// fingerprint del stack TLS del servidor
+type TLSFingerprint struct {
+	JARM   string   //hash JARM (62 caracteres)
+	JA3S   string   //md5 JA3S del handshake por defecto
+	Labels []string //etiquetas de la lista de hashes conocidos
+}
+
+// resultado del upgrade en claro -> TLS (STARTTLS, STLS, AUTH TLS, SSLRequest)
+type StartTLSInfo struct {
+	Command  string //comando de upgrade del protocolo
+	Offered  bool   //el servidor ofrece el upgrade
+	Required bool   //el servidor rechaza operar sin TLS
+}
+
+// versiones obsoletas que el servidor todavia acepta (TLS 1.0/1.1, SSL)
+func (t *TLSInfo) Deprecated() []string {
+	if t == nil {
+		return nil
+	}
+
+	var out []string
+	for _, v := range t.Versions {
+		if v.Accepted && (strings.HasPrefix(v.Name, "SSL") || v.Name == "TLS 1.0" || v.Name == "TLS 1.1") {
+			out = append(out, v.Name)
+		}
+	}
+	return out
+}
+
+// resumen de una linea: "TLS 1.3 TLS_AES_128_GCM_SHA256 h2"
+func (t *TLSInfo) Summary() string {
+	if t == nil {
+		return ""
+	}
+
+	parts := []string{t.Version, t.Cipher}
+	if t.ALPN != "" {
+		parts = append(parts, t.ALPN)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,66 @@
+package service
+
+//VULNERABILIDADES -> CVEs conocidos para el producto/version identificado (feed local)
+import "sort"
+
+// vulnerabilidad asociada al servicio por su CPE
+type Vulnerability struct {
+	ID       string   //CVE-AAAA-NNNN
+	CVSS     float64  //score base (0 si el feed no lo trae)
+	Severity Severity //del feed o derivada del CVSS
+	Summary  string   //descripcion corta
+	CPE      string   //CPE del servicio que coincidio
+}
+
+// severidad segun el score CVSS v3 (0 = info)
+func SeverityFromCVSS(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityInfo
+	}
+}
+
+// ordena de mayor a menor severidad, luego por CVSS y por ID
+func SortVulnerabilities(list []Vulnerability) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() > b.Severity.Rank()
+		}
+		if a.CVSS != b.CVSS {
+			return a.CVSS > b.CVSS
+		}
+		return a.ID < b.ID
+	})
+}
+
+// ordena los hallazgos de mayor a menor severidad (estable)
+func SortFindings(list []Finding) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Severity.Rank() > list[j].Severity.Rank()
+	})
+}
+
+// severidad mas alta entre hallazgos y vulnerabilidades ("" si no hay ninguno)
+func (i *ServiceInfo) MaxSeverity() Severity {
+	var max Severity
+	for _, f := range i.Findings {
+		if f.Severity.Rank() > max.Rank() {
+			max = f.Severity
+		}
+	}
+	for _, v := range i.Vulns {
+		if v.Severity.Rank() > max.Rank() {
+			max = v.Severity
+		}
+	}
+	return max
+}
@@ -6,6 +6,7 @@ import (
 	"go-scanner/internal/model"
 	"go-scanner/internal/scanner"
 	"go-scanner/internal/scanner/banner"
+	"go-scanner/internal/utils"
 	"net"  //API de red
 	"sync" //sincronizacion
 	"time"
@@ -19,10 +20,13 @@ type TCPConnectScanner struct {
 	Concurrency  int                 //numero maximo de conexiones concurrentes
 	EnableBanner bool                //habilitar banner grabbing pasivo
 	Metadata     *model.HostMetadata //contexto del descubrimiento
+	Order        utils.Permutation   //orden de recorrido de los puertos
+	Grabber      *banner.Grabber     //banner en cualquier puerto (nil = solo puertos conocidos)
+	Dialer       utils.Dialer        //conexiones de la campaña (nil = directo)
 }
 
 // nueva instacia de TCPConnectScanner
-func NewTCPConnectScanner(target string, ports []int, timeout time.Duration, concurrency int, enableBanner bool, meta *model.HostMetadata) *TCPConnectScanner {
+func NewTCPConnectScanner(target string, ports []int, timeout time.Duration, concurrency int, enableBanner bool, meta *model.HostMetadata, order utils.Permutation) *TCPConnectScanner {
 	return &TCPConnectScanner{
 		Target:       target,
 		Ports:        ports,
@@ -30,6 +34,7 @@ func NewTCPConnectScanner(target string, ports []int, timeout time.Duration, con
 		Concurrency:  concurrency,
 		EnableBanner: enableBanner, //banner grabbing
 		Metadata:     meta,
+		Order:        order,
 	}
 }
 
@@ -41,7 +46,7 @@ func (s *TCPConnectScanner) Scan(results chan<- scanner.ScanResult) {
 	// semaforo para limitar concurrencia y no saturar FDs o la red.
 	sem := make(chan struct{}, s.Concurrency)
 
-	for _, port := range s.Ports { //recorrer cada puerto
+	for _, port := range s.Order.Ports(s.Ports) { //recorrer cada puerto (permutados)
 		wg.Add(1)
 		sem <- struct{}{} //intenta adquirir un slot del semaforo
 		//si el semaforo esta lleno, el loop se bloquea y se evita crear mas gorutinas
@@ -60,6 +65,7 @@ func (s *TCPConnectScanner) Scan(results chan<- scanner.ScanResult) {
 			results <- scanner.ScanResult{
 				Host:     s.Target,
 				Port:     p,
+				Protocol: model.ProtocolTCP,
 				State:    state,
 				Banner:   bannerText, //incluir banner grabbing
 				Metadata: s.Metadata,
@@ -73,7 +79,7 @@ func (s *TCPConnectScanner) Scan(results chan<- scanner.ScanResult) {
 // intentar establecer una conexion TCP con el target:puerto
 func (s *TCPConnectScanner) scanPort(port int) (bool, string) {
 	address := net.JoinHostPort(s.Target, fmt.Sprintf("%d", port)) //endpoint TCP estandar
-	conn, err := net.DialTimeout("tcp", address, s.Timeout)
+	conn, err := utils.DialTimeout(s.Dialer, "tcp", address, s.Timeout)
 
 	if err != nil {
 		return false, ""
@@ -82,7 +88,9 @@ func (s *TCPConnectScanner) scanPort(port int) (bool, string) {
 	defer conn.Close()
 
 	var collectedBanner string
-	if s.EnableBanner {
+	if s.Grabber != nil {
+		collectedBanner = s.Grabber.Grab(conn, address) //null probe y probes genericos
+	} else if s.EnableBanner {
 		collectedBanner, _ = banner.Grab(conn, port) //intentar obtener el banner
 	}
 
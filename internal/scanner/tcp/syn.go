@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"go-scanner/internal/model"
 	"go-scanner/internal/scanner"
+	"go-scanner/internal/utils"
 	"math/rand"
 	"net"
 	"sync"
@@ -26,6 +27,7 @@ type TCPSynScanner struct {
 	Timeout     time.Duration
 	Concurrency int
 	Metadata    *model.HostMetadata
+	Order       utils.Permutation //orden de envio de los SYN
 }
 
 // representacion de los 20 bytes del header TCP
@@ -51,13 +53,14 @@ type PseudoHeader struct {
 }
 
 // Nueva instancia de TCPSynScanner
-func NewTCPSynScanner(target string, ports []int, timeout time.Duration, concurrency int, meta *model.HostMetadata) *TCPSynScanner {
+func NewTCPSynScanner(target string, ports []int, timeout time.Duration, concurrency int, meta *model.HostMetadata, order utils.Permutation) *TCPSynScanner {
 	return &TCPSynScanner{
 		Target:      target,
 		Ports:       ports,
 		Timeout:     timeout,
 		Concurrency: concurrency,
 		Metadata:    meta,
+		Order:       order,
 	}
 }
 
@@ -131,6 +134,7 @@ func (s *TCPSynScanner) ScanCtx(ctx context.Context, results chan<- scanner.Scan
 			results <- scanner.ScanResult{
 				Host:     s.Target,
 				Port:     port,
+				Protocol: model.ProtocolTCP,
 				State:    scanner.PortStateFiltered,
 				Banner:   "",
 				Metadata: s.Metadata,
@@ -148,7 +152,8 @@ func (s *TCPSynScanner) sendPackets(fd int, dstIP net.IP, srcIP net.IP) {
 	// simulacion de trafico real (puerto fuente aleatorio (o eso intento))
 	srcPort := uint16(1024 + rand.Intn(60000))
 
-	for _, port := range s.Ports {
+	//orden permutado para no disparar firmas de escaneo secuencial
+	for _, port := range s.Order.Ports(s.Ports) {
 		dstPort := uint16(port)
 
 		// contruccion real de TCP SYN para el envio
@@ -240,6 +245,7 @@ func (s *TCPSynScanner) listen(ctx context.Context, fd int, targetIP net.IP, sen
 			found <- scanner.ScanResult{
 				Host:     s.Target,
 				Port:     int(tcpH.Source),
+				Protocol: model.ProtocolTCP,
 				State:    state,
 				Banner:   "", // SYN no captura banners
 				Metadata: s.Metadata,
@@ -311,6 +317,7 @@ func (s *TCPSynScanner) reportFatalError(results chan<- scanner.ScanResult, port
 	results <- scanner.ScanResult{
 		Host:     s.Target,
 		Port:     port,
+		Protocol: model.ProtocolTCP,
 		Error:    err,
 		State:    scanner.PortStateClosed,
 		Metadata: s.Metadata,
@@ -11,6 +11,7 @@ import (
 
 	"go-scanner/internal/model"
 	"go-scanner/internal/scanner"
+	"go-scanner/internal/utils"
 )
 
 // ensure UDPScanner implements scanner.Scanner
@@ -22,15 +23,18 @@ type UDPScanner struct {
 	Timeout     time.Duration
 	Concurrency int
 	Metadata    *model.HostMetadata
+	Order       utils.Permutation
+	Dialer      utils.Dialer //conexiones de la campaña (nil = directo)
 }
 
-func NewUDPScanner(target string, ports []int, timeout time.Duration, concurrency int, meta *model.HostMetadata) *UDPScanner {
+func NewUDPScanner(target string, ports []int, timeout time.Duration, concurrency int, meta *model.HostMetadata, order utils.Permutation) *UDPScanner {
 	return &UDPScanner{
 		Target:      target,
 		Ports:       ports,
 		Timeout:     timeout,
 		Concurrency: concurrency,
 		Metadata:    meta,
+		Order:       order,
 	}
 }
 
@@ -40,9 +44,10 @@ func (s *UDPScanner) Scan(results chan<- scanner.ScanResult) {
 	dstIP := net.ParseIP(s.Target).To4()
 	if dstIP == nil {
 		results <- scanner.ScanResult{
-			Host:  s.Target,
-			Port:  0,
-			Error: fmt.Errorf("invalid IPv4 target"),
+			Host:     s.Target,
+			Port:     0,
+			Protocol: model.ProtocolUDP,
+			Error:    fmt.Errorf("invalid IPv4 target"),
 		}
 		return
 	}
@@ -55,7 +60,7 @@ func (s *UDPScanner) Scan(results chan<- scanner.ScanResult) {
 
 	sem := make(chan struct{}, s.Concurrency)
 
-	for _, port := range s.Ports {
+	for _, port := range s.Order.Ports(s.Ports) {
 		scanWg.Add(1)
 		sem <- struct{}{}
 
@@ -69,6 +74,7 @@ func (s *UDPScanner) Scan(results chan<- scanner.ScanResult) {
 			resultsMap[p] = scanner.ScanResult{
 				Host:     s.Target,
 				Port:     p,
+				Protocol: model.ProtocolUDP,
 				State:    state,
 				Metadata: s.Metadata,
 			}
@@ -95,6 +101,7 @@ func (s *UDPScanner) Scan(results chan<- scanner.ScanResult) {
 			results <- scanner.ScanResult{
 				Host:     s.Target,
 				Port:     port,
+				Protocol: model.ProtocolUDP,
 				State:    scanner.PortStateFiltered,
 				Metadata: s.Metadata,
 			}
@@ -105,7 +112,7 @@ func (s *UDPScanner) Scan(results chan<- scanner.ScanResult) {
 func (s *UDPScanner) scanPort(port int) scanner.PortState {
 	address := net.JoinHostPort(s.Target, fmt.Sprintf("%d", port))
 
-	conn, err := net.DialTimeout("udp", address, s.Timeout)
+	conn, err := utils.DialTimeout(s.Dialer, "udp", address, s.Timeout)
 
 	if err != nil {
 		return scanner.PortStateFiltered
@@ -200,6 +207,7 @@ func (s *UDPScanner) updateClosedPortsWithICMP(dstIP net.IP, resultsMap map[int]
 				resultsMap[port] = scanner.ScanResult{
 					Host:     s.Target,
 					Port:     port,
+					Protocol: model.ProtocolUDP,
 					State:    scanner.PortStateClosed,
 					Metadata: s.Metadata,
 				}
@@ -207,5 +215,3 @@ func (s *UDPScanner) updateClosedPortsWithICMP(dstIP net.IP, resultsMap map[int]
 		}
 	}
 }
-
-
@@ -0,0 +1,277 @@
+package version
+
+//BASE DE PROBES Y REGLAS DE MATCH (subconjunto compatible con nmap-service-probes)
+import (
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/service"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// un probe: payload a enviar y reglas para reconocer la respuesta
+type Probe struct {
+	Name      string
+	Protocol  model.Protocol
+	Payload   []byte
+	Rarity    int           //1 (comun) a 9 (raro), se envian en orden de rareza
+	Ports     map[int]bool  //puertos donde el probe suele tener exito
+	SSLPorts  map[int]bool  //idem, pero sobre TLS (solo informativo por ahora)
+	TotalWait time.Duration //tiempo maximo de espera de la respuesta (0 = timeout del scan)
+	Fallback  []string      //probes cuyas reglas tambien se prueban contra esta respuesta
+	Matches   []*Match
+	fallbacks []*Probe
+}
+
+// regla de match contra la respuesta de un probe
+type Match struct {
+	Service string
+	Soft    bool //softmatch: identifica el servicio pero no el producto
+	Pattern *regexp.Regexp
+	Info    versionInfo
+}
+
+// plantillas p// v// i// h// o// d// cpe:// de la regla
+type versionInfo struct {
+	Product    string
+	Version    string
+	Info       string
+	Hostname   string
+	OS         string
+	DeviceType string
+	CPE        []string
+}
+
+// base completa de probes
+type DB struct {
+	Probes  []*Probe
+	Skipped int //reglas descartadas (regex no soportadas por RE2)
+}
+
+// resultado de aplicar las reglas sobre una respuesta
+type Result struct {
+	Probe string //probe que obtuvo la respuesta
+	Soft  bool
+	Info  *service.ServiceInfo
+}
+
+// busca un probe por protocolo y nombre
+func (db *DB) Probe(proto model.Protocol, name string) *Probe {
+	for _, p := range db.Probes {
+		if p.Protocol == proto && p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// probes a enviar para un puerto, en orden: NULL, probes del puerto y resto por rareza
+// se descartan los probes mas raros que la intensidad indicada (salvo que apunten al puerto)
+func (db *DB) ProbesFor(proto model.Protocol, port int, intensity int) []*Probe {
+	var out []*Probe
+	for _, p := range db.Probes {
+		if p.Protocol != proto {
+			continue
+		}
+		if p.Rarity > intensity && !p.Ports[port] && !p.SSLPorts[port] {
+			continue
+		}
+		out = append(out, p)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if (len(a.Payload) == 0) != (len(b.Payload) == 0) {
+			return len(a.Payload) == 0 //NULL probe primero
+		}
+		if a.Ports[port] != b.Ports[port] {
+			return a.Ports[port]
+		}
+		return a.Rarity < b.Rarity
+	})
+	return out
+}
+
+// aplica las reglas del probe (y de sus fallbacks) sobre una respuesta
+// los hard matches ganan, un softmatch solo se retorna si no hubo ninguno
+func (db *DB) MatchResponse(p *Probe, response []byte) (*Result, bool) {
+	if len(response) == 0 {
+		return nil, false
+	}
+
+	subject := latin1(response)
+
+	var soft *Result
+	for _, probe := range append([]*Probe{p}, p.fallbacks...) {
+		for _, m := range probe.Matches {
+			groups := m.Pattern.FindStringSubmatch(subject)
+			if groups == nil {
+				continue
+			}
+
+			res := &Result{Probe: p.Name, Soft: m.Soft, Info: m.build(groups, len(p.Payload) == 0)}
+			if !m.Soft {
+				return res, true
+			}
+			if soft == nil {
+				soft = res
+			}
+		}
+	}
+
+	if soft != nil {
+		return soft, true
+	}
+	return nil, false
+}
+
+// probes cuyas respuestas puede contener un banner ya capturado:
+// el NULL (banner no solicitado) y los genericos que envia el banner grabber
+var bannerProbes = []string{"NULL", "GenericLines", "GetRequest"}
+
+// aplica las reglas de los probes de banner sobre un banner ya capturado (sin trafico extra)
+func (db *DB) MatchBanner(proto model.Protocol, banner string) (*Result, bool) {
+	var soft *Result
+	for _, name := range bannerProbes {
+		p := db.Probe(proto, name)
+		if p == nil {
+			continue
+		}
+		res, ok := db.MatchResponse(p, []byte(banner))
+		if !ok {
+			continue
+		}
+		if !res.Soft {
+			return res, true
+		}
+		if soft == nil {
+			soft = res
+		}
+	}
+
+	if soft != nil {
+		return soft, true
+	}
+	return nil, false
+}
+
+// construye el ServiceInfo de un match sustituyendo $1..$9
+func (m *Match) build(groups []string, passive bool) *service.ServiceInfo {
+	method := service.MethodProbe
+	if passive {
+		method = service.MethodBanner
+	}
+
+	confidence := model.ConfidenceHigh
+	if m.Soft {
+		confidence = model.ConfidenceMedium
+	}
+
+	info := &service.ServiceInfo{
+		Type:       service.TypeFromName(m.Service),
+		Method:     method,
+		Product:    substitute(m.Info.Product, groups),
+		Version:    substitute(m.Info.Version, groups),
+		ExtraInfo:  substitute(m.Info.Info, groups),
+		Hostname:   substitute(m.Info.Hostname, groups),
+		OSHint:     substitute(m.Info.OS, groups),
+		DeviceType: substitute(m.Info.DeviceType, groups),
+		Confidence: confidence,
+	}
+	for _, c := range m.Info.CPE {
+		if v := substitute(c, groups); v != "" {
+			info.CPE = append(info.CPE, "cpe:/"+v)
+		}
+	}
+	return info
+}
+
+// $SUBST(1,"a","b") y $P(1)
+var helperRe = regexp.MustCompile(`\$(SUBST|P|I)\((\d)(?:,"([^"]*)"(?:,"([^"]*)")?)?\)`)
+
+// sustituye las referencias a grupos de captura en una plantilla
+func substitute(tmpl string, groups []string) string {
+	if tmpl == "" {
+		return ""
+	}
+
+	//bytes crudos del grupo, la validacion UTF-8 se hace al final
+	group := func(s string) string {
+		n, _ := strconv.Atoi(s)
+		if n < len(groups) {
+			return bytesFromLatin1(groups[n])
+		}
+		return ""
+	}
+
+	out := helperRe.ReplaceAllStringFunc(tmpl, func(expr string) string {
+		m := helperRe.FindStringSubmatch(expr)
+		value := group(m[2])
+		switch m[1] {
+		case "SUBST":
+			return strings.ReplaceAll(value, m[3], m[4])
+		case "P":
+			return printable(value)
+		default: //$I: entero binario, se deja el valor crudo en decimal
+			return integer(value, m[3])
+		}
+	})
+
+	var b strings.Builder
+	for i := 0; i < len(out); i++ {
+		if out[i] == '$' && i+1 < len(out) && out[i+1] >= '1' && out[i+1] <= '9' {
+			b.WriteString(group(out[i+1 : i+2]))
+			i++
+			continue
+		}
+		b.WriteByte(out[i])
+	}
+	return strings.TrimSpace(strings.ToValidUTF8(b.String(), ""))
+}
+
+// solo caracteres imprimibles
+func printable(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x20 && s[i] < 0x7f {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// $I(n,">") -> entero sin signo big (">") o little ("<") endian
+func integer(s string, endian string) string {
+	var v uint64
+	raw := []byte(s)
+	if endian == "<" {
+		for i := len(raw) - 1; i >= 0; i-- {
+			v = v<<8 | uint64(raw[i])
+		}
+	} else {
+		for _, c := range raw {
+			v = v<<8 | uint64(c)
+		}
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// cada byte de la respuesta pasa a ser un rune, asi \xNN en la regex matchea el byte NN
+func latin1(b []byte) string {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+// inverso de latin1 para los grupos capturados
+func bytesFromLatin1(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return string(b)
+}
@@ -0,0 +1,225 @@
+package version
+
+//MOTOR DE DETECCION DE VERSIONES
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"go-scanner/internal/model"
+	"go-scanner/internal/scanner/service"
+	"go-scanner/internal/utils"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+//go:embed data/service-probes
+var defaultProbes []byte
+
+// intensidad por defecto: probes con rareza <= 7
+const DefaultIntensity = 7
+
+// limite de lectura por respuesta
+const maxResponse = 16 * 1024
+
+// espera adicional una vez que empezaron a llegar datos
+const readGrace = 300 * time.Millisecond
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *DB
+	defaultErr  error
+)
+
+// base embebida por defecto
+func Default() (*DB, error) {
+	defaultOnce.Do(func() {
+		defaultDB, defaultErr = Parse(bytes.NewReader(defaultProbes))
+	})
+	return defaultDB, defaultErr
+}
+
+// bases ya cargadas por ruta, se comparten entre hosts y escaneos
+var (
+	loadedMu sync.Mutex
+	loaded   = make(map[string]*DB)
+)
+
+// carga la base por defecto mas un archivo del usuario (opcional)
+// las reglas del usuario se prueban antes que las embebidas
+func Load(path string) (*DB, error) {
+	base, err := Default()
+	if err != nil {
+		return nil, fmt.Errorf("embedded service probes: %w", err)
+	}
+	if path == "" {
+		return base, nil
+	}
+
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	if db, ok := loaded[path]; ok {
+		return db, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open service probes file: %w", err)
+	}
+	defer f.Close()
+
+	user, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	db := merge(user, base)
+	loaded[path] = db
+	return db, nil
+}
+
+// une dos bases sin modificar las originales
+func merge(first, second *DB) *DB {
+	out := &DB{Skipped: first.Skipped + second.Skipped}
+	index := make(map[string]*Probe)
+
+	for _, src := range []*DB{first, second} {
+		for _, p := range src.Probes {
+			key := string(p.Protocol) + "/" + p.Name
+			if existing, ok := index[key]; ok {
+				existing.Matches = append(existing.Matches, p.Matches...)
+				continue
+			}
+			cp := *p
+			cp.Matches = append([]*Match(nil), p.Matches...)
+			index[key] = &cp
+			out.Probes = append(out.Probes, &cp)
+		}
+	}
+
+	out.link()
+	return out
+}
+
+// ejecuta los probes sobre un puerto abierto hasta el primer hard match
+type Engine struct {
+	DB        *DB
+	Timeout   time.Duration //timeout de conexion y lectura por probe
+	Intensity int           //rareza maxima de los probes enviados
+	Dialer    utils.Dialer  //conexiones de la campaña (nil = directo)
+}
+
+// nueva instancia del motor
+func NewEngine(db *DB, timeout time.Duration, intensity int) *Engine {
+	if intensity <= 0 {
+		intensity = DefaultIntensity
+	}
+	return &Engine{
+		DB:        db,
+		Timeout:   timeout,
+		Intensity: intensity,
+	}
+}
+
+// identifica el servicio enviando los probes en orden de rareza
+// un softmatch se recuerda, pero se sigue buscando un hard match
+func (e *Engine) Identify(target string, port int, proto model.Protocol) (*service.ServiceInfo, error) {
+	var soft *Result
+	var lastErr error
+
+	for _, p := range e.DB.ProbesFor(proto, port, e.Intensity) {
+		//UDP sin payload no obtiene respuesta
+		if proto == model.ProtocolUDP && len(p.Payload) == 0 {
+			continue
+		}
+
+		response, err := e.send(target, port, p)
+		if err != nil {
+			lastErr = err
+			if errors.Is(err, errRefused) {
+				break //el puerto se cerro, no tiene sentido seguir
+			}
+			continue
+		}
+
+		res, ok := e.DB.MatchResponse(p, response)
+		if !ok {
+			continue
+		}
+		if !res.Soft {
+			return res.Info, nil
+		}
+		if soft == nil {
+			soft = res
+		}
+	}
+
+	if soft != nil {
+		return soft.Info, nil
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, nil
+}
+
+var errRefused = errors.New("connection refused")
+
+// envia el payload del probe y lee la respuesta (una conexion por probe)
+func (e *Engine) send(target string, port int, p *Probe) ([]byte, error) {
+	address := net.JoinHostPort(target, strconv.Itoa(port))
+
+	conn, err := utils.DialTimeout(e.Dialer, string(p.Protocol), address, e.Timeout)
+	if err != nil {
+		//solo un rechazo explicito cierra el puerto, un timeout o error transitorio deja probar el siguiente probe
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, errRefused
+		}
+		return nil, err
+	}
+	defer conn.Close()
+
+	wait := e.Timeout
+	if p.TotalWait > 0 && p.TotalWait < wait {
+		wait = p.TotalWait
+	}
+
+	if len(p.Payload) > 0 {
+		conn.SetWriteDeadline(time.Now().Add(e.Timeout))
+		if _, err := conn.Write(p.Payload); err != nil {
+			return nil, err
+		}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(wait))
+	return readResponse(conn, p.Protocol)
+}
+
+// lee hasta el cierre, el deadline o el limite de tamaño
+// en UDP basta con el primer datagrama
+func readResponse(conn net.Conn, proto model.Protocol) ([]byte, error) {
+	buf := make([]byte, 4096)
+	var out []byte
+
+	for len(out) < maxResponse {
+		n, err := conn.Read(buf)
+		out = append(out, buf[:n]...)
+		if err != nil || proto == model.ProtocolUDP {
+			break
+		}
+		//con datos recibidos solo se espera un poco mas por el resto de la respuesta
+		if n > 0 {
+			conn.SetReadDeadline(time.Now().Add(readGrace))
+		}
+	}
+
+	if len(out) > maxResponse {
+		out = out[:maxResponse]
+	}
+	return out, nil
+}
@@ -0,0 +1,335 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"go-scanner/internal/model"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsea un archivo en formato nmap-service-probes (subconjunto)
+// directivas soportadas: Probe, match, softmatch, ports, sslports, rarity, totalwaitms, fallback
+// el resto (Exclude, tcpwrappedms, ...) se ignora
+func Parse(r io.Reader) (*DB, error) {
+	db := &DB{}
+	var current *Probe
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024) //hay reglas muy largas
+	lineNo := 0
+
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		directive, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		if directive == "Probe" {
+			p, err := parseProbe(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			db.Probes = append(db.Probes, p)
+			current = p
+			continue
+		}
+
+		if current == nil {
+			//directivas globales (Exclude) o basura antes del primer Probe
+			continue
+		}
+
+		var err error
+		switch directive {
+		case "match", "softmatch":
+			var m *Match
+			m, err = parseMatch(rest, directive == "softmatch")
+			if err == errUnsupportedRegex {
+				db.Skipped++
+				err = nil
+			} else if err == nil {
+				current.Matches = append(current.Matches, m)
+			}
+		case "ports":
+			current.Ports, err = parsePortList(rest)
+		case "sslports":
+			current.SSLPorts, err = parsePortList(rest)
+		case "rarity":
+			current.Rarity, err = strconv.Atoi(rest)
+		case "totalwaitms":
+			var ms int
+			ms, err = strconv.Atoi(rest)
+			current.TotalWait = time.Duration(ms) * time.Millisecond
+		case "fallback":
+			for _, name := range strings.Split(rest, ",") {
+				current.Fallback = append(current.Fallback, strings.TrimSpace(name))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNo, directive, err)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	db.link()
+	return db, nil
+}
+
+// resuelve los fallbacks por nombre, los probes TCP tambien prueban las reglas de NULL
+func (db *DB) link() {
+	for _, p := range db.Probes {
+		p.fallbacks = nil
+		for _, name := range p.Fallback {
+			if fb := db.Probe(p.Protocol, name); fb != nil && fb != p {
+				p.fallbacks = append(p.fallbacks, fb)
+			}
+		}
+		if p.Protocol == model.ProtocolTCP && p.Name != "NULL" {
+			if null := db.Probe(model.ProtocolTCP, "NULL"); null != nil {
+				p.fallbacks = append(p.fallbacks, null)
+			}
+		}
+	}
+}
+
+// Probe TCP GetRequest q|GET / HTTP/1.0\r\n\r\n|
+func parseProbe(rest string) (*Probe, error) {
+	fields := strings.SplitN(rest, " ", 3)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid Probe directive: %s", rest)
+	}
+
+	p := &Probe{Name: fields[1], Rarity: 1}
+	switch strings.ToUpper(fields[0]) {
+	case "TCP":
+		p.Protocol = model.ProtocolTCP
+	case "UDP":
+		p.Protocol = model.ProtocolUDP
+	default:
+		return nil, fmt.Errorf("invalid probe protocol: %s", fields[0])
+	}
+
+	q := fields[2]
+	if len(q) < 3 || q[0] != 'q' {
+		return nil, fmt.Errorf("invalid probe string: %s", q)
+	}
+	delim := q[1]
+	end := strings.IndexByte(q[2:], delim)
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated probe string: %s", q)
+	}
+
+	payload, err := unescape(q[2 : 2+end])
+	if err != nil {
+		return nil, err
+	}
+	p.Payload = payload
+	return p, nil
+}
+
+var errUnsupportedRegex = fmt.Errorf("unsupported regex")
+
+// match ssh m|^SSH-([\d.]+)-OpenSSH_([\w.]+)| p/OpenSSH/ v/$2/ i/protocol $1/ cpe:/a:openbsd:openssh:$2/
+func parseMatch(rest string, soft bool) (*Match, error) {
+	service, pattern, ok := strings.Cut(rest, " ")
+	if !ok || len(pattern) < 3 || pattern[0] != 'm' {
+		return nil, fmt.Errorf("invalid match: %s", rest)
+	}
+
+	delim := pattern[1]
+	end := strings.IndexByte(pattern[2:], delim)
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated regex: %s", rest)
+	}
+	expr := pattern[2 : 2+end]
+	tail := pattern[2+end+1:]
+
+	//flags (i, s) pegados al delimitador final
+	flags := ""
+	for len(tail) > 0 && (tail[0] == 'i' || tail[0] == 's') {
+		flags += string(tail[0])
+		tail = tail[1:]
+	}
+
+	re, err := compileRegex(expr, flags)
+	if err != nil {
+		return nil, errUnsupportedRegex
+	}
+
+	info, err := parseVersionInfo(strings.TrimSpace(tail))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Match{Service: service, Soft: soft, Pattern: re, Info: info}, nil
+}
+
+// traduce la sintaxis PCRE mas comun de nmap a RE2
+func compileRegex(expr, flags string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if c != '\\' || i+1 >= len(expr) {
+			b.WriteByte(c)
+			continue
+		}
+
+		next := expr[i+1]
+		switch {
+		case next == '0' && (i+2 >= len(expr) || expr[i+2] < '0' || expr[i+2] > '7'):
+			b.WriteString(`\x00`)
+		case next == 'Z':
+			b.WriteString(`\z`)
+		default:
+			b.WriteByte(c)
+			b.WriteByte(next)
+		}
+		i++
+	}
+
+	prefix := ""
+	if flags != "" {
+		prefix = "(?" + flags + ")"
+	}
+	return regexp.Compile(prefix + b.String())
+}
+
+// p/.../ v/.../ i/.../ h/.../ o/.../ d/.../ cpe:/.../a
+func parseVersionInfo(s string) (versionInfo, error) {
+	var info versionInfo
+
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			break
+		}
+
+		field := s[:1]
+		if strings.HasPrefix(s, "cpe:") {
+			field = "cpe"
+			s = s[4:]
+		} else {
+			s = s[1:]
+		}
+
+		if s == "" {
+			return info, fmt.Errorf("truncated version field %s", field)
+		}
+		delim := s[0]
+		end := strings.IndexByte(s[1:], delim)
+		if end < 0 {
+			return info, fmt.Errorf("unterminated version field %s", field)
+		}
+		value := s[1 : 1+end]
+		s = s[1+end+1:]
+
+		//flag opcional 'a' despues de cpe
+		if field == "cpe" && strings.HasPrefix(s, "a") {
+			s = s[1:]
+		}
+
+		switch field {
+		case "p":
+			info.Product = value
+		case "v":
+			info.Version = value
+		case "i":
+			info.Info = value
+		case "h":
+			info.Hostname = value
+		case "o":
+			info.OS = value
+		case "d":
+			info.DeviceType = value
+		case "cpe":
+			info.CPE = append(info.CPE, value)
+		}
+	}
+	return info, nil
+}
+
+// 21,22,80-85
+func parsePortList(s string) (map[int]bool, error) {
+	ports := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", part)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(end); err != nil {
+				return nil, fmt.Errorf("invalid port: %s", part)
+			}
+		}
+		for p := from; p <= to; p++ {
+			ports[p] = true
+		}
+	}
+	return ports, nil
+}
+
+// escapes del payload: \r \n \t \0 \xHH \\ ...
+func unescape(s string) ([]byte, error) {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			out = append(out, c)
+			continue
+		}
+		if i+1 >= len(s) {
+			return nil, fmt.Errorf("trailing backslash in probe string")
+		}
+
+		i++
+		switch s[i] {
+		case 'r':
+			out = append(out, '\r')
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case '0':
+			out = append(out, 0)
+		case 'a':
+			out = append(out, '\a')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'v':
+			out = append(out, '\v')
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, fmt.Errorf("truncated \\x escape in probe string")
+			}
+			v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid \\x escape: %s", s[i-1:i+3])
+			}
+			out = append(out, byte(v))
+			i += 2
+		default:
+			out = append(out, s[i])
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,191 @@
+package vuln
+
+//CPE Y VERSIONES -> formatos URI (2.2) y formatted string (2.3), comparacion de versiones
+import (
+	"strconv"
+	"strings"
+)
+
+// partes de un CPE que se usan para el match
+type CPE struct {
+	Part    string //a (aplicacion), o (sistema), h (hardware)
+	Vendor  string
+	Product string
+	Version string //"" o "*" = cualquiera
+}
+
+// "cpe:/a:openbsd:openssh:8.9p1" o "cpe:2.3:a:openbsd:openssh:8.9p1:*:..."
+func ParseCPE(s string) (CPE, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	var fields []string
+	switch {
+	case strings.HasPrefix(s, "cpe:2.3:"):
+		fields = splitEscaped(strings.TrimPrefix(s, "cpe:2.3:"))
+	case strings.HasPrefix(s, "cpe:/"):
+		fields = strings.Split(strings.TrimPrefix(s, "cpe:/"), ":")
+	default:
+		return CPE{}, false
+	}
+
+	var c CPE
+	for i, f := range fields {
+		f = unescape(f)
+		switch i {
+		case 0:
+			c.Part = f
+		case 1:
+			c.Vendor = f
+		case 2:
+			c.Product = f
+		case 3:
+			c.Version = f
+		}
+	}
+	return c, c.Part != ""
+}
+
+// clave del indice
+func (c CPE) Key() string {
+	return c.Vendor + ":" + c.Product
+}
+
+// separa por ":" respetando "\:" (CPE 2.3)
+func splitEscaped(s string) []string {
+	var out []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			cur.WriteByte(s[i])
+			cur.WriteByte(s[i+1])
+			i++
+		case s[i] == ':':
+			out = append(out, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(s[i])
+		}
+	}
+	return append(out, cur.String())
+}
+
+// quita los escapes de CPE 2.3 ("1\.0" -> "1.0")
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// compara versiones por segmentos numericos y alfabeticos: -1, 0, 1
+// "9.3p2" > "9.3p1" > "9.3", "1.0.2k" > "1.0.2", "2.4.10" > "2.4.9"
+// los pre-releases quedan antes de la version final: "2.4.10-rc1" < "2.4.10" < "2.4.10p1"
+// los ceros finales no cuentan: "1.0.0" = "1.0"
+func CompareVersions(a, b string) int {
+	ta, tb := tokens(a), tokens(b)
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		if c := compareToken(ta[i], tb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ta) > len(tb):
+		return extraTokens(ta[len(tb):])
+	case len(ta) < len(tb):
+		return -extraTokens(tb[len(ta):])
+	}
+	return 0
+}
+
+// segmentos que solo tiene una de las versiones: los ceros no cuentan ("1.0.0" = "1.0"),
+// un pre-release la hace menor y cualquier otro segmento mayor
+func extraTokens(rest []string) int {
+	for _, t := range rest {
+		if n, err := strconv.Atoi(t); err == nil && n == 0 {
+			continue
+		}
+		if preRelease(t) > 0 {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// orden de los sufijos de pre-release (0 = no es pre-release)
+var preReleases = map[string]int{"dev": 1, "alpha": 2, "beta": 3, "pre": 4, "rc": 5}
+
+func preRelease(token string) int {
+	return preReleases[token]
+}
+
+// "8.9p1" -> ["8", "9", "p", "1"]
+func tokens(v string) []string {
+	var out []string
+	var cur strings.Builder
+	digit := false
+	flush := func() {
+		if cur.Len() > 0 {
+			out = append(out, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(v) {
+		isDigit := r >= '0' && r <= '9'
+		isAlpha := r >= 'a' && r <= 'z'
+		if !isDigit && !isAlpha {
+			flush()
+			continue
+		}
+		if cur.Len() > 0 && isDigit != digit {
+			flush()
+		}
+		digit = isDigit
+		cur.WriteRune(r)
+	}
+	flush()
+	return out
+}
+
+// numeros por valor, texto por orden lexico, un numero es mayor que un texto
+// un pre-release es menor que cualquier otro segmento
+func compareToken(a, b string) int {
+	pa, pb := preRelease(a), preRelease(b)
+	switch {
+	case pa > 0 && pb > 0:
+		return compareInt(pa, pb)
+	case pa > 0:
+		return -1
+	case pb > 0:
+		return 1
+	}
+
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInt(na, nb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package vuln
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"2.4.10", "2.4.9", 1},
+		{"2.4.9", "2.4.10", -1},
+		{"9.3p2", "9.3p1", 1},
+		{"9.3p1", "9.3", 1},
+		{"1.0.2k", "1.0.2", 1},
+		{"1.0.2k", "1.0.2l", -1},
+		{"1.0.0", "1.0", 0},
+		{"1.0", "1.0.0.0", 0},
+		{"1.0.1", "1.0", 1},
+
+		//pre-releases antes de la version final
+		{"2.4.10-rc1", "2.4.10", -1},
+		{"2.4.10", "2.4.10-rc1", 1},
+		{"2.4.10rc1", "2.4.10", -1},
+		{"2.4.10-rc1", "2.4.9", 1},
+		{"1.0.0-rc1", "1.0", -1},
+		{"1.0-dev", "1.0-alpha", -1},
+		{"1.0-alpha", "1.0-beta", -1},
+		{"1.0-beta2", "1.0-pre1", -1},
+		{"1.0-pre1", "1.0-rc1", -1},
+		{"1.0-rc1", "1.0-rc2", -1},
+		{"1.0-rc", "1.0-rc1", -1},
+		{"2.4.10-rc1", "2.4.10p1", -1},
+		{"2.4.10-RC1", "2.4.10-rc1", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseCPE(t *testing.T) {
+	tests := []struct {
+		in   string
+		want CPE
+		ok   bool
+	}{
+		{"cpe:/a:openbsd:openssh:8.9p1", CPE{Part: "a", Vendor: "openbsd", Product: "openssh", Version: "8.9p1"}, true},
+		{"cpe:/a:redislabs:redis", CPE{Part: "a", Vendor: "redislabs", Product: "redis"}, true},
+		{"cpe:2.3:a:apache:http_server:2.4.57:*:*:*:*:*:*:*", CPE{Part: "a", Vendor: "apache", Product: "http_server", Version: "2.4.57"}, true},
+		{"cpe:2.3:o:cisco:ios:15.2\\(4\\)m:*:*:*:*:*:*:*", CPE{Part: "o", Vendor: "cisco", Product: "ios", Version: "15.2(4)m"}, true},
+		{"cpe:2.3:a:vendor:prod\\:uct:1.0", CPE{Part: "a", Vendor: "vendor", Product: "prod:uct", Version: "1.0"}, true},
+		{"CPE:/A:Microsoft:IIS:10.0", CPE{Part: "a", Vendor: "microsoft", Product: "iis", Version: "10.0"}, true},
+		{"openssh 8.9", CPE{}, false},
+		{"", CPE{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseCPE(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseCPE(%q) = %+v, %v, want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
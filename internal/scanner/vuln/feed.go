@@ -0,0 +1,157 @@
+package vuln
+
+//FEED DE VULNERABILIDADES -> CVEs por rango de versiones de un CPE, cargados de un JSON local (sin red)
+import (
+	"encoding/json"
+	"fmt"
+	"go-scanner/internal/scanner/service"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// formato del feed (nombres de los rangos como en la API de NVD)
+//
+//	{"vulnerabilities": [{
+//	  "id": "CVE-2023-38408", "cvss": 9.8, "summary": "...",
+//	  "affected": [{"cpe": "cpe:2.3:a:openbsd:openssh:*", "versionEndExcluding": "9.3p2"}]
+//	}]}
+type feed struct {
+	Vulnerabilities []feedVuln `json:"vulnerabilities"`
+}
+
+type feedVuln struct {
+	ID       string         `json:"id"`
+	CVSS     float64        `json:"cvss"`
+	Severity string         `json:"severity"` //opcional, si no se deriva del CVSS
+	Summary  string         `json:"summary"`
+	Affected []feedAffected `json:"affected"`
+}
+
+// CPE afectado: version exacta en el CPE, o "*" con un rango (sin rango = todas)
+type feedAffected struct {
+	CPE                   string `json:"cpe"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// base de vulnerabilidades indexada por vendor:product
+type DB struct {
+	Count   int //vulnerabilidades cargadas
+	entries map[string][]entry
+}
+
+// un CPE afectado de una vulnerabilidad
+type entry struct {
+	vuln    *service.Vulnerability
+	version string //version exacta ("" = rango)
+	start   string
+	end     string
+	startEq bool //start incluido
+	endEq   bool //end incluido
+}
+
+// decodifica y valida un feed
+func Parse(r io.Reader) (*DB, error) {
+	var f feed
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+
+	db := &DB{entries: make(map[string][]entry)}
+	for i, fv := range f.Vulnerabilities {
+		if fv.ID == "" {
+			return nil, fmt.Errorf("vulnerability %d: missing id", i)
+		}
+		if fv.CVSS < 0 || fv.CVSS > 10 {
+			return nil, fmt.Errorf("%s: invalid cvss %v", fv.ID, fv.CVSS)
+		}
+		v := &service.Vulnerability{
+			ID:       fv.ID,
+			CVSS:     fv.CVSS,
+			Severity: service.SeverityFromCVSS(fv.CVSS),
+			Summary:  strings.TrimSpace(fv.Summary),
+		}
+		if fv.Severity != "" {
+			sev, ok := service.ParseSeverity(fv.Severity)
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown severity %q", fv.ID, fv.Severity)
+			}
+			v.Severity = sev
+		}
+
+		for _, a := range fv.Affected {
+			c, ok := ParseCPE(a.CPE)
+			if !ok || c.Vendor == "" || c.Product == "" {
+				return nil, fmt.Errorf("%s: invalid cpe %q", fv.ID, a.CPE)
+			}
+			if a.VersionStartIncluding != "" && a.VersionStartExcluding != "" {
+				return nil, fmt.Errorf("%s: both versionStartIncluding and versionStartExcluding", fv.ID)
+			}
+			if a.VersionEndIncluding != "" && a.VersionEndExcluding != "" {
+				return nil, fmt.Errorf("%s: both versionEndIncluding and versionEndExcluding", fv.ID)
+			}
+
+			e := entry{
+				vuln:    v,
+				start:   a.VersionStartIncluding + a.VersionStartExcluding,
+				startEq: a.VersionStartIncluding != "",
+				end:     a.VersionEndIncluding + a.VersionEndExcluding,
+				endEq:   a.VersionEndIncluding != "",
+			}
+			//"*" y "-" (no aplica) dejan la version al rango
+			if c.Version != "*" && c.Version != "-" && c.Version != "" {
+				e.version = c.Version
+			}
+			key := c.Key()
+			db.entries[key] = append(db.entries[key], e)
+		}
+		db.Count++
+	}
+	return db, nil
+}
+
+// feed ya cargado y el estado del archivo al leerlo
+type cached struct {
+	db      *DB
+	modTime time.Time
+	size    int64
+}
+
+// feeds ya cargados por ruta, se comparten entre escaneos
+// se vuelven a leer si el archivo cambio (el servidor web corre indefinidamente)
+var (
+	loadedMu sync.Mutex
+	loaded   = make(map[string]cached)
+)
+
+// carga el feed del archivo
+func Load(path string) (*DB, error) {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vulnerability feed: %w", err)
+	}
+	if c, ok := loaded[path]; ok && c.modTime.Equal(stat.ModTime()) && c.size == stat.Size() {
+		return c.db, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vulnerability feed: %w", err)
+	}
+	defer f.Close()
+
+	db, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	loaded[path] = cached{db: db, modTime: stat.ModTime(), size: stat.Size()}
+	return db, nil
+}
@@ -0,0 +1,61 @@
+package vuln
+
+//MATCH -> CPEs del servicio contra el feed
+import "go-scanner/internal/scanner/service"
+
+// vulnerabilidades que afectan al servicio, de mayor a menor severidad
+// sin version conocida no se reporta nada (no se puede saber si esta afectado)
+func (db *DB) Match(info *service.ServiceInfo) []service.Vulnerability {
+	if db == nil || info == nil {
+		return nil
+	}
+
+	var out []service.Vulnerability
+	seen := make(map[string]bool)
+	for _, raw := range info.CPE {
+		c, ok := ParseCPE(raw)
+		if !ok {
+			continue
+		}
+		version := c.Version
+		//CPE de aplicacion sin version: la del mismo servicio
+		if (version == "" || version == "*") && c.Part == "a" {
+			version = info.Version
+		}
+		if version == "" || version == "*" || version == "-" {
+			continue
+		}
+
+		for _, e := range db.entries[c.Key()] {
+			if seen[e.vuln.ID] || !e.affects(version) {
+				continue
+			}
+			seen[e.vuln.ID] = true
+			v := *e.vuln
+			v.CPE = raw
+			out = append(out, v)
+		}
+	}
+	service.SortVulnerabilities(out)
+	return out
+}
+
+// la version esta dentro de lo afectado
+func (e entry) affects(version string) bool {
+	if e.version != "" {
+		return CompareVersions(version, e.version) == 0
+	}
+	if e.start != "" {
+		c := CompareVersions(version, e.start)
+		if c < 0 || (c == 0 && !e.startEq) {
+			return false
+		}
+	}
+	if e.end != "" {
+		c := CompareVersions(version, e.end)
+		if c > 0 || (c == 0 && !e.endEq) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package vuln
+
+import (
+	"go-scanner/internal/scanner/service"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFeed = `{"vulnerabilities": [
+	{"id": "CVE-0000-0001", "cvss": 9.8, "affected": [{"cpe": "cpe:2.3:a:apache:http_server:*", "versionEndExcluding": "2.4.10"}]},
+	{"id": "CVE-0000-0002", "cvss": 5.3, "affected": [{"cpe": "cpe:2.3:a:apache:http_server:*", "versionStartIncluding": "2.4.10", "versionEndIncluding": "2.4.12"}]},
+	{"id": "CVE-0000-0003", "cvss": 7.5, "affected": [{"cpe": "cpe:/a:openbsd:openssh:8.9p1"}]},
+	{"id": "CVE-0000-0004", "cvss": 3.1, "severity": "high", "affected": [{"cpe": "cpe:/a:openbsd:openssh", "versionStartExcluding": "9.0", "versionEndExcluding": "9.3p2"}]}
+]}`
+
+func TestEntryAffects(t *testing.T) {
+	tests := []struct {
+		name    string
+		e       entry
+		version string
+		want    bool
+	}{
+		{"exact", entry{version: "8.9p1"}, "8.9p1", true},
+		{"exact other", entry{version: "8.9p1"}, "8.9p2", false},
+		{"no range", entry{}, "1.0", true},
+		{"end excluded below", entry{end: "2.4.10"}, "2.4.9", true},
+		{"end excluded equal", entry{end: "2.4.10"}, "2.4.10", false},
+		{"end excluded pre-release", entry{end: "2.4.10"}, "2.4.10-rc1", true},
+		{"end included equal", entry{end: "2.4.12", endEq: true}, "2.4.12", true},
+		{"end included above", entry{end: "2.4.12", endEq: true}, "2.4.13", false},
+		{"start included equal", entry{start: "2.4.10", startEq: true}, "2.4.10", true},
+		{"start included pre-release", entry{start: "2.4.10", startEq: true}, "2.4.10-rc1", false},
+		{"start excluded equal", entry{start: "9.0"}, "9.0", false},
+		{"start excluded above", entry{start: "9.0"}, "9.0p1", true},
+		{"range inside", entry{start: "9.0", end: "9.3p2"}, "9.3p1", true},
+		{"range end", entry{start: "9.0", end: "9.3p2"}, "9.3p2", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.e.affects(tt.version); got != tt.want {
+			t.Errorf("%s: affects(%q) = %v, want %v", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	db, err := Parse(strings.NewReader(testFeed))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if db.Count != 4 {
+		t.Fatalf("Count = %d, want 4", db.Count)
+	}
+
+	tests := []struct {
+		name string
+		info service.ServiceInfo
+		want []string
+	}{
+		{"release before fix", service.ServiceInfo{CPE: []string{"cpe:/a:apache:http_server:2.4.9"}}, []string{"CVE-0000-0001"}},
+		{"pre-release of fixed version", service.ServiceInfo{CPE: []string{"cpe:/a:apache:http_server:2.4.10-rc1"}}, []string{"CVE-0000-0001"}},
+		{"fixed release", service.ServiceInfo{CPE: []string{"cpe:/a:apache:http_server:2.4.10"}}, []string{"CVE-0000-0002"}},
+		{"version from service", service.ServiceInfo{Version: "8.9p1", CPE: []string{"cpe:/a:openbsd:openssh"}}, []string{"CVE-0000-0003"}},
+		{"severity from feed", service.ServiceInfo{CPE: []string{"cpe:/a:openbsd:openssh:9.1"}}, []string{"CVE-0000-0004"}},
+		{"no version", service.ServiceInfo{CPE: []string{"cpe:/a:apache:http_server"}}, nil},
+		{"other product", service.ServiceInfo{CPE: []string{"cpe:/a:nginx:nginx:1.0"}}, nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, v := range db.Match(&tt.info) {
+			got = append(got, v.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		`{"vulnerabilities": [{"cvss": 5}]}`,
+		`{"vulnerabilities": [{"id": "X", "cvss": 11}]}`,
+		`{"vulnerabilities": [{"id": "X", "severity": "urgent"}]}`,
+		`{"vulnerabilities": [{"id": "X", "affected": [{"cpe": "openssh"}]}]}`,
+		`{"vulnerabilities": [{"id": "X", "affected": [{"cpe": "cpe:/a:a:b", "versionEndIncluding": "1", "versionEndExcluding": "2"}]}]}`,
+		`{"vulnerabilities": [`,
+	}
+
+	for _, feed := range tests {
+		if _, err := Parse(strings.NewReader(feed)); err == nil {
+			t.Errorf("Parse(%s) succeeded, want error", feed)
+		}
+	}
+}
+
+func TestLoadReloadsChangedFeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.json")
+	if err := os.WriteFile(path, []byte(testFeed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := Load(path)
+	if err != nil || first.Count != 4 {
+		t.Fatalf("Load = %+v, %v", first, err)
+	}
+	if again, _ := Load(path); again != first {
+		t.Error("unchanged feed was parsed again")
+	}
+
+	//feed actualizado con el servidor corriendo
+	updated := `{"vulnerabilities": [{"id": "CVE-0000-0005", "cvss": 5.0, "affected": [{"cpe": "cpe:/a:nginx:nginx:1.0"}]}]}`
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+
+	db, err := Load(path)
+	if err != nil || db.Count != 1 {
+		t.Errorf("Load after update = %+v, %v, want 1 entry", db, err)
+	}
+
+	os.Remove(path)
+	if _, err := Load(path); err == nil {
+		t.Error("Load of removed feed succeeded, want error")
+	}
+}
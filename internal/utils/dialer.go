@@ -0,0 +1,55 @@
+package utils
+
+//DIALER -> conexiones salientes de la campaña, compartido por scanners, banners, version y probers
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+// abre conexiones hacia los targets (directo, desde una IP de origen, ...)
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// dialer directo que sale desde una IP local fija (nil = la que elija el sistema)
+type SourceDialer struct {
+	Source net.IP
+}
+
+// nuevo dialer con la IP de origen dada ("" = la que elija el sistema)
+func NewSourceDialer(source string) (*SourceDialer, error) {
+	if source == "" {
+		return &SourceDialer{}, nil
+	}
+	ip := net.ParseIP(source)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: source}
+	}
+	return &SourceDialer{Source: ip}, nil
+}
+
+// la direccion local depende de la red ("tcp*" o "udp*")
+func (d *SourceDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	if d.Source != nil {
+		switch {
+		case strings.HasPrefix(network, "tcp"):
+			dialer.LocalAddr = &net.TCPAddr{IP: d.Source}
+		case strings.HasPrefix(network, "udp"):
+			dialer.LocalAddr = &net.UDPAddr{IP: d.Source}
+		}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// conexion con timeout usando el dialer de la campaña (nil = net.DialTimeout)
+func DialTimeout(d Dialer, network, address string, timeout time.Duration) (net.Conn, error) {
+	if d == nil {
+		return net.DialTimeout(network, address, timeout)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return d.DialContext(ctx, network, address)
+}
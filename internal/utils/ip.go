@@ -38,6 +38,11 @@ func ParseTarget(target string) ([]string, error) {
 	return nil, fmt.Errorf("could not resolve target: %s", target)
 }
 
+// el target es un nombre (no IP, CIDR ni rango), mismo criterio que ParseTarget
+func IsHostname(target string) bool {
+	return !strings.Contains(target, "/") && !strings.Contains(target, "-") && net.ParseIP(target) == nil
+}
+
 // parseCIDR toma una CIDR y devuelve una lista de IPs
 func parseCIDR(cidr string) ([]string, error) {
 	ip, ipnet, err := net.ParseCIDR(cidr)
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// define el orden pseudo-aleatorio (reproducible) de puertos y hosts
+type Permutation struct {
+	Enabled bool  //si es false se respeta el orden original
+	Seed    int64 //semilla, misma semilla -> mismo orden
+}
+
+// semilla distinta para hosts, asi no comparten el mismo orden que los puertos
+const hostSeedSalt = 0x5bd1e995
+
+// nueva permutacion, semilla 0 genera una a partir del reloj
+func NewPermutation(enabled bool, seed int64) Permutation {
+	if enabled && seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return Permutation{Enabled: enabled, Seed: seed}
+}
+
+// retorna una copia de los puertos en el orden de la permutacion
+func (p Permutation) Ports(ports []int) []int {
+	return permute(ports, uint64(p.Seed), p.Enabled)
+}
+
+// retorna una copia de los hosts en el orden de la permutacion
+func (p Permutation) Hosts(hosts []string) []string {
+	return permute(hosts, uint64(p.Seed)^hostSeedSalt, p.Enabled)
+}
+
+// Fisher-Yates determinista sobre una copia del slice
+func permute[T any](items []T, seed uint64, enabled bool) []T {
+	out := make([]T, len(items))
+	copy(out, items)
+
+	if !enabled || len(out) < 2 {
+		return out
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed>>32|seed<<32))
+	for i := len(out) - 1; i > 0; i-- {
+		j := rng.IntN(i + 1)
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
@@ -2,59 +2,170 @@ package utils
 
 import (
 	"fmt"
+	"go-scanner/internal/scanner/portdb"
 	"strconv"
 	"strings"
 )
 
+// puertos a escanear separados por protocolo
+type PortSpec struct {
+	TCP []int
+	UDP []int
+}
+
+// retorna los puertos de un protocolo ("tcp" o "udp")
+func (s PortSpec) For(proto string) []int {
+	if strings.EqualFold(proto, portdb.UDP) {
+		return s.UDP
+	}
+	return s.TCP
+}
+
+// true si no hay puertos para ningun protocolo
+func (s PortSpec) Empty() bool {
+	return len(s.TCP) == 0 && len(s.UDP) == 0
+}
+
 // parsea un string de puertos a escanear
 func ParsePortRange(portStr string) ([]int, error) {
-	var ports []int //slice de puertos validos
-	parts := strings.Split(portStr, ",")
+	spec, err := ParsePortSpec(portStr, portdb.TCP)
+	if err != nil {
+		return nil, err
+	}
+	return spec.TCP, nil
+}
 
-	for _, part := range parts {
-		if strings.Contains(part, "-") {
-			//manejo de rangos
-			rangeParts := strings.Split(part, "-")
+// parsea una especificacion de puertos con soporte de:
+//   - numeros, rangos y listas: 80, 1-1024, 22,80,443
+//   - rangos abiertos: "-" (1-65535), "1024-", "-1024"
+//   - nombres de servicio: http,ssh,3306
+//   - prefijos de protocolo: T:80,U:53 (el prefijo aplica hasta el siguiente)
+//
+// los puertos sin prefijo se asignan a defaultProto
+func ParsePortSpec(spec string, defaultProto string) (PortSpec, error) {
+	var out PortSpec
+	seen := make(map[string]map[int]bool)
+	proto := strings.ToLower(defaultProto)
 
-			if len(rangeParts) != 2 {
-				return nil, fmt.Errorf("invalid range format: %s", part)
-			}
+	add := func(p string, port int) {
+		if !isValidPort(port) {
+			return
+		}
+		if seen[p] == nil {
+			seen[p] = make(map[int]bool)
+		}
+		if seen[p][port] {
+			return
+		}
+		seen[p][port] = true
+		if p == portdb.UDP {
+			out.UDP = append(out.UDP, port)
+		} else {
+			out.TCP = append(out.TCP, port)
+		}
+	}
 
-			start, err := strconv.Atoi(rangeParts[0])
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
 
-			if err != nil {
-				return nil, fmt.Errorf("invalid initial port: %s", rangeParts[0])
+		//prefijo de protocolo
+		if len(part) >= 2 && part[1] == ':' {
+			switch strings.ToUpper(part[:1]) {
+			case "T":
+				proto = portdb.TCP
+			case "U":
+				proto = portdb.UDP
+			default:
+				return PortSpec{}, fmt.Errorf("invalid protocol prefix: %s", part)
 			}
+			part = part[2:]
+		}
 
-			end, err := strconv.Atoi(rangeParts[1])
+		if part == "" {
+			continue
+		}
 
-			if err != nil {
-				return nil, fmt.Errorf("invalid final port: %s", rangeParts[1])
-			}
+		ports, err := parsePortItem(part, proto)
+		if err != nil {
+			return PortSpec{}, err
+		}
+		for _, port := range ports {
+			add(proto, port)
+		}
+	}
+	return out, nil
+}
 
-			if start > end {
-				return nil, fmt.Errorf("initial port is greater than final port: %s", part)
-			}
+// parsea un unico elemento (puerto, rango o nombre de servicio)
+func parsePortItem(part string, proto string) ([]int, error) {
+	//nombre de servicio
+	if !isNumericSpec(part) {
+		ports := portdb.Ports(part, proto)
+		if len(ports) == 0 {
+			return nil, fmt.Errorf("unknown service name for %s: %s", proto, part)
+		}
+		return ports, nil
+	}
 
-			for i := start; i <= end; i++ {
-				if isValidPort(i) {
-					ports = append(ports, i)
-				}
-			}
-		} else {
-			//manejo de puertos individuales
-			port, err := strconv.Atoi(part)
-			if err != nil {
-				return nil, fmt.Errorf("invalid port: %s", part)
-			}
-			if isValidPort(port) {
-				ports = append(ports, port)
-			}
+	if !strings.Contains(part, "-") {
+		//manejo de puertos individuales
+		port, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", part)
+		}
+		return []int{port}, nil
+	}
+
+	//manejo de rangos
+	rangeParts := strings.Split(part, "-")
+
+	if len(rangeParts) != 2 {
+		return nil, fmt.Errorf("invalid range format: %s", part)
+	}
+
+	//extremos vacios -> rango abierto
+	start, end := 1, 65535
+	var err error
+
+	if rangeParts[0] != "" {
+		start, err = strconv.Atoi(rangeParts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid initial port: %s", rangeParts[0])
 		}
 	}
+
+	if rangeParts[1] != "" {
+		end, err = strconv.Atoi(rangeParts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid final port: %s", rangeParts[1])
+		}
+	}
+
+	//extremos validados antes de reservar el rango
+	if !isValidPort(start) || !isValidPort(end) {
+		return nil, fmt.Errorf("port out of range (1-65535): %s", part)
+	}
+	if start > end {
+		return nil, fmt.Errorf("initial port is greater than final port: %s", part)
+	}
+
+	ports := make([]int, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		ports = append(ports, i)
+	}
 	return ports, nil
 }
 
+// true si el elemento solo contiene digitos y guiones
+func isNumericSpec(part string) bool {
+	for _, c := range part {
+		if (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
 // verifica si el numero del puerto es valido
 func isValidPort(port int) bool {
 	return port > 0 && port <= 65535
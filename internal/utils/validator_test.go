@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePortSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		tcp, udp []int
+	}{
+		{"80", []int{80}, nil},
+		{"22,80,443,80", []int{22, 80, 443}, nil},
+		{"1-3", []int{1, 2, 3}, nil},
+		{"65533-", []int{65533, 65534, 65535}, nil},
+		{"-2", []int{1, 2}, nil},
+		{"ssh", []int{22}, nil},
+		{"T:80,U:53,161,T:443", []int{80, 443}, []int{53, 161}},
+		{"0,80,70000", []int{80}, nil},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParsePortSpec(tt.spec, "tcp")
+		if err != nil {
+			t.Errorf("ParsePortSpec(%q): %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(spec.TCP, tt.tcp) || !reflect.DeepEqual(spec.UDP, tt.udp) {
+			t.Errorf("ParsePortSpec(%q) = tcp %v udp %v, want tcp %v udp %v", tt.spec, spec.TCP, spec.UDP, tt.tcp, tt.udp)
+		}
+	}
+
+	if spec, err := ParsePortSpec("-", "tcp"); err != nil || len(spec.TCP) != 65535 {
+		t.Errorf(`ParsePortSpec("-") = %d ports, %v`, len(spec.TCP), err)
+	}
+}
+
+func TestParsePortSpecErrors(t *testing.T) {
+	tests := []struct {
+		spec, err string
+	}{
+		{"1-4000000000", "port out of range"},
+		{"0-100", "port out of range"},
+		{"65000-65536", "port out of range"},
+		{"99999999999999999999-1", "invalid initial port"},
+		{"1-x2", "unknown service name"},
+		{"100-10", "initial port is greater than final port"},
+		{"1-2-3", "invalid range format"},
+		{"X:80", "invalid protocol prefix"},
+		{"nosuchservice", "unknown service name"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParsePortSpec(tt.spec, "tcp")
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParsePortSpec(%q) error = %v, want %q", tt.spec, err, tt.err)
+		}
+	}
+}